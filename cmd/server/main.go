@@ -2,10 +2,17 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,10 +23,13 @@ import (
 	"tg-hr-platform/internal/auth"
 	"tg-hr-platform/internal/cache"
 	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
 	"tg-hr-platform/internal/http/handlers"
 	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/payments"
 	"tg-hr-platform/internal/repo"
 	"tg-hr-platform/internal/service"
+	"tg-hr-platform/internal/telegram"
 )
 
 func main() {
@@ -57,8 +67,18 @@ func main() {
     hrUserRepo := &repo.HRUserRepo{Q: queries, Pool: pool, DefaultStatus: hrDefaultStatus}
     auditSvc := &service.AuditLogService{Q: queries}
 
-    jwtVerifier := auth.NewJWTVerifier(getenv("JWT_SECRET", "dev-secret-change-me"))
-    jwtSigner := handlers.NewJWTClaimsSigner(getenv("JWT_SECRET", "dev-secret-change-me"))
+    // JWT: JWT_KEYS="kid:secret,..." enables rotation (JWT_ACTIVE_KID signs new tokens);
+    // otherwise JWT_SECRET is the single key.
+    jwtKeys, err := auth.ParseKeySet(os.Getenv("JWT_KEYS"), os.Getenv("JWT_ACTIVE_KID"), getenv("JWT_SECRET", "dev-secret-change-me"))
+    if err != nil {
+        log.Fatal(err)
+    }
+    jwtIssuer := getenv("JWT_ISSUER", "tg-hr-platform")
+    tokenDenylist := &cache.TokenDenylist{RDB: rdb}
+    jwtVerifier := auth.NewJWTVerifier(jwtKeys, jwtIssuer)
+    jwtVerifier.SetRevocations(tokenDenylist)
+    jwtSigner := handlers.NewJWTClaimsSigner(jwtKeys, jwtIssuer,
+        getDuration("JWT_ACCESS_TTL", time.Hour), getDuration("JWT_REFRESH_TTL", 30*24*time.Hour))
     telegramVerifier := auth.NewTelegramVerifier(getenv("TELEGRAM_BOT_TOKEN", ""))
 
     // 开发模式开关（优先级高于 bot token）
@@ -70,7 +90,9 @@ func main() {
         log.Println("⚠️  WARNING: TELEGRAM_BOT_TOKEN is empty; set TELEGRAM_DEV_MODE=true for local testing")
     }
     
-    authMw := &middleware.AuthMiddleware{JWT: jwtVerifier}
+    companyStatusSvc := &service.CompanyStatusService{Q: queries, Cache: &cache.CompanyStatusCache{RDB: rdb}}
+    hrUserStateSvc := &service.HRUserStateService{Q: queries, Cache: &cache.HRUserStateCache{RDB: rdb}}
+    authMw := &middleware.AuthMiddleware{JWT: jwtVerifier, Companies: companyStatusSvc, Users: hrUserStateSvc}
 
     r := gin.New()
     r.Use(gin.Recovery())
@@ -86,7 +108,7 @@ func main() {
             c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
             c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
             c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Cookie")
-            c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+            c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
         }
 
         if c.Request.Method == "OPTIONS" {
@@ -101,44 +123,303 @@ func main() {
         c.JSON(http.StatusOK, gin.H{"ok": true, "ts": time.Now().UTC()})
     })
 
-    // Telegram Bot webhook
+    // SIGINT/SIGTERM stop the bot poller and drain in-flight HTTP requests
+    runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    var background sync.WaitGroup
+
+    // Starts new quota periods once period_end is reached
+    rollover := &service.QuotaRolloverWorker{Repo: &repo.QuotaRepo{Q: queries, Pool: pool}}
+    background.Add(1)
+    go func() {
+        defer background.Done()
+        rollover.Run(runCtx)
+    }()
+
+    // Credit purchases; providers are registered below as they are configured
+    paymentSvc := &service.PaymentService{Q: queries, Repo: &repo.PaymentRepo{Q: queries, Pool: pool}, Audit: auditSvc}
+
+    // Telegram Bot: webhook (default) or long polling (BOT_MODE=polling, no public URL needed)
     botToken := getenv("TELEGRAM_BOT_TOKEN", "")
     webAppURL := getenv("BOT_WEBAPP_URL", "http://localhost:3000")
     webhookSecret := getenv("TELEGRAM_WEBHOOK_SECRET", "")
+    botMode := strings.ToLower(getenv("BOT_MODE", "webhook"))
     if botToken != "" {
+        // Webhook updates accept contact requests, delete profiles and credit payments on the
+        // sender's word, so they must be authenticated by the secret token
+        if botMode == "webhook" && webhookSecret == "" {
+            log.Fatal("TELEGRAM_WEBHOOK_SECRET is required with BOT_MODE=webhook")
+        }
+        botClient := telegram.NewClient(botToken)
         botHandler := handlers.NewBotHandler(botToken, webAppURL, webhookSecret)
-        r.POST("/bot/webhook", botHandler.HandleWebhook)
-        log.Printf("✅ Bot webhook registered at POST /bot/webhook")
+        botHandler.SetClient(botClient)
+        botHandler.SetOnboarding(&service.OnboardingService{Q: queries, Repo: candRepo, Cache: candCache})
+        botHandler.SetCandidateProfiles(&service.CandidateProfileService{Q: queries, Repo: candRepo, Cache: candCache, Audit: auditSvc})
+        botHandler.SetContactRequests(&service.ContactRequestService{Q: queries, Repo: candRepo, Audit: auditSvc})
+        botHandler.SetSavedSearches(&service.SavedSearchService{Q: queries})
+        // Invoices in Telegram Stars, or in fiat through a Telegram Payments provider token
+        if strings.EqualFold(getenv("PAYMENTS_TELEGRAM", ""), "true") || getenv("PAYMENTS_TELEGRAM", "") == "1" {
+            paymentSvc.Register(payments.NewTelegramProvider(botClient, getenv("TELEGRAM_PAYMENTS_PROVIDER_TOKEN", "")))
+        }
+        botHandler.SetPayments(paymentSvc)
+
+        switch botMode {
+        case "polling":
+            go registerBot(botClient, "", "")
+            poller := telegram.NewPoller(botClient, botHandler.HandlePolledUpdate)
+            background.Add(1)
+            go func() {
+                defer background.Done()
+                if err := poller.Run(runCtx); err != nil {
+                    log.Printf("❌ Bot polling failed: %v", err)
+                }
+            }()
+            log.Println("✅ Bot running in polling mode (webhook disabled)")
+        case "webhook":
+            go registerBot(botClient, getenv("TELEGRAM_WEBHOOK_URL", ""), webhookSecret)
+            r.POST("/bot/webhook", botHandler.HandleWebhook)
+            log.Printf("✅ Bot webhook registered at POST /bot/webhook")
+        default:
+            log.Fatalf("invalid BOT_MODE %q (want webhook or polling)", botMode)
+        }
         log.Printf("📍 WebApp URL: %s", webAppURL)
+
+        // Delivers queued bot messages (e.g. unlock notifications) in both bot modes
+        outbox := &service.BotOutboxWorker{Q: queries, Client: botClient}
+        background.Add(1)
+        go func() {
+            defer background.Done()
+            outbox.Run(runCtx)
+        }()
+
+        // Queues saved search digests for the outbox above
+        digests := &service.SavedSearchDigestWorker{Q: queries, Candidates: candSvc, WebAppURL: webAppURL}
+        background.Add(1)
+        go func() {
+            defer background.Done()
+            digests.Run(runCtx)
+        }()
+    } else {
+        log.Println("⚠️  TELEGRAM_BOT_TOKEN is empty, bot is disabled")
+    }
+
+    // Payment providers that confirm payments with signed webhooks
+    paymentH := &handlers.PaymentHandler{Svc: paymentSvc, Audit: auditSvc}
+    if cardAPIURL := getenv("PAYMENTS_CARD_API_URL", ""); cardAPIURL != "" {
+        cardSecret := getenv("PAYMENTS_CARD_WEBHOOK_SECRET", "")
+        if cardSecret == "" {
+            log.Fatal("PAYMENTS_CARD_WEBHOOK_SECRET is required with PAYMENTS_CARD_API_URL")
+        }
+        paymentSvc.Register(payments.NewCardProvider(cardAPIURL, getenv("PAYMENTS_CARD_API_KEY", ""), cardSecret,
+            getenv("PAYMENTS_RETURN_URL", webAppURL)))
+    }
+    if strings.EqualFold(getenv("PAYMENTS_FAKE", ""), "true") || getenv("PAYMENTS_FAKE", "") == "1" {
+        // development only: opening a fake checkout link pays it (?result=failed declines)
+        paymentH.Fake = payments.NewFakeProvider(randomSecret(), getenv("PAYMENTS_FAKE_CHECKOUT_URL", "http://localhost:8080/payments/fake"))
+        paymentSvc.Register(paymentH.Fake)
+        r.GET("/payments/fake/:checkout_id", paymentH.FakePay)
+        r.POST("/payments/fake/:checkout_id", paymentH.FakePay)
+        log.Println("⚠️  WARNING: fake payment provider is enabled (PAYMENTS_FAKE=true); credits can be bought without paying")
+    }
+    r.POST("/payments/webhook/:provider", paymentH.Webhook)
+    if providers := paymentSvc.Providers(); len(providers) > 0 {
+        log.Printf("✅ Payment providers enabled: %s", strings.Join(providers, ", "))
     } else {
-        log.Println("⚠️  TELEGRAM_BOT_TOKEN is empty, bot webhook is disabled")
+        log.Println("⚠️  No payment provider configured, credits can only be topped up by platform admins")
     }
 
     // Public auth endpoints
     cookieSecure := strings.EqualFold(getenv("COOKIE_SECURE", "false"), "true") || getenv("COOKIE_SECURE", "") == "1"
         authHandler := handlers.NewAuthHandler(telegramVerifier, jwtSigner, hrUserRepo, cookieSecure)
+    authHandler.SetCompanyStatusLookup(companyStatusSvc)
+    authHandler.SetSessionStore(jwtVerifier, tokenDenylist)
     r.POST("/auth/telegram/login", authHandler.TelegramLogin)
+    r.POST("/auth/telegram/webapp", authHandler.TelegramWebAppLogin)
+    r.POST("/auth/refresh", authHandler.Refresh)
+    r.POST("/auth/logout", authHandler.Logout)
+    authHandler.SetUserSessionRevoker(hrUserStateSvc)
+    r.POST("/auth/logout-all", authMw.Auth(), authHandler.LogoutAll)
 
     // Protected API endpoints
     api := r.Group("/api")
     api.Use(authMw.Auth(), authMw.AuthActiveHR())
     
+        planSvc := &service.PlanService{Q: queries, Repo: &repo.PlanRepo{Q: queries, Pool: pool}}
         accountH := handlers.NewAccountHandler(queries)
+        accountH.Plans = planSvc
         api.GET("/me", accountH.GetMe)
+        quotaH := &handlers.QuotaHandler{Svc: &service.QuotaService{Q: queries}}
+        api.GET("/quota/history", quotaH.History)
+        creditRepo := &repo.CreditRepo{Q: queries, Pool: pool}
+        creditSvc := &service.CreditService{Q: queries, Repo: creditRepo}
+        creditH := &handlers.CreditHandler{Svc: creditSvc}
+        api.GET("/credits", creditH.Balance)
+        api.GET("/credits/ledger", middleware.RequirePermission(domain.PermAuditRead), creditH.Ledger)
+        api.GET("/payments/packs", paymentH.Packs)
+        purchases := api.Group("/payments", middleware.RequirePermission(domain.PermQuotaManage))
+        purchases.POST("", paymentH.Checkout)
+        purchases.GET("", paymentH.List)
+        purchases.GET("/:id", paymentH.Get)
 
+    // Role-based access: see domain.rolePermissions for the owner/admin/recruiter matrix
     candH := &handlers.CandidateHandler{Svc: candSvc, Audit: auditSvc}
-    api.GET("/candidates", candH.List)
-    api.GET("/candidates/:slug", candH.Get)
-    api.POST("/candidates/:slug/unlock", candH.Unlock)
+    api.GET("/candidates", middleware.RequirePermission(domain.PermCandidatesRead), candH.List)
+    api.GET("/candidates/:slug", middleware.RequirePermission(domain.PermCandidatesRead), candH.Get)
+    api.POST("/candidates/:slug/unlock", middleware.RequirePermission(domain.PermCandidatesUnlock), candH.Unlock)
+    contactReportSvc := &service.ContactReportService{Repo: &repo.ContactReportRepo{Q: queries, Pool: pool}, Candidates: candRepo}
+    contactReportH := &handlers.ContactReportHandler{Svc: contactReportSvc, Audit: auditSvc}
+    api.POST("/candidates/:slug/report", middleware.RequirePermission(domain.PermCandidatesUnlock), contactReportH.Report)
+
+    // Private notes and tags, visible only to the caller's company
+    noteH := &handlers.CandidateNoteHandler{Svc: &service.CandidateNoteService{Q: queries, Repo: candRepo}, Audit: auditSvc}
+    notes := api.Group("/candidates/:slug", middleware.RequirePermission(domain.PermCandidatesRead))
+    notes.GET("/notes", noteH.ListNotes)
+    notes.POST("/notes", noteH.AddNote)
+    notes.PATCH("/notes/:id", noteH.UpdateNote)
+    notes.DELETE("/notes/:id", noteH.DeleteNote)
+    notes.POST("/tags", noteH.AddTag)
+    notes.DELETE("/tags/:tag", noteH.RemoveTag)
+    api.GET("/tags", middleware.RequirePermission(domain.PermCandidatesRead), noteH.ListTags)
+
+    // Hiring pipelines: any role can view and move candidates, owners/admins set them up
+    pipelineH := &handlers.PipelineHandler{
+        Svc:   &service.PipelineService{Q: queries, Repo: &repo.PipelineRepo{Q: queries, Pool: pool}, Candidates: candRepo},
+        Audit: auditSvc,
+    }
+    pipelines := api.Group("/pipelines")
+    pipelines.GET("", middleware.RequirePermission(domain.PermCandidatesRead), pipelineH.List)
+    pipelines.POST("", middleware.RequirePermission(domain.PermPipelinesManage), pipelineH.Create)
+    pipelines.GET("/:id", middleware.RequirePermission(domain.PermCandidatesRead), pipelineH.Get)
+    pipelines.PATCH("/:id", middleware.RequirePermission(domain.PermPipelinesManage), pipelineH.Rename)
+    pipelines.DELETE("/:id", middleware.RequirePermission(domain.PermPipelinesManage), pipelineH.Delete)
+    pipelines.PUT("/:id/stages", middleware.RequirePermission(domain.PermPipelinesManage), pipelineH.ReplaceStages)
+    pipelines.PUT("/:id/candidates/:slug", middleware.RequirePermission(domain.PermCandidatesRead), pipelineH.MoveCandidate)
+    pipelines.DELETE("/:id/candidates/:slug", middleware.RequirePermission(domain.PermCandidatesRead), pipelineH.RemoveCandidate)
+
+    // Saved searches: private to the HR user, digests go to their Telegram chat
+    savedSearchSvc := &service.SavedSearchService{Q: queries, Repo: &repo.SavedSearchRepo{Q: queries, Pool: pool}}
+    savedSearchH := &handlers.SavedSearchHandler{Svc: savedSearchSvc, Audit: auditSvc}
+    savedSearches := api.Group("/saved-searches", middleware.RequirePermission(domain.PermCandidatesRead))
+    savedSearches.POST("", savedSearchH.Create)
+    savedSearches.GET("", savedSearchH.List)
+    savedSearches.PATCH("/:id", savedSearchH.Update)
+    savedSearches.DELETE("/:id", savedSearchH.Delete)
 
     auditH := &handlers.AuditLogHandler{Svc: auditSvc}
-    api.GET("/audit-logs", auditH.GetAuditLogs)
+    api.GET("/audit-logs", middleware.RequirePermission(domain.PermAuditRead), auditH.GetAuditLogs)
+
+    // Company invitations (owner only)
+    inviteSvc := &service.InviteService{Q: queries, BotUsername: strings.TrimPrefix(getenv("TELEGRAM_BOT_USERNAME", ""), "@")}
+    inviteH := &handlers.InviteHandler{Svc: inviteSvc, Audit: auditSvc}
+    invites := api.Group("/company/invites", middleware.RequirePermission(domain.PermMembersManage))
+    invites.POST("", inviteH.Create)
+    invites.GET("", inviteH.List)
+    invites.PATCH("/:id", inviteH.Update)
+    invites.DELETE("/:id", inviteH.Revoke)
+
+    // Company members (owner only)
+    memberH := &handlers.MemberHandler{Svc: &service.MemberService{Repo: hrUserRepo, States: hrUserStateSvc}, Audit: auditSvc}
+    members := api.Group("/company/members", middleware.RequirePermission(domain.PermMembersManage))
+    members.GET("", memberH.List)
+    members.POST("/:id/approve", memberH.Approve)
+    members.POST("/:id/block", memberH.Block)
+    members.PATCH("/:id/role", memberH.UpdateRole)
+    members.DELETE("/:id", memberH.Remove)
+    members.POST("/:id/revoke-sessions", memberH.RevokeSessions)
+
+    // Platform admin console (cross-tenant, for the ops team)
+    adminTgIDs := parseInt64Set(getenv("PLATFORM_ADMIN_TG_IDS", ""))
+    if len(adminTgIDs) > 0 {
+        if devMode {
+            log.Println("⚠️  WARNING: /admin is enabled while Telegram verification is DISABLED; anyone can log in as an admin")
+        }
+        adminAuthH := handlers.NewAdminAuthHandler(telegramVerifier, jwtSigner, adminTgIDs, cookieSecure)
+        r.POST("/admin/auth/telegram/login", adminAuthH.TelegramLogin)
+
+        adminMw := &middleware.AdminAuthMiddleware{JWT: jwtVerifier, Allowed: adminTgIDs}
+        adminH := &handlers.AdminHandler{Svc: &service.AdminService{Q: queries, CompanyStatus: companyStatusSvc, HRUsers: hrUserStateSvc, Credits: creditRepo, Members: hrUserRepo}, Plans: planSvc, Credits: creditSvc, ContactReports: contactReportSvc, Audit: auditSvc}
+        admin := r.Group("/admin", adminMw.Auth())
+        admin.GET("/companies", adminH.ListCompanies)
+        admin.PATCH("/companies/:id/status", adminH.UpdateCompanyStatus)
+        admin.PUT("/companies/:id/quota", adminH.UpdateCompanyQuota)
+        admin.GET("/companies/:id/plans", adminH.ListCompanyPlans)
+        admin.PUT("/companies/:id/plan", adminH.AssignCompanyPlan)
+        admin.POST("/companies/:id/credits", adminH.TopUpCompanyCredits)
+        admin.PUT("/candidates/:slug/unlock-cost", adminH.UpdateCandidateUnlockCost)
+        admin.GET("/contact-reports", adminH.ListContactReports)
+        admin.PATCH("/contact-reports/:id", adminH.ResolveContactReport)
+        admin.GET("/plans", adminH.ListPlans)
+        admin.POST("/plans", adminH.CreatePlan)
+        admin.PATCH("/plans/:id", adminH.UpdatePlan)
+        admin.GET("/hr-users", adminH.ListHRUsers)
+        admin.PATCH("/hr-users/:id/status", adminH.UpdateHRUserStatus)
+        admin.POST("/hr-users/:id/revoke-sessions", adminH.RevokeHRUserSessions)
+
+        // Read-only support view of one company, reusing the /api handlers (unaudited as HR actions)
+        imp := admin.Group("/impersonate/:company_id", middleware.Impersonate(adminH.EnterImpersonation))
+        impCandH := &handlers.CandidateHandler{Svc: candSvc}
+        imp.GET("/candidates", impCandH.List)
+        imp.GET("/candidates/:slug", impCandH.Get)
+        imp.GET("/audit-logs", auditH.GetAuditLogs)
+        imp.GET("/members", memberH.List)
+        imp.GET("/invites", inviteH.List)
+        imp.GET("/pipelines", pipelineH.List)
+        imp.GET("/pipelines/:id", pipelineH.Get)
+        log.Printf("✅ Platform admin console enabled for %d Telegram account(s)", len(adminTgIDs))
+    } else {
+        log.Println("⚠️  PLATFORM_ADMIN_TG_IDS is empty, /admin console is disabled")
+    }
 
     addr := getenv("ADDR", ":8080")
-    log.Printf("listening on %s", addr)
-    if err := r.Run(addr); err != nil {
+    srv := &http.Server{Addr: addr, Handler: r}
+    go func() {
+        log.Printf("listening on %s", addr)
+        if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+            log.Fatal(err)
+        }
+    }()
+
+    <-runCtx.Done()
+    log.Println("shutting down...")
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    defer cancel()
+    if err := srv.Shutdown(shutdownCtx); err != nil {
+        log.Printf("⚠️  HTTP shutdown: %v", err)
+    }
+    background.Wait()
+}
+
+// registerBot 启动时注册 bot 命令菜单；配置了 TELEGRAM_WEBHOOK_URL 时同时设置 webhook
+func registerBot(client *telegram.Client, webhookURL, webhookSecret string) {
+    ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+    defer cancel()
+
+    me, err := client.GetMe(ctx)
+    if err != nil {
+        log.Printf("⚠️  Telegram getMe failed (check TELEGRAM_BOT_TOKEN): %v", err)
+        return
+    }
+    log.Printf("🤖 Bot connected as @%s", me.Username)
+    if err := handlers.SetBotCommands(ctx, client); err != nil {
+        log.Printf("⚠️  setMyCommands failed: %v", err)
+    }
+    if webhookURL != "" {
+        err := client.SetWebhook(ctx, telegram.SetWebhookParams{URL: webhookURL, SecretToken: webhookSecret})
+        if err != nil {
+            log.Printf("⚠️  setWebhook failed: %v", err)
+        } else {
+            log.Printf("✅ Webhook set to %s", webhookURL)
+        }
+    }
+}
+
+// randomSecret 生成随机密钥（用于仅在本进程内签名/验证的场景）
+func randomSecret() string {
+    b := make([]byte, 32)
+    if _, err := rand.Read(b); err != nil {
         log.Fatal(err)
     }
+    return hex.EncodeToString(b)
 }
 
 func getenv(k, def string) string {
@@ -149,6 +430,38 @@ func getenv(k, def string) string {
     return v
 }
 
+// getDuration 读取时长配置（如 "1h"、"30m"），无效时使用默认值
+func getDuration(k string, def time.Duration) time.Duration {
+    v := os.Getenv(k)
+    if v == "" {
+        return def
+    }
+    d, err := time.ParseDuration(v)
+    if err != nil || d <= 0 {
+        log.Printf("⚠️  invalid %s=%q, using %s", k, v, def)
+        return def
+    }
+    return d
+}
+
+// parseInt64Set 解析逗号分隔的 ID 列表，例如：PLATFORM_ADMIN_TG_IDS=123456,789012
+func parseInt64Set(s string) map[int64]bool {
+    out := make(map[int64]bool)
+    for _, part := range strings.Split(s, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+        id, err := strconv.ParseInt(part, 10, 64)
+        if err != nil {
+            log.Printf("⚠️  ignoring invalid ID %q", part)
+            continue
+        }
+        out[id] = true
+    }
+    return out
+}
+
 // getAllowedOrigins 从环境变量获取允许的源列表
 // 支持多个源（逗号分隔），例如：ALLOWED_ORIGINS=http://localhost:3000,http://localhost:3001,https://example.com
 func getAllowedOrigins() []string {
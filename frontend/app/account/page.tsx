@@ -3,6 +3,7 @@
 import { useEffect, useState } from 'react'
 import { useRouter } from 'next/navigation'
 import Header from '@/components/Header'
+import BuyCredits from '@/components/BuyCredits'
 import { accountAPI, MeResponse } from '@/lib/api'
 import { toast } from '@/lib/toast'
 import { useAuthStore } from '@/lib/store'
@@ -178,6 +179,8 @@ export default function AccountPage() {
                 </>
               )}
             </div>
+
+            {data.user.role === 'owner' && <BuyCredits onPaid={fetchMe} />}
           </div>
         )}
       </div>
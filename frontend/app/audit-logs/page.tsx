@@ -41,6 +41,21 @@ export default function AuditLogsPage() {
       'candidate.list': '浏览候选人列表',
       'candidate.view': '查看候选人详情',
       'candidate.unlock': '解锁候选人联系方式',
+      'candidate.contact_request': '请求候选人联系方式',
+      'candidate.contact_report': '报告联系方式无效',
+      'candidate.note_create': '添加候选人备注',
+      'candidate.note_update': '修改候选人备注',
+      'candidate.note_delete': '删除候选人备注',
+      'candidate.tag_add': '添加候选人标签',
+      'candidate.tag_remove': '移除候选人标签',
+      'pipeline.candidate_move': '移动候选人招聘阶段',
+      'pipeline.candidate_remove': '移出招聘流程',
+      'payment.checkout': '发起积分购买',
+      'payment.succeeded': '积分购买成功',
+      'payment.failed': '积分购买失败',
+      'admin.credits.topup': '平台充值积分',
+      'admin.contact_report.refund': '联系方式报告已退款',
+      'admin.contact_report.reject': '联系方式报告被驳回',
     }
     return labels[action] || action
   }
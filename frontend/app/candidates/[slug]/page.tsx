@@ -4,7 +4,7 @@ import { useEffect, useState } from 'react'
 import { useRouter } from 'next/navigation'
 import Link from 'next/link'
 import { toast } from '@/lib/toast'
-import { candidateAPI, CandidateDetail } from '@/lib/api'
+import { candidateAPI, CandidateDetail, ContactReport, ContactReportReason } from '@/lib/api'
 import { useAuthStore } from '@/lib/store'
 import Header from '@/components/Header'
 import { EmptyState, LoadingState } from '@/components/State'
@@ -16,6 +16,8 @@ export default function CandidateDetailPage({ params }: { params: { slug: string
   const [loading, setLoading] = useState(false)
   const [unlocking, setUnlocking] = useState(false)
   const [showContact, setShowContact] = useState(false)
+  const [reportReason, setReportReason] = useState<ContactReportReason>('telegram_invalid')
+  const [reporting, setReporting] = useState(false)
 
   useEffect(() => {
     if (!user) {
@@ -42,7 +44,12 @@ export default function CandidateDetailPage({ params }: { params: { slug: string
   const handleUnlock = async () => {
     try {
       setUnlocking(true)
-      const contact = await candidateAPI.unlock(params.slug)
+      const { contact, contact_request } = await candidateAPI.unlock(params.slug)
+      if (contact_request) {
+        setCandidate((prev) => (prev ? { ...prev, contact_request } : null))
+        toast.success('已向候选人发送请求，对方同意后即可查看联系方式')
+        return
+      }
       setShowContact(true)
       setCandidate((prev) =>
         prev ? { ...prev, contact, unlocked_contact: true } : null
@@ -53,6 +60,13 @@ export default function CandidateDetailPage({ params }: { params: { slug: string
         router.push(`/quota?reason=exceeded&return=/candidates/${params.slug}`)
       } else if (error.response?.status === 409) {
         router.push(`/quota?reason=not_configured&return=/candidates/${params.slug}`)
+      } else if (error.response?.data?.error === 'contact_request_declined') {
+        setCandidate((prev) =>
+          prev && prev.contact_request
+            ? { ...prev, contact_request: { ...prev.contact_request, status: 'declined' } }
+            : prev
+        )
+        toast.error('候选人已拒绝公开联系方式')
       } else {
         toast.error(error.response?.data?.error || '解锁失败')
       }
@@ -61,6 +75,26 @@ export default function CandidateDetailPage({ params }: { params: { slug: string
     }
   }
 
+  const handleReport = async () => {
+    try {
+      setReporting(true)
+      const contact_report = await candidateAPI.reportContact(params.slug, reportReason)
+      setCandidate((prev) => (prev ? { ...prev, contact_report } : null))
+      toast.success('已提交，平台审核通过后会退还积分')
+    } catch (error: any) {
+      const code = error.response?.data?.error
+      if (code === 'contact_report_window_closed') {
+        toast.error('解锁已超过 30 天，无法再报告')
+      } else if (code === 'contact_report_exists') {
+        toast.error('已经报告过该联系方式')
+      } else {
+        toast.error(code || '提交失败')
+      }
+    } finally {
+      setReporting(false)
+    }
+  }
+
   if (loading) {
     return (
       <div className="min-h-screen bg-gray-50">
@@ -195,21 +229,54 @@ export default function CandidateDetailPage({ params }: { params: { slug: string
                       <p className="font-medium text-gray-900">{candidate.contact.phone}</p>
                     </div>
                   )}
+
+                  {/* 联系方式无效时可申请退还积分 */}
+                  <div className="pt-3 border-t border-gray-200">
+                    {candidate.contact_report ? (
+                      <p className="text-sm text-gray-600">{contactReportHint(candidate.contact_report)}</p>
+                    ) : (
+                      <div className="flex gap-2">
+                        <select
+                          value={reportReason}
+                          onChange={(e) => setReportReason(e.target.value as ContactReportReason)}
+                          className="input-field flex-1 text-sm"
+                        >
+                          <option value="telegram_invalid">Telegram 无效</option>
+                          <option value="phone_invalid">电话无效</option>
+                          <option value="email_invalid">邮箱无效</option>
+                          <option value="other">其他</option>
+                        </select>
+                        <button
+                          onClick={handleReport}
+                          disabled={reporting}
+                          className="btn-secondary text-sm disabled:opacity-50"
+                        >
+                          {reporting ? '提交中...' : '报告无效'}
+                        </button>
+                      </div>
+                    )}
+                  </div>
                 </div>
               ) : (
                 <div className="text-center">
                   <p className="text-gray-600 text-sm mb-4">
                     {candidate.unlocked_contact
                       ? '已解锁'
-                      : '联系方式已锁定，点击下方按钮解锁'}
+                      : contactRequestHint(candidate)}
                   </p>
-                  {!candidate.unlocked_contact && (
+                  {!candidate.unlocked_contact &&
+                    candidate.contact_request?.status !== 'pending' &&
+                    candidate.contact_request?.status !== 'declined' && (
                     <button
                       onClick={handleUnlock}
                       disabled={unlocking}
                       className="btn-primary w-full disabled:opacity-50"
                     >
-                      {unlocking ? '解锁中...' : '🔓 解锁联系方式'}
+                      {unlocking
+                        ? '解锁中...'
+                        : candidate.contact_mode === 'request'
+                          ? '📨 请求联系方式'
+                          : '🔓 解锁联系方式'}
                     </button>
                   )}
                 </div>
@@ -221,3 +288,31 @@ export default function CandidateDetailPage({ params }: { params: { slug: string
     </div>
   )
 }
+
+// 未解锁时的说明：request 模式的候选人需先同意，同意后才扣除额度
+function contactRequestHint(candidate: CandidateDetail): string {
+  const req = candidate.contact_request
+  if (req?.status === 'pending') {
+    return `已发送请求，等待候选人同意（${new Date(req.expires_at).toLocaleDateString()} 前有效）`
+  }
+  if (req?.status === 'declined') {
+    return '候选人已拒绝公开联系方式'
+  }
+  if (req?.status === 'expired') {
+    return '请求已过期，可以重新发送'
+  }
+  if (candidate.contact_mode === 'request') {
+    return '该候选人需要同意后才公开联系方式，同意后才扣除额度'
+  }
+  return '联系方式已锁定，点击下方按钮解锁'
+}
+
+function contactReportHint(report: ContactReport): string {
+  if (report.status === 'refunded') {
+    return `已确认联系方式无效，退还 ${report.refund_amount} 积分`
+  }
+  if (report.status === 'rejected') {
+    return report.review_note ? `报告未通过：${report.review_note}` : '报告未通过审核'
+  }
+  return '已报告联系方式无效，等待平台审核'
+}
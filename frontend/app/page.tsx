@@ -3,7 +3,7 @@
 import { useEffect, useRef, useState } from 'react'
 import { useRouter } from 'next/navigation'
 import { toast } from '@/lib/toast'
-import { authAPI, TelegramAuthData } from '@/lib/api'
+import { authAPI, LoginResponse, TelegramAuthData } from '@/lib/api'
 import { useAuthStore } from '@/lib/store'
 
 declare global {
@@ -23,6 +23,8 @@ declare global {
         }
         ready: () => void
         close: () => void
+        // status: paid / cancelled / failed / pending
+        openInvoice?: (url: string, callback?: (status: string) => void) => void
       }
     }
     onTelegramAuth?: (user: any) => void
@@ -44,66 +46,75 @@ export default function LoginPage() {
 
     if (webAppAvailable) {
       window.Telegram?.WebApp?.ready()
-      const initData = window.Telegram?.WebApp?.initDataUnsafe
-      if (initData?.user) {
-        handleTelegramLogin(initData)
+      const initData = window.Telegram?.WebApp?.initData
+      if (initData) {
+        handleWebAppLogin(initData)
       }
     }
   }, [])
 
+  const inviteFromURL = () => new URLSearchParams(window.location.search).get('invite') || undefined
+
+  // Mini App：直接把原始 initData 交给服务端校验（WebAppData 签名）
+  const handleWebAppLogin = async (initData: string) => {
+    try {
+      handleLoginResponse(await authAPI.telegramWebAppLogin(initData, inviteFromURL()))
+    } catch (error: any) {
+      toast.error(error.response?.data?.error || '登录失败，请重试')
+    }
+  }
+
+  // Login Widget 回调数据
   const handleTelegramLogin = async (telegramData: any) => {
     try {
-      const isWebAppData = Boolean(telegramData?.user)
-      const authData: TelegramAuthData = isWebAppData
-        ? {
-            id: telegramData.user.id,
-            first_name: telegramData.user.first_name,
-            last_name: telegramData.user.last_name || '',
-            username: telegramData.user.username || '',
-            photo_url: telegramData.user.photo_url || '',
-            auth_date: telegramData.auth_date,
-            hash: window.Telegram?.WebApp?.initData?.split('hash=')[1] || '',
-          }
-        : {
-            id: telegramData.id,
-            first_name: telegramData.first_name,
-            last_name: telegramData.last_name || '',
-            username: telegramData.username || '',
-            photo_url: telegramData.photo_url || '',
-            auth_date: telegramData.auth_date,
-            hash: telegramData.hash || '',
-          }
+      const authData: TelegramAuthData = {
+        id: telegramData.id,
+        first_name: telegramData.first_name,
+        last_name: telegramData.last_name || '',
+        username: telegramData.username || '',
+        photo_url: telegramData.photo_url || '',
+        auth_date: telegramData.auth_date,
+        hash: telegramData.hash || '',
+      }
 
-      const response = await authAPI.telegramLogin(authData)
-
-      if (response.success) {
-        // Store token and user info
-        setUser({
-          hrUserID: response.user_id,
-          companyID: 0, // Will be set from response in production
-          status: response.status as 'active' | 'pending' | 'blocked',
-          role: 'recruiter',
-        })
-
-        toast.success('登录成功！')
-        
-        if (response.status === 'pending') {
-          toast.info('你的账户待审批，请等待管理员审核')
-          router.push('/waiting-approval')
-        } else {
-          router.push('/candidates')
-        }
-      } else {
-        toast.error('登录失败')
+      const inviteCode = inviteFromURL()
+      if (inviteCode) {
+        authData.invite_code = inviteCode
       }
+
+      handleLoginResponse(await authAPI.telegramLogin(authData))
     } catch (error: any) {
       toast.error(error.response?.data?.error || '登录失败，请重试')
     }
   }
 
+  const handleLoginResponse = (response: LoginResponse) => {
+    if (response.success) {
+      // Store token and user info
+      setUser({
+        hrUserID: response.user_id,
+        companyID: 0, // Will be set from response in production
+        status: response.status as 'active' | 'pending' | 'blocked',
+        role: response.role || 'recruiter',
+      })
+
+      toast.success('登录成功！')
+      
+      if (response.status === 'pending') {
+        toast.info('你的账户待审批，请等待管理员审核')
+        router.push('/waiting-approval')
+      } else {
+        router.push('/candidates')
+      }
+    } else {
+      toast.error('登录失败')
+    }
+  }
+
   const handleLoginClick = async () => {
-    if (window.Telegram?.WebApp?.initDataUnsafe?.user) {
-      await handleTelegramLogin(window.Telegram.WebApp.initDataUnsafe)
+    const initData = window.Telegram?.WebApp?.initData
+    if (initData) {
+      await handleWebAppLogin(initData)
       return
     }
     toast.info('请在 Telegram 中打开此链接')
@@ -0,0 +1,108 @@
+/**
+ * BuyCredits 组件
+ * 企业所有者购买积分包：下单后跳转支付页面（Telegram 内直接打开发票），并轮询订单状态
+ */
+
+import { useEffect, useState } from 'react'
+import { CreditPack, PackPrice, Payment, paymentAPI } from '@/lib/api'
+import { toast } from '@/lib/toast'
+
+interface BuyCreditsProps {
+  // 支付成功后回调（刷新余额）
+  onPaid?: () => void
+}
+
+const providerLabels: Record<string, string> = {
+  telegram: 'Telegram 支付',
+  card: '银行卡',
+  fake: '模拟支付',
+}
+
+const POLL_INTERVAL_MS = 2000
+const POLL_ATTEMPTS = 60
+
+function formatPrice(price: PackPrice): string {
+  if (price.currency === 'XTR') {
+    return `${price.amount} ⭐`
+  }
+  return `${(price.amount / 100).toFixed(2)} ${price.currency}`
+}
+
+export default function BuyCredits({ onPaid }: BuyCreditsProps) {
+  const [packs, setPacks] = useState<CreditPack[]>([])
+  const [pending, setPending] = useState<Payment | null>(null)
+
+  useEffect(() => {
+    paymentAPI
+      .getPacks()
+      .then((res) => setPacks(res.items))
+      .catch(() => setPacks([]))
+  }, [])
+
+  const waitForPayment = async (payment: Payment) => {
+    for (let i = 0; i < POLL_ATTEMPTS; i++) {
+      await new Promise((resolve) => setTimeout(resolve, POLL_INTERVAL_MS))
+      const current = await paymentAPI.get(payment.id)
+      if (current.status === 'succeeded') {
+        toast.success(`支付成功，已增加 ${current.credits} 积分`)
+        onPaid?.()
+        return
+      }
+      if (current.status === 'failed') {
+        toast.error('支付失败，请重试')
+        return
+      }
+    }
+    toast.info('支付结果确认中，稍后可在积分流水中查看')
+  }
+
+  const buy = async (pack: CreditPack, price: PackPrice) => {
+    try {
+      const payment = await paymentAPI.checkout(pack.id, price.provider)
+      setPending(payment)
+      const openInvoice = window.Telegram?.WebApp?.openInvoice
+      if (payment.provider === 'telegram' && openInvoice && payment.checkout_url) {
+        openInvoice(payment.checkout_url)
+      } else if (payment.checkout_url) {
+        window.open(payment.checkout_url, '_blank')
+      }
+      await waitForPayment(payment)
+    } catch (error: any) {
+      const code = error.response?.data?.error
+      toast.error(code === 'payment_provider_error' ? '支付服务暂不可用，请稍后再试' : code || '下单失败')
+    } finally {
+      setPending(null)
+    }
+  }
+
+  if (packs.length === 0) {
+    return null
+  }
+
+  return (
+    <div className="card md:col-span-2">
+      <h2 className="text-xl font-bold text-gray-900 mb-1">购买积分</h2>
+      <p className="text-sm text-gray-600 mb-4">购买的积分长期有效，不随配额周期过期</p>
+      <div className="grid gap-4 md:grid-cols-3">
+        {packs.map((pack) => (
+          <div key={pack.id} className="border border-gray-200 rounded-lg p-4">
+            <p className="text-lg font-bold text-gray-900">{pack.name}</p>
+            <div className="mt-3 space-y-2">
+              {pack.prices.map((price) => (
+                <button
+                  key={price.provider}
+                  className="btn-secondary w-full text-sm"
+                  disabled={pending !== null}
+                  onClick={() => buy(pack, price)}
+                >
+                  {providerLabels[price.provider] || price.provider} · {formatPrice(price)}
+                </button>
+              ))}
+            </div>
+          </div>
+        ))}
+      </div>
+      {pending && <p className="mt-4 text-sm text-gray-600">正在等待支付结果…</p>}
+    </div>
+  )
+}
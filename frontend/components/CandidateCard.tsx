@@ -74,10 +74,41 @@ export default function CandidateCard({ candidate }: CandidateCardProps) {
         </div>
       )}
 
+      {/* 本企业标签 */}
+      {candidate.tags && candidate.tags.length > 0 && (
+        <div className="mt-4 flex flex-wrap gap-2">
+          {candidate.tags.map((tag) => (
+            <span
+              key={tag}
+              className="bg-purple-50 text-purple-700 px-2 py-1 rounded text-xs"
+            >
+              #{tag}
+            </span>
+          ))}
+        </div>
+      )}
+
+      {/* 招聘流程阶段 */}
+      {candidate.pipelines && candidate.pipelines.length > 0 && (
+        <div className="mt-4 pt-4 border-t border-gray-200">
+          <p className="text-xs text-gray-600 mb-2">招聘进度:</p>
+          <div className="flex flex-wrap gap-2">
+            {candidate.pipelines.map((p) => (
+              <span
+                key={p.pipeline_id}
+                className="bg-amber-50 text-amber-800 px-2 py-1 rounded text-xs"
+              >
+                {p.pipeline_name} · {p.stage}
+              </span>
+            ))}
+          </div>
+        </div>
+      )}
+
       {/* 底部操作区 */}
       <div className="mt-4 pt-4 border-t border-gray-200 flex justify-between items-center">
         <span className={`text-xs ${candidate.unlocked_contact ? 'text-green-600' : 'text-gray-500'}`}>
-          {candidate.unlocked_contact ? '✓ 已解锁' : '🔒 未解锁'}
+          {candidate.unlocked_contact ? '✓ 已解锁' : `🔒 未解锁 · ${candidate.unlock_cost} 积分`}
         </span>
         <span className="text-blue-600 group-hover:text-blue-700 font-medium text-sm">
           查看详情 →
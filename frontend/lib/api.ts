@@ -38,7 +38,19 @@ apiClient.interceptors.request.use((config) => {
  */
 apiClient.interceptors.response.use(
   (response) => response,
-  (error) => {
+  async (error) => {
+    // 401 未授权 - 先尝试用 refresh token 续期一次（access token 默认 1 小时过期）
+    const original = error.config
+    const isAuthCall = original?.url?.startsWith('/auth/')
+    if (error.response?.status === 401 && original && !original._retried && !isAuthCall) {
+      original._retried = true
+      try {
+        await apiClient.post('/auth/refresh')
+        return apiClient(original)
+      } catch {
+        // 续期失败，继续走下面的登出逻辑
+      }
+    }
     // 401 未授权 - 清除令牌并重定向到登录页
     if (error.response?.status === 401) {
       if (typeof window !== 'undefined') {
@@ -73,6 +85,7 @@ export interface TelegramAuthData {
   photo_url?: string      // 头像 URL（可选）
   auth_date: number       // 认证时间戳
   hash: string            // HMAC 哈希值，用于验证
+  invite_code?: string    // 团队邀请码（可选，来自 bot /start 深链）
 }
 
 /**
@@ -82,6 +95,7 @@ export interface LoginResponse {
   success: boolean        // 登录是否成功
   user_id: number         // HR 用户 ID
   status: string          // 用户状态：active/pending/blocked
+  role: string            // 角色：owner/admin/recruiter
 }
 
 /**
@@ -99,7 +113,20 @@ export interface Candidate {
   bc_experience: boolean            // 是否有区块链经验
   summary: string                   // 简介
   unlocked_contact: boolean         // 是否已解锁联系方式
+  unlock_cost: number               // 首次解锁消耗的积分
   skills: string[]                  // 技能列表
+  pipelines: CandidatePipelineStage[] // 所在招聘流程及阶段
+  tags: string[]                    // 本企业打的标签
+}
+
+/**
+ * 候选人在某个招聘流程中的当前阶段
+ */
+export interface CandidatePipelineStage {
+  pipeline_id: number     // 招聘流程 ID
+  pipeline_name: string   // 招聘流程名称
+  stage_id: number        // 阶段 ID
+  stage: string           // 阶段名称
 }
 
 /**
@@ -112,6 +139,49 @@ export interface CandidateDetail extends Candidate {
     email: string         // 邮箱
     phone: string         // 电话
   }
+  contact_mode: 'open' | 'request'  // request：解锁需候选人同意
+  contact_request?: ContactRequest  // 本企业的联系方式请求（如有）
+  contact_report?: ContactReport    // 本企业对联系方式无效的报告（如有）
+  notes: CandidateNote[]            // 本企业的私有备注（新的在前）
+}
+
+/**
+ * 联系方式无效报告：平台审核通过后退还解锁消耗的积分
+ */
+export interface ContactReport {
+  id: number
+  candidate_slug: string
+  reason: ContactReportReason
+  details?: string
+  status: 'pending' | 'refunded' | 'rejected'
+  refund_amount: number             // 退还的积分
+  review_note?: string              // 审核说明
+  created_at: string
+  reviewed_at?: string
+}
+
+export type ContactReportReason = 'telegram_invalid' | 'phone_invalid' | 'email_invalid' | 'other'
+
+/**
+ * 候选人备注（仅本企业可见，只有作者可以修改或删除）
+ */
+export interface CandidateNote {
+  id: number
+  body: string
+  author_id: number
+  author_name: string
+  created_at: string
+  updated_at: string
+}
+
+/**
+ * 联系方式请求（候选人为 request 模式时，解锁会先发起请求，候选人同意后才扣额度）
+ */
+export interface ContactRequest {
+  status: 'pending' | 'accepted' | 'declined' | 'expired'
+  requested_at: string
+  expires_at: string
+  decided_at?: string
 }
 
 /**
@@ -120,6 +190,7 @@ export interface CandidateDetail extends Candidate {
 export interface CandidateListParams {
   q?: string                    // 关键词搜索
   skill?: string                // 技能筛选
+  tag?: string                  // 本企业标签筛选
   english?: string              // 英语水平筛选
   bc_experience?: boolean       // 区块链经验筛选
   availability_days_max?: number// 最大可用天数
@@ -145,6 +216,14 @@ export interface CandidateContactResponse {
   phone: string                 // 电话号码
 }
 
+/**
+ * 解锁结果：直接返回联系方式，或（202）返回待候选人确认的请求
+ */
+export interface UnlockResult {
+  contact?: CandidateContactResponse
+  contact_request?: ContactRequest
+}
+
 /**
  * 审计日志接口
  */
@@ -191,7 +270,97 @@ export interface MeResponse {
     unlock_quota_remaining: number
     period_start: string
     period_end: string
+    credit_balance: number          // 可用积分
   }
+  plan: Plan | null    // 当前订阅套餐
+}
+
+/**
+ * 订阅套餐：每个额度周期开始时按套餐的解锁次数重置额度
+ */
+export interface Plan {
+  id: number
+  code: string                      // starter / pro / enterprise ...
+  name: string
+  unlock_quota_monthly: number      // 每个周期的解锁次数
+  seat_limit: number                // 可用成员数，0 表示不限
+  features: Record<string, boolean> // 功能标记
+  is_default: boolean
+}
+
+/**
+ * 积分余额：每个额度周期按套餐发放积分，周期结束时未用完的发放积分过期，充值积分不过期
+ */
+export interface CreditBalance {
+  balance: number
+  ledger_balance: number            // 流水合计
+  reconciled: boolean               // 余额与流水是否一致
+  period_grant: number              // 本周期发放
+  period_spent: number              // 本周期已用
+  period_start: string
+  period_end: string
+}
+
+/**
+ * 积分流水
+ */
+export interface CreditEntry {
+  id: number
+  kind: 'grant' | 'topup' | 'unlock' | 'refund' | 'expire' | 'adjustment'
+  amount: number                    // 正数为入账，负数为扣减
+  balance_after: number
+  candidate_slug?: string
+  hr_user_id?: number
+  ref?: string
+  note?: string
+  created_at: string
+}
+
+export interface CreditLedgerResponse {
+  items: CreditEntry[]
+  page: number
+  page_size: number
+}
+
+export type PaymentProvider = 'telegram' | 'card' | 'fake'
+
+export interface PackPrice {
+  provider: PaymentProvider
+  amount: number                    // 最小货币单位（CNY 为分，XTR 为 Stars）
+  currency: string
+}
+
+export interface CreditPack {
+  id: number
+  code: string
+  name: string
+  credits: number
+  prices: PackPrice[]
+}
+
+export interface CreditPacksResponse {
+  items: CreditPack[]
+  providers: PaymentProvider[]
+}
+
+export interface Payment {
+  id: number
+  pack_code: string
+  provider: PaymentProvider
+  credits: number
+  amount: number
+  currency: string
+  status: 'pending' | 'succeeded' | 'failed'
+  checkout_url?: string
+  failure_reason?: string
+  created_at: string
+  paid_at?: string
+}
+
+export interface PaymentListResponse {
+  items: Payment[]
+  page: number
+  page_size: number
 }
 
 // ==================== API 方法 ====================
@@ -209,6 +378,27 @@ export const authAPI = {
     const response = await apiClient.post('/auth/telegram/login', data)
     return response.data
   },
+
+  /**
+   * Telegram Mini App 登录（从 bot 的 web_app 按钮打开时）
+   * @param initData window.Telegram.WebApp.initData 原始字符串，由服务端校验签名
+   * @param inviteCode 团队邀请码（可选）
+   * @returns 登录响应
+   */
+  telegramWebAppLogin: async (initData: string, inviteCode?: string): Promise<LoginResponse> => {
+    const response = await apiClient.post('/auth/telegram/webapp', {
+      init_data: initData,
+      invite_code: inviteCode || undefined,
+    })
+    return response.data
+  },
+
+  /**
+   * 登出：服务端吊销当前 token 并清除 cookie
+   */
+  logout: async (): Promise<void> => {
+    await apiClient.post('/auth/logout')
+  },
 }
 
 /**
@@ -238,10 +428,24 @@ export const candidateAPI = {
   /**
    * 解锁候选人联系方式
    * @param slug 候选人唯一标识
-   * @returns 联系方式信息
+   * @returns 联系方式信息；候选人需同意时返回 contact_request
    */
-  unlock: async (slug: string): Promise<CandidateContactResponse> => {
+  unlock: async (slug: string): Promise<UnlockResult> => {
     const response = await apiClient.post(`/api/candidates/${slug}/unlock`)
+    if (response.status === 202) {
+      return { contact_request: response.data.contact_request }
+    }
+    return { contact: response.data }
+  },
+
+  /**
+   * 报告已解锁的联系方式无效，等待平台审核退款
+   * @param slug 候选人唯一标识
+   * @param reason 原因
+   * @param details 补充说明（可选）
+   */
+  reportContact: async (slug: string, reason: ContactReportReason, details?: string): Promise<ContactReport> => {
+    const response = await apiClient.post(`/api/candidates/${slug}/report`, { reason, details })
     return response.data
   },
 }
@@ -277,4 +481,66 @@ export const accountAPI = {
   },
 }
 
+/**
+ * 积分相关 API
+ */
+export const creditAPI = {
+  /**
+   * 获取积分余额
+   */
+  getBalance: async (): Promise<CreditBalance> => {
+    const response = await apiClient.get('/api/credits')
+    return response.data
+  },
+
+  /**
+   * 获取积分流水（需要审计日志权限）
+   */
+  getLedger: async (page: number = 1, page_size: number = 20): Promise<CreditLedgerResponse> => {
+    const response = await apiClient.get('/api/credits/ledger', {
+      params: { page, page_size },
+    })
+    return response.data
+  },
+}
+
+/**
+ * 购买积分 API
+ */
+export const paymentAPI = {
+  /**
+   * 获取在售积分包及各支付方式的价格
+   */
+  getPacks: async (): Promise<CreditPacksResponse> => {
+    const response = await apiClient.get('/api/payments/packs')
+    return response.data
+  },
+
+  /**
+   * 下单，返回的 checkout_url 为支付页面（仅企业所有者）
+   */
+  checkout: async (pack_id: number, provider: PaymentProvider): Promise<Payment> => {
+    const response = await apiClient.post('/api/payments', { pack_id, provider })
+    return response.data
+  },
+
+  /**
+   * 查询订单状态
+   */
+  get: async (id: number): Promise<Payment> => {
+    const response = await apiClient.get(`/api/payments/${id}`)
+    return response.data
+  },
+
+  /**
+   * 获取购买记录
+   */
+  list: async (page: number = 1, page_size: number = 20): Promise<PaymentListResponse> => {
+    const response = await apiClient.get('/api/payments', {
+      params: { page, page_size },
+    })
+    return response.data
+  },
+}
+
 export default apiClient
@@ -2,6 +2,7 @@
  * 简化状态管理（不依赖 Zustand）
  */
 import { useState, useEffect } from 'react'
+import { authAPI } from './api'
 
 export interface User {
   hrUserID: number
@@ -28,9 +29,13 @@ class SimpleAuthStore {
     this.notify()
     if (typeof window !== 'undefined') {
       localStorage.removeItem('hr_auth')
-      // Best-effort cookie cleanup (HttpOnly cookies cannot be cleared from JS)
-      document.cookie = 'hr_auth=; Max-Age=0; path=/'
-      window.location.href = '/unauthorized'
+      // Server revokes the tokens and clears the HttpOnly cookies
+      authAPI
+        .logout()
+        .catch(() => {})
+        .finally(() => {
+          window.location.href = '/unauthorized'
+        })
     }
   }
 
@@ -1,35 +1,96 @@
 package auth
 
 import (
+    "context"
     "errors"
+    "log"
+    "time"
 
     "github.com/golang-jwt/jwt/v5"
     "tg-hr-platform/internal/domain"
 )
 
+// AdminScope marks platform admin tokens.
+const AdminScope = "platform_admin"
+
+// Token types ("typ" claim). Access tokens authorize /api; refresh tokens only /auth/refresh.
+const (
+    TokenTypeAccess  = "access"
+    TokenTypeRefresh = "refresh"
+)
+
+// ErrDenylistUnavailable is returned by ParseRefresh when the denylist can't be checked:
+// a refresh token outlives any outage, so it is not accepted unchecked.
+var ErrDenylistUnavailable = errors.New("token denylist unavailable")
+
+// Revocations is a denylist of token IDs (jti), e.g. cache.TokenDenylist.
+type Revocations interface {
+    IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// TokenMeta is the registered-claims part of a token needed to revoke it.
+type TokenMeta struct {
+    ID        string
+    Type      string
+    ExpiresAt time.Time
+}
+
 type JWTVerifier struct {
-    secret []byte
+    keys        *KeySet
+    issuer      string
+    revocations Revocations
+}
+
+func NewJWTVerifier(keys *KeySet, issuer string) *JWTVerifier {
+    return &JWTVerifier{keys: keys, issuer: issuer}
 }
 
-func NewJWTVerifier(secret string) *JWTVerifier {
-    return &JWTVerifier{secret: []byte(secret)}
+// SetRevocations enables the jti denylist check in Parse/ParseRefresh.
+func (v *JWTVerifier) SetRevocations(r Revocations) {
+    v.revocations = r
 }
 
-// Parse implements middleware.JWTVerifier
+// Parse implements middleware.JWTVerifier (access tokens only)
 func (v *JWTVerifier) Parse(tokenStr string) (*domain.HRClaims, error) {
-    token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
-        if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, errors.New("unexpected signing method")
-        }
-        return v.secret, nil
-    })
-    if err != nil || token == nil || !token.Valid {
-        return nil, errors.New("invalid token")
+    claims, _, err := v.parseHR(tokenStr, TokenTypeAccess)
+    return claims, err
+}
+
+// ParseRefresh validates a refresh token and returns its claims plus jti/exp for rotation.
+func (v *JWTVerifier) ParseRefresh(tokenStr string) (*domain.HRClaims, TokenMeta, error) {
+    return v.parseHR(tokenStr, TokenTypeRefresh)
+}
+
+// Meta verifies signature, issuer and expiry but ignores the denylist; used by logout.
+func (v *JWTVerifier) Meta(tokenStr string) (TokenMeta, error) {
+    claimsMap, err := v.parseMap(tokenStr)
+    if err != nil {
+        return TokenMeta{}, err
     }
+    return metaFromClaims(claimsMap)
+}
 
-    claimsMap, ok := token.Claims.(jwt.MapClaims)
-    if !ok {
-        return nil, errors.New("invalid claims")
+func (v *JWTVerifier) parseHR(tokenStr, typ string) (*domain.HRClaims, TokenMeta, error) {
+    claimsMap, err := v.parseMap(tokenStr)
+    if err != nil {
+        return nil, TokenMeta{}, err
+    }
+    meta, err := metaFromClaims(claimsMap)
+    if err != nil {
+        return nil, TokenMeta{}, err
+    }
+    if meta.Type != typ {
+        return nil, TokenMeta{}, errors.New("wrong token type")
+    }
+    revoked, err := v.isRevoked(meta.ID)
+    if err != nil {
+        if typ == TokenTypeRefresh {
+            return nil, TokenMeta{}, ErrDenylistUnavailable
+        }
+        log.Printf("⚠️  token denylist unavailable: %v", err)
+    }
+    if revoked {
+        return nil, TokenMeta{}, errors.New("token revoked")
     }
 
     // required fields
@@ -37,19 +98,78 @@ func (v *JWTVerifier) Parse(tokenStr string) (*domain.HRClaims, error) {
     companyID, _ := toInt64(claimsMap["company_id"])
     status, _ := claimsMap["status"].(string)
     role, _ := claimsMap["role"].(string)
+    sv, _ := toInt64(claimsMap["sv"]) // absent in tokens issued before session_version
 
     if hrUserID == 0 || companyID == 0 {
-        return nil, errors.New("missing fields")
+        return nil, TokenMeta{}, errors.New("missing fields")
     }
     if status == "" {
         status = "active"
     }
     return &domain.HRClaims{
-        HRUserID:  hrUserID,
-        CompanyID: companyID,
-        Status:    status,
-        Role:      role,
-    }, nil
+        HRUserID:       hrUserID,
+        CompanyID:      companyID,
+        Status:         status,
+        Role:           role,
+        SessionVersion: int32(sv),
+    }, meta, nil
+}
+
+func (v *JWTVerifier) parseMap(tokenStr string) (jwt.MapClaims, error) {
+    opts := []jwt.ParserOption{jwt.WithExpirationRequired(), jwt.WithIssuedAt()}
+    if v.issuer != "" {
+        opts = append(opts, jwt.WithIssuer(v.issuer))
+    }
+    token, err := jwt.Parse(tokenStr, v.keys.keyFunc, opts...)
+    if err != nil || token == nil || !token.Valid {
+        return nil, errors.New("invalid token")
+    }
+
+    claimsMap, ok := token.Claims.(jwt.MapClaims)
+    if !ok {
+        return nil, errors.New("invalid claims")
+    }
+    return claimsMap, nil
+}
+
+// isRevoked reports the denylist error to parseHR, which fails open for access tokens like
+// the rest of the Redis usage (they are short-lived, and availability shouldn't hinge on
+// the cache) but closed for refresh tokens.
+func (v *JWTVerifier) isRevoked(jti string) (bool, error) {
+    if v.revocations == nil {
+        return false, nil
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+    defer cancel()
+    return v.revocations.IsRevoked(ctx, jti)
+}
+
+func metaFromClaims(claimsMap jwt.MapClaims) (TokenMeta, error) {
+    jti, _ := claimsMap["jti"].(string)
+    typ, _ := claimsMap["typ"].(string)
+    exp, err := claimsMap.GetExpirationTime()
+    if err != nil || exp == nil || jti == "" {
+        return TokenMeta{}, errors.New("missing fields")
+    }
+    return TokenMeta{ID: jti, Type: typ, ExpiresAt: exp.Time}, nil
+}
+
+// ParseAdmin parses a platform admin token (scope=platform_admin).
+// HR tokens are rejected here, and admin tokens are rejected by Parse (no typ/hr_user_id).
+func (v *JWTVerifier) ParseAdmin(tokenStr string) (*domain.AdminClaims, error) {
+    claimsMap, err := v.parseMap(tokenStr)
+    if err != nil {
+        return nil, err
+    }
+    if scope, _ := claimsMap["scope"].(string); scope != AdminScope {
+        return nil, errors.New("invalid scope")
+    }
+    tgUserID, _ := toInt64(claimsMap["tg_user_id"])
+    if tgUserID == 0 {
+        return nil, errors.New("missing fields")
+    }
+    username, _ := claimsMap["username"].(string)
+    return &domain.AdminClaims{TgUserID: tgUserID, Username: username}, nil
 }
 
 func toInt64(v any) (int64, bool) {
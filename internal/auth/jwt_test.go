@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testIssuer = "tg-hr-platform"
+
+// signTest signs an HR token with the given kid/secret; mutate adjusts the claims first.
+func signTest(t *testing.T, kid, secret, typ string, mutate func(jwt.MapClaims)) string {
+	t.Helper()
+	now := time.Now()
+	mc := jwt.MapClaims{
+		"typ":        typ,
+		"hr_user_id": int64(7),
+		"company_id": int64(3),
+		"status":     "active",
+		"role":       "owner",
+		"sv":         int32(2),
+		"jti":        "jti-" + typ,
+		"iat":        now.Unix(),
+		"exp":        now.Add(time.Hour).Unix(),
+		"iss":        testIssuer,
+	}
+	if mutate != nil {
+		mutate(mc)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mc)
+	token.Header["kid"] = kid
+	s, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+type fakeRevocations struct {
+	revoked map[string]bool
+	err     error
+}
+
+func (f *fakeRevocations) IsRevoked(_ context.Context, jti string) (bool, error) {
+	return f.revoked[jti], f.err
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	keys, err := ParseKeySet("k2:new-secret,k1:old-secret", "k2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid, secret := keys.Active(); kid != "k2" || string(secret) != "new-secret" {
+		t.Fatalf("active key %s:%s, want k2:new-secret", kid, secret)
+	}
+	v := NewJWTVerifier(keys, testIssuer)
+
+	tests := []struct {
+		name   string
+		token  string
+		wantOK bool
+	}{
+		{"active key", signTest(t, "k2", "new-secret", TokenTypeAccess, nil), true},
+		{"previous key", signTest(t, "k1", "old-secret", TokenTypeAccess, nil), true},
+		{"unknown kid", signTest(t, "k0", "old-secret", TokenTypeAccess, nil), false},
+		{"kid of another key", signTest(t, "k2", "old-secret", TokenTypeAccess, nil), false},
+		{"no kid", signTest(t, "", "new-secret", TokenTypeAccess, nil), false},
+	}
+	for _, tt := range tests {
+		if _, err := v.Parse(tt.token); (err == nil) != tt.wantOK {
+			t.Errorf("%s: err = %v, want ok %v", tt.name, err, tt.wantOK)
+		}
+	}
+
+	// once k1 is dropped from JWT_KEYS its tokens stop working
+	rotated, err := ParseKeySet("k2:new-secret", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJWTVerifier(rotated, testIssuer).Parse(tests[1].token); err == nil {
+		t.Error("token of a removed key accepted")
+	}
+}
+
+func TestJWTClaimsAndValidation(t *testing.T) {
+	keys, err := ParseKeySet("", "", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewJWTVerifier(keys, testIssuer)
+	sign := func(typ string, mutate func(jwt.MapClaims)) string {
+		return signTest(t, DefaultKID, "secret", typ, mutate)
+	}
+
+	claims, err := v.Parse(sign(TokenTypeAccess, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.HRUserID != 7 || claims.CompanyID != 3 || claims.Role != "owner" || claims.SessionVersion != 2 {
+		t.Errorf("access claims %+v", claims)
+	}
+	claims, meta, err := v.ParseRefresh(sign(TokenTypeRefresh, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.SessionVersion != 2 || meta.ID != "jti-refresh" || meta.Type != TokenTypeRefresh || time.Until(meta.ExpiresAt) <= 0 {
+		t.Errorf("refresh claims %+v, meta %+v", claims, meta)
+	}
+	// tokens issued before session_version carry no sv and count as version 0
+	if claims, err := v.Parse(sign(TokenTypeAccess, func(mc jwt.MapClaims) { delete(mc, "sv") })); err != nil || claims.SessionVersion != 0 {
+		t.Errorf("token without sv: %+v, %v", claims, err)
+	}
+
+	expired := func(mc jwt.MapClaims) { mc["exp"] = time.Now().Add(-time.Minute).Unix() }
+	rejected := map[string]func() error{
+		"refresh token as access": func() error { _, err := v.Parse(sign(TokenTypeRefresh, nil)); return err },
+		"access token as refresh": func() error { _, _, err := v.ParseRefresh(sign(TokenTypeAccess, nil)); return err },
+		"no typ":                  func() error { _, err := v.Parse(sign("", nil)); return err },
+		"expired access":          func() error { _, err := v.Parse(sign(TokenTypeAccess, expired)); return err },
+		"expired refresh":         func() error { _, _, err := v.ParseRefresh(sign(TokenTypeRefresh, expired)); return err },
+		"no exp": func() error {
+			_, err := v.Parse(sign(TokenTypeAccess, func(mc jwt.MapClaims) { delete(mc, "exp") }))
+			return err
+		},
+		"other issuer": func() error {
+			_, err := v.Parse(sign(TokenTypeAccess, func(mc jwt.MapClaims) { mc["iss"] = "other" }))
+			return err
+		},
+		"no jti": func() error {
+			_, err := v.Parse(sign(TokenTypeAccess, func(mc jwt.MapClaims) { delete(mc, "jti") }))
+			return err
+		},
+		"no company": func() error {
+			_, err := v.Parse(sign(TokenTypeAccess, func(mc jwt.MapClaims) { delete(mc, "company_id") }))
+			return err
+		},
+		"expired token meta": func() error { _, err := v.Meta(sign(TokenTypeRefresh, expired)); return err },
+	}
+	for name, parse := range rejected {
+		if err := parse(); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestJWTDenylist(t *testing.T) {
+	keys, err := ParseKeySet("", "", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewJWTVerifier(keys, testIssuer)
+	revocations := &fakeRevocations{revoked: map[string]bool{"jti-access": true, "jti-refresh": true}}
+	v.SetRevocations(revocations)
+	access := signTest(t, DefaultKID, "secret", TokenTypeAccess, nil)
+	refresh := signTest(t, DefaultKID, "secret", TokenTypeRefresh, nil)
+
+	if _, err := v.Parse(access); err == nil {
+		t.Error("revoked access token accepted")
+	}
+	if _, _, err := v.ParseRefresh(refresh); err == nil {
+		t.Error("revoked refresh token accepted")
+	}
+	// logout still needs the jti of a revoked token
+	if meta, err := v.Meta(refresh); err != nil || meta.ID != "jti-refresh" {
+		t.Errorf("Meta of a revoked token: %+v, %v", meta, err)
+	}
+
+	// with the denylist down, access tokens fail open and refresh tokens closed
+	revocations.revoked, revocations.err = nil, errors.New("redis: connection refused")
+	if _, err := v.Parse(access); err != nil {
+		t.Errorf("access token with the denylist down: %v", err)
+	}
+	if _, _, err := v.ParseRefresh(refresh); !errors.Is(err, ErrDenylistUnavailable) {
+		t.Errorf("refresh token with the denylist down: %v, want ErrDenylistUnavailable", err)
+	}
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultKID is used when only JWT_SECRET is configured.
+const DefaultKID = "default"
+
+// KeySet holds the HMAC keys accepted for JWTs, indexed by "kid" header.
+// New tokens are signed with the active key; older keys stay valid for verification
+// until removed, so JWT_SECRET can be rotated without logging everyone out.
+type KeySet struct {
+	activeKID string
+	keys      map[string][]byte
+}
+
+// ParseKeySet builds a KeySet from JWT_KEYS ("kid1:secret1,kid2:secret2") and JWT_ACTIVE_KID.
+// If spec is empty, fallbackSecret (JWT_SECRET) becomes the only key with kid "default".
+// If activeKID is empty, the first key in spec is active.
+func ParseKeySet(spec, activeKID, fallbackSecret string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string][]byte)}
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		if fallbackSecret == "" {
+			return nil, errors.New("no JWT signing key configured")
+		}
+		ks.keys[DefaultKID] = []byte(fallbackSecret)
+		ks.activeKID = DefaultKID
+		return ks, nil
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(part, ":")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("invalid JWT key entry %q (want kid:secret)", part)
+		}
+		if _, dup := ks.keys[kid]; dup {
+			return nil, fmt.Errorf("duplicate JWT kid %q", kid)
+		}
+		ks.keys[kid] = []byte(secret)
+		if ks.activeKID == "" {
+			ks.activeKID = kid
+		}
+	}
+	if activeKID != "" {
+		if _, ok := ks.keys[activeKID]; !ok {
+			return nil, fmt.Errorf("JWT active kid %q not found in keys", activeKID)
+		}
+		ks.activeKID = activeKID
+	}
+	if len(ks.keys) == 0 {
+		return nil, errors.New("no JWT signing key configured")
+	}
+	return ks, nil
+}
+
+// Active returns the kid and secret used to sign new tokens.
+func (k *KeySet) Active() (string, []byte) {
+	return k.activeKID, k.keys[k.activeKID]
+}
+
+// keyFunc resolves the verification key from the token's kid header.
+func (k *KeySet) keyFunc(t *jwt.Token) (any, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, errors.New("unexpected signing method")
+	}
+	kid, _ := t.Header["kid"].(string)
+	secret, ok := k.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown kid")
+	}
+	return secret, nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signedInitData(t *testing.T, botToken string, authDate time.Time, user string) string {
+	t.Helper()
+	v := url.Values{}
+	v.Set("query_id", "AAF1")
+	v.Set("user", user)
+	v.Set("auth_date", strconv.FormatInt(authDate.Unix(), 10))
+	v.Set("start_param", "INVITE123")
+	v.Set("hash", webAppHash(botToken, webAppDataCheckString(v)))
+	return v.Encode()
+}
+
+func TestVerifyWebAppInitData(t *testing.T) {
+	const token = "123456:test-token"
+	v := NewTelegramVerifier(token)
+	user := `{"id":42,"first_name":"Ann","username":"ann"}`
+
+	d, err := v.VerifyWebAppInitData(signedInitData(t, token, time.Now(), user))
+	if err != nil {
+		t.Fatalf("valid initData rejected: %v", err)
+	}
+	if d.User.ID != 42 || d.User.Username != "ann" || d.StartParam != "INVITE123" {
+		t.Fatalf("unexpected data: %+v", d)
+	}
+
+	cases := map[string]string{
+		"wrong bot token": signedInitData(t, "other:token", time.Now(), user),
+		"expired":         signedInitData(t, token, time.Now().Add(-2*WebAppInitDataMaxAge), user),
+		"future":          signedInitData(t, token, time.Now().Add(time.Hour), user),
+		"missing user id": signedInitData(t, token, time.Now(), `{"first_name":"Ann"}`),
+		"bot user":        signedInitData(t, token, time.Now(), `{"id":7,"is_bot":true}`),
+	}
+	for name, initData := range cases {
+		if _, err := v.VerifyWebAppInitData(initData); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	// tampering with any signed field invalidates the hash
+	q, _ := url.ParseQuery(signedInitData(t, token, time.Now(), user))
+	q.Set("user", `{"id":1,"first_name":"Mallory"}`)
+	if _, err := v.VerifyWebAppInitData(q.Encode()); err == nil {
+		t.Error("tampered initData accepted")
+	}
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CompanyStatusTTL is short so that blocking a company takes effect within seconds
+// even on instances that missed the explicit invalidation.
+const CompanyStatusTTL = 15 * time.Second
+
+// CompanyStatusCache caches companies.status for the auth middleware
+type CompanyStatusCache struct {
+	RDB *redis.Client
+}
+
+func companyStatusKey(companyID int64) string {
+	return fmt.Sprintf("company:status:%d", companyID)
+}
+
+// Get returns ("", nil) on a cache miss
+func (c *CompanyStatusCache) Get(ctx context.Context, companyID int64) (string, error) {
+	val, err := c.RDB.Get(ctx, companyStatusKey(companyID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+func (c *CompanyStatusCache) Set(ctx context.Context, companyID int64, status string) error {
+	return c.RDB.SetEx(ctx, companyStatusKey(companyID), status, CompanyStatusTTL).Err()
+}
+
+func (c *CompanyStatusCache) Invalidate(ctx context.Context, companyID int64) error {
+	return c.RDB.Del(ctx, companyStatusKey(companyID)).Err()
+}
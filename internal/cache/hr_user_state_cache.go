@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"tg-hr-platform/internal/domain"
+)
+
+// HRUserStateTTL bounds how stale a user's status/role/session_version can be on an
+// instance that missed the explicit invalidation.
+const HRUserStateTTL = 30 * time.Second
+
+// HRUserStateCache caches hr_users status, role and session_version for the auth middleware
+type HRUserStateCache struct {
+	RDB *redis.Client
+}
+
+func hrUserStateKey(hrUserID int64) string {
+	return fmt.Sprintf("hr:state:%d", hrUserID)
+}
+
+// Get returns (nil, nil) on a cache miss
+func (c *HRUserStateCache) Get(ctx context.Context, hrUserID int64) (*domain.HRUserState, error) {
+	val, err := c.RDB.Get(ctx, hrUserStateKey(hrUserID)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st domain.HRUserState
+	if json.Unmarshal([]byte(val), &st) != nil {
+		return nil, nil
+	}
+	return &st, nil
+}
+
+func (c *HRUserStateCache) Set(ctx context.Context, st domain.HRUserState) error {
+	b, _ := json.Marshal(st)
+	return c.RDB.SetEx(ctx, hrUserStateKey(st.HRUserID), string(b), HRUserStateTTL).Err()
+}
+
+func (c *HRUserStateCache) Invalidate(ctx context.Context, hrUserID int64) error {
+	return c.RDB.Del(ctx, hrUserStateKey(hrUserID)).Err()
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylist stores revoked JWT IDs (jti) until the token would have expired anyway
+type TokenDenylist struct {
+	RDB *redis.Client
+}
+
+func revokedTokenKey(jti string) string {
+	return fmt.Sprintf("jwt:revoked:%s", jti)
+}
+
+// Revoke denylists jti until expiresAt; already-expired tokens are skipped
+func (d *TokenDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return d.RDB.SetEx(ctx, revokedTokenKey(jti), "1", ttl).Err()
+}
+
+// RevokeOnce denylists jti like Revoke, atomically (SET NX): it reports false if jti was
+// already revoked, so of two concurrent refreshes with the same token only one wins.
+// Already-expired tokens report false as well.
+func (d *TokenDenylist) RevokeOnce(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+	return d.RDB.SetNX(ctx, revokedTokenKey(jti), "1", ttl).Result()
+}
+
+// IsRevoked implements auth.Revocations
+func (d *TokenDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := d.RDB.Exists(ctx, revokedTokenKey(jti)).Result()
+	return n > 0, err
+}
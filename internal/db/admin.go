@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Platform Admin ====================
+
+type AdminListCompaniesParams struct {
+	Status *string
+	Q      *string
+	Limit  int32
+	Offset int32
+}
+
+type AdminListCompaniesRow struct {
+	ID               int64
+	Name             string
+	Status           string
+	CreatedAt        pgtype.Timestamptz
+	MemberCount      int64
+	QuotaConfigured  bool
+	UnlockQuotaTotal int32
+	UnlockQuotaUsed  int32
+	PlanCode         pgtype.Text
+}
+
+func (q *Queries) AdminListCompanies(ctx context.Context, p AdminListCompaniesParams) ([]AdminListCompaniesRow, error) {
+	sql := `
+SELECT
+  c.id, c.name, c.status, c.created_at,
+  (SELECT count(*) FROM hr_users h WHERE h.company_id = c.id AND h.status <> 'removed') AS member_count,
+  (q.company_id IS NOT NULL) AS quota_configured,
+  COALESCE(q.unlock_quota_total, 0) AS unlock_quota_total,
+  COALESCE(q.unlock_quota_used, 0) AS unlock_quota_used,
+  (SELECT code FROM plans WHERE id = company_plan_id(c.id, CURRENT_DATE)) AS plan_code
+FROM companies c
+LEFT JOIN company_quotas q ON q.company_id = c.id
+WHERE ($1::text IS NULL OR c.status = $1::text)
+  AND ($2::text IS NULL OR c.name ILIKE '%' || $2::text || '%')
+ORDER BY c.created_at DESC
+LIMIT $3 OFFSET $4;`
+	rows, err := q.db.Query(ctx, sql, p.Status, p.Q, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]AdminListCompaniesRow, 0)
+	for rows.Next() {
+		var r AdminListCompaniesRow
+		if err := rows.Scan(&r.ID, &r.Name, &r.Status, &r.CreatedAt, &r.MemberCount,
+			&r.QuotaConfigured, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PlanCode); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type UpdateCompanyStatusParams struct {
+	ID     int64
+	Status string
+}
+
+func (q *Queries) UpdateCompanyStatus(ctx context.Context, p UpdateCompanyStatusParams) (int64, error) {
+	tag, err := q.db.Exec(ctx, `UPDATE companies SET status = $2, updated_at = now() WHERE id = $1`, p.ID, p.Status)
+	return tag.RowsAffected(), err
+}
+
+type AdminListHRUsersParams struct {
+	Status    *string
+	CompanyID *int64
+	Limit     int32
+	Offset    int32
+}
+
+type AdminListHRUsersRow struct {
+	ID          int64
+	CompanyID   int64
+	CompanyName string
+	TgUsername  pgtype.Text
+	DisplayName pgtype.Text
+	Role        string
+	Status      string
+	CreatedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) AdminListHRUsers(ctx context.Context, p AdminListHRUsersParams) ([]AdminListHRUsersRow, error) {
+	sql := `
+SELECT h.id, h.company_id, c.name AS company_name, h.tg_username, h.display_name, h.role, h.status, h.created_at
+FROM hr_users h
+JOIN companies c ON c.id = h.company_id
+WHERE h.status <> 'removed'
+  AND ($1::text IS NULL OR h.status = $1::text)
+  AND ($2::bigint IS NULL OR h.company_id = $2::bigint)
+ORDER BY h.created_at DESC
+LIMIT $3 OFFSET $4;`
+	rows, err := q.db.Query(ctx, sql, p.Status, p.CompanyID, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]AdminListHRUsersRow, 0)
+	for rows.Next() {
+		var r AdminListHRUsersRow
+		if err := rows.Scan(&r.ID, &r.CompanyID, &r.CompanyName, &r.TgUsername, &r.DisplayName,
+			&r.Role, &r.Status, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// AdminUpsertCompanyQuotaParams: nil fields keep the current value (or the table default on insert).
+type AdminUpsertCompanyQuotaParams struct {
+	CompanyID        int64
+	UnlockQuotaTotal *int32
+	UnlockQuotaUsed  *int32
+	PeriodEnd        pgtype.Date
+}
+
+func (q *Queries) AdminUpsertCompanyQuota(ctx context.Context, p AdminUpsertCompanyQuotaParams) (CompanyQuotaDetailRow, error) {
+	sql := `
+INSERT INTO company_quotas (company_id, unlock_quota_total, unlock_quota_used, period_end)
+VALUES (
+  $1,
+  COALESCE($2::int, (SELECT unlock_quota_monthly FROM plans WHERE id = company_plan_id($1, CURRENT_DATE)), 20),
+  COALESCE($3::int, 0),
+  COALESCE($4::date, CURRENT_DATE + INTERVAL '30 days')
+)
+ON CONFLICT (company_id) DO UPDATE SET
+  unlock_quota_total = COALESCE($2::int, company_quotas.unlock_quota_total),
+  unlock_quota_used  = COALESCE($3::int, company_quotas.unlock_quota_used),
+  period_end         = COALESCE($4::date, company_quotas.period_end),
+  updated_at = now()
+RETURNING company_id, unlock_quota_total, unlock_quota_used, period_start, period_end, credit_balance;`
+	var r CompanyQuotaDetailRow
+	err := q.db.QueryRow(ctx, sql, p.CompanyID, p.UnlockQuotaTotal, p.UnlockQuotaUsed, p.PeriodEnd).Scan(
+		&r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd, &r.CreditBalance,
+	)
+	return r, err
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ==================== Bot Outbox ====================
+
+type EnqueueUnlockNotificationParams struct {
+	CompanyID   int64
+	CandidateID int64
+	Kind        string
+}
+
+// EnqueueUnlockNotification queues a message for the candidate if they opted in and are
+// linked to a Telegram account; otherwise it inserts nothing.
+func (q *Queries) EnqueueUnlockNotification(ctx context.Context, p EnqueueUnlockNotificationParams) error {
+	_, err := q.db.Exec(ctx, `
+INSERT INTO bot_outbox (chat_id, kind, payload)
+SELECT c.tg_user_id, $3, jsonb_build_object(
+  'candidate_id', c.id,
+  'company_name', co.name,
+  'desired_role', COALESCE(c.desired_role, ''),
+  'timezone', COALESCE(c.timezone, '')
+)
+FROM candidates c
+JOIN companies co ON co.id = $1
+WHERE c.id = $2
+  AND c.notify_on_unlock
+  AND c.tg_user_id IS NOT NULL
+  AND c.status <> 'deleted'`, p.CompanyID, p.CandidateID, p.Kind)
+	return err
+}
+
+type BotOutboxRow struct {
+	ID        int64
+	ChatID    int64
+	Kind      string
+	Payload   []byte
+	Attempts  int32
+	CreatedAt time.Time
+}
+
+type ClaimDueBotOutboxParams struct {
+	Limit        int32
+	LeaseSeconds int32
+}
+
+// ClaimDueBotOutbox leases due messages: attempts is incremented and next_attempt_at pushed
+// out by the lease, so a crashed worker's messages are picked up again after it expires.
+// SKIP LOCKED lets several instances claim concurrently.
+func (q *Queries) ClaimDueBotOutbox(ctx context.Context, p ClaimDueBotOutboxParams) ([]BotOutboxRow, error) {
+	rows, err := q.db.Query(ctx, `
+UPDATE bot_outbox
+SET attempts = attempts + 1,
+    next_attempt_at = now() + ($2::int * interval '1 second')
+WHERE id IN (
+  SELECT id FROM bot_outbox
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY next_attempt_at
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, chat_id, kind, payload, attempts, created_at`, p.Limit, p.LeaseSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []BotOutboxRow
+	for rows.Next() {
+		var r BotOutboxRow
+		if err := rows.Scan(&r.ID, &r.ChatID, &r.Kind, &r.Payload, &r.Attempts, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (q *Queries) MarkBotOutboxSent(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE bot_outbox
+SET status = 'sent', sent_at = now(), last_error = NULL
+WHERE id = $1`, id)
+	return err
+}
+
+type RetryBotOutboxParams struct {
+	ID           int64
+	DelaySeconds int32
+	LastError    string
+}
+
+func (q *Queries) RetryBotOutbox(ctx context.Context, p RetryBotOutboxParams) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE bot_outbox
+SET next_attempt_at = now() + ($2::int * interval '1 second'), last_error = $3
+WHERE id = $1`, p.ID, p.DelaySeconds, p.LastError)
+	return err
+}
+
+type FailBotOutboxParams struct {
+	ID        int64
+	LastError string
+}
+
+func (q *Queries) FailBotOutbox(ctx context.Context, p FailBotOutboxParams) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE bot_outbox
+SET status = 'failed', last_error = $2
+WHERE id = $1`, p.ID, p.LastError)
+	return err
+}
+
+type SetCandidateNotifyOnUnlockParams struct {
+	ID     int64
+	Notify bool
+}
+
+func (q *Queries) SetCandidateNotifyOnUnlock(ctx context.Context, p SetCandidateNotifyOnUnlockParams) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE candidates
+SET notify_on_unlock = $2
+WHERE id = $1 AND status <> 'deleted'`, p.ID, p.Notify)
+	return err
+}
@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Candidate Notes ====================
+
+type CandidateNoteRow struct {
+	ID         int64
+	HrUserID   pgtype.Int8
+	AuthorName string
+	Body       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+const candidateNoteColumns = `
+  n.id, n.hr_user_id, COALESCE(h.display_name, h.tg_username, ''), n.body, n.created_at, n.updated_at`
+
+func scanCandidateNote(row interface{ Scan(...any) error }) (CandidateNoteRow, error) {
+	var r CandidateNoteRow
+	err := row.Scan(&r.ID, &r.HrUserID, &r.AuthorName, &r.Body, &r.CreatedAt, &r.UpdatedAt)
+	return r, err
+}
+
+type CreateCandidateNoteParams struct {
+	CompanyID   int64
+	CandidateID int64
+	HrUserID    int64
+	Body        string
+}
+
+func (q *Queries) CreateCandidateNote(ctx context.Context, p CreateCandidateNoteParams) (CandidateNoteRow, error) {
+	return scanCandidateNote(q.db.QueryRow(ctx, `
+WITH n AS (
+  INSERT INTO candidate_notes (company_id, candidate_id, hr_user_id, body)
+  VALUES ($1, $2, $3, $4)
+  RETURNING *
+)
+SELECT`+candidateNoteColumns+`
+FROM n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id`, p.CompanyID, p.CandidateID, p.HrUserID, p.Body))
+}
+
+type ListCandidateNotesParams struct {
+	CompanyID   int64
+	CandidateID int64
+}
+
+// ListCandidateNotes returns the company's notes on the candidate, newest first.
+func (q *Queries) ListCandidateNotes(ctx context.Context, p ListCandidateNotesParams) ([]CandidateNoteRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT`+candidateNoteColumns+`
+FROM candidate_notes n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id
+WHERE n.company_id = $1 AND n.candidate_id = $2
+ORDER BY n.created_at DESC, n.id DESC`, p.CompanyID, p.CandidateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]CandidateNoteRow, 0)
+	for rows.Next() {
+		r, err := scanCandidateNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type GetCandidateNoteParams struct {
+	ID          int64
+	CompanyID   int64
+	CandidateID int64
+}
+
+func (q *Queries) GetCandidateNote(ctx context.Context, p GetCandidateNoteParams) (CandidateNoteRow, error) {
+	return scanCandidateNote(q.db.QueryRow(ctx, `
+SELECT`+candidateNoteColumns+`
+FROM candidate_notes n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id
+WHERE n.id = $1 AND n.company_id = $2 AND n.candidate_id = $3`, p.ID, p.CompanyID, p.CandidateID))
+}
+
+type UpdateCandidateNoteParams struct {
+	ID       int64
+	HrUserID int64
+	Body     string
+}
+
+// UpdateCandidateNote edits a note written by HrUserID; ErrNoRows otherwise.
+func (q *Queries) UpdateCandidateNote(ctx context.Context, p UpdateCandidateNoteParams) (CandidateNoteRow, error) {
+	return scanCandidateNote(q.db.QueryRow(ctx, `
+WITH n AS (
+  UPDATE candidate_notes
+  SET body = $3, updated_at = now()
+  WHERE id = $1 AND hr_user_id = $2
+  RETURNING *
+)
+SELECT`+candidateNoteColumns+`
+FROM n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id`, p.ID, p.HrUserID, p.Body))
+}
+
+type DeleteCandidateNoteParams struct {
+	ID       int64
+	HrUserID int64
+}
+
+// DeleteCandidateNote deletes a note written by HrUserID and returns the number of deleted rows.
+func (q *Queries) DeleteCandidateNote(ctx context.Context, p DeleteCandidateNoteParams) (int64, error) {
+	tag, err := q.db.Exec(ctx, `DELETE FROM candidate_notes WHERE id = $1 AND hr_user_id = $2`, p.ID, p.HrUserID)
+	return tag.RowsAffected(), err
+}
+
+// ==================== Candidate Tags ====================
+
+type AddCandidateTagParams struct {
+	CompanyID   int64
+	CandidateID int64
+	Tag         string
+	CreatedBy   int64
+	// MaxPerCandidate caps the company's tags on the candidate
+	MaxPerCandidate int32
+}
+
+// AddCandidateTag returns the number of added rows: 0 when the candidate already has the
+// tag (in any letter case) or already has MaxPerCandidate tags.
+func (q *Queries) AddCandidateTag(ctx context.Context, p AddCandidateTagParams) (int64, error) {
+	tag, err := q.db.Exec(ctx, `
+INSERT INTO candidate_tags (company_id, candidate_id, tag, created_by)
+SELECT $1, $2, $3, $4
+WHERE (SELECT count(*) FROM candidate_tags WHERE company_id = $1 AND candidate_id = $2) < $5
+ON CONFLICT (company_id, candidate_id, lower(tag)) DO NOTHING`, p.CompanyID, p.CandidateID, p.Tag, p.CreatedBy, p.MaxPerCandidate)
+	return tag.RowsAffected(), err
+}
+
+type RemoveCandidateTagParams struct {
+	CompanyID   int64
+	CandidateID int64
+	Tag         string
+}
+
+// RemoveCandidateTag returns the number of removed rows (0 if the candidate lacks the tag).
+func (q *Queries) RemoveCandidateTag(ctx context.Context, p RemoveCandidateTagParams) (int64, error) {
+	tag, err := q.db.Exec(ctx, `
+DELETE FROM candidate_tags
+WHERE company_id = $1 AND candidate_id = $2 AND lower(tag) = lower($3)`, p.CompanyID, p.CandidateID, p.Tag)
+	return tag.RowsAffected(), err
+}
+
+type ListCandidateTagsParams struct {
+	CompanyID    int64
+	CandidateIDs []int64
+}
+
+type ListCandidateTagsRow struct {
+	CandidateID int64
+	Tag         string
+}
+
+// ListCandidateTags returns the company's tags on the candidates, oldest first.
+func (q *Queries) ListCandidateTags(ctx context.Context, p ListCandidateTagsParams) ([]ListCandidateTagsRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT candidate_id, tag
+FROM candidate_tags
+WHERE company_id = $1 AND candidate_id = ANY($2::bigint[])
+ORDER BY candidate_id, created_at, tag`, p.CompanyID, p.CandidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]ListCandidateTagsRow, 0)
+	for rows.Next() {
+		var r ListCandidateTagsRow
+		if err := rows.Scan(&r.CandidateID, &r.Tag); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type ListCompanyTagsRow struct {
+	Tag   string
+	Count int32
+}
+
+// ListCompanyTags returns the tags the company uses on active candidates, most used first.
+func (q *Queries) ListCompanyTags(ctx context.Context, companyID int64) ([]ListCompanyTagsRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT min(ct.tag), count(*)::int
+FROM candidate_tags ct
+JOIN candidates c ON c.id = ct.candidate_id
+WHERE ct.company_id = $1 AND c.status = 'active'
+GROUP BY lower(ct.tag)
+ORDER BY count(*) DESC, lower(ct.tag)`, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]ListCompanyTagsRow, 0)
+	for rows.Next() {
+		var r ListCompanyTagsRow
+		if err := rows.Scan(&r.Tag, &r.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
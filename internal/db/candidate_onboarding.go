@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Candidate Onboarding ====================
+
+type OnboardingSessionRow struct {
+	ChatID    int64
+	TgUserID  int64
+	Step      string
+	Data      []byte
+	UpdatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) GetOnboardingSession(ctx context.Context, chatID int64) (OnboardingSessionRow, error) {
+	var r OnboardingSessionRow
+	err := q.db.QueryRow(ctx, `
+SELECT chat_id, tg_user_id, step, data, updated_at
+FROM candidate_onboarding_sessions
+WHERE chat_id = $1`, chatID).Scan(&r.ChatID, &r.TgUserID, &r.Step, &r.Data, &r.UpdatedAt)
+	return r, err
+}
+
+type UpsertOnboardingSessionParams struct {
+	ChatID   int64
+	TgUserID int64
+	Step     string
+	Data     []byte
+}
+
+func (q *Queries) UpsertOnboardingSession(ctx context.Context, p UpsertOnboardingSessionParams) error {
+	_, err := q.db.Exec(ctx, `
+INSERT INTO candidate_onboarding_sessions (chat_id, tg_user_id, step, data)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (chat_id) DO UPDATE
+SET tg_user_id = EXCLUDED.tg_user_id, step = EXCLUDED.step, data = EXCLUDED.data, updated_at = now()`,
+		p.ChatID, p.TgUserID, p.Step, p.Data)
+	return err
+}
+
+func (q *Queries) DeleteOnboardingSession(ctx context.Context, chatID int64) error {
+	_, err := q.db.Exec(ctx, `DELETE FROM candidate_onboarding_sessions WHERE chat_id = $1`, chatID)
+	return err
+}
+
+func (q *Queries) FindCandidateIDByTelegramID(ctx context.Context, tgUserID int64) (int64, error) {
+	var id int64
+	err := q.db.QueryRow(ctx, `
+SELECT id FROM candidates
+WHERE tg_user_id = $1 AND status <> 'deleted'
+LIMIT 1`, tgUserID).Scan(&id)
+	return id, err
+}
+
+type CreateOnboardedCandidateParams struct {
+	PublicSlug           string
+	TgUserID             int64
+	DisplayName          string
+	DesiredRole          string
+	EnglishLevel         string
+	ExpectedSalaryMinCny int32
+	ExpectedSalaryMaxCny int32
+	AvailabilityDays     int32
+	Timezone             string
+}
+
+// CreateOnboardedCandidate returns pgx.ErrNoRows if the Telegram account already has a candidate.
+func (q *Queries) CreateOnboardedCandidate(ctx context.Context, p CreateOnboardedCandidateParams) (int64, error) {
+	var id int64
+	err := q.db.QueryRow(ctx, `
+INSERT INTO candidates (
+  public_slug, tg_user_id, display_name, desired_role, english_level,
+  expected_salary_min_cny, expected_salary_max_cny, availability_days, timezone,
+  contact_consent_at, status
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), 'active')
+ON CONFLICT (tg_user_id) DO NOTHING
+RETURNING id`,
+		p.PublicSlug, p.TgUserID, p.DisplayName, p.DesiredRole, p.EnglishLevel,
+		p.ExpectedSalaryMinCny, p.ExpectedSalaryMaxCny, p.AvailabilityDays, p.Timezone,
+	).Scan(&id)
+	return id, err
+}
+
+// GetOrCreateSkillID matches skills case-insensitively so "golang" reuses an existing "Golang".
+func (q *Queries) GetOrCreateSkillID(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := q.db.QueryRow(ctx, `
+WITH existing AS (
+  SELECT id FROM skills WHERE lower(name) = lower($1) ORDER BY id LIMIT 1
+), inserted AS (
+  INSERT INTO skills (name)
+  SELECT $1 WHERE NOT EXISTS (SELECT 1 FROM existing)
+  ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+  RETURNING id
+)
+SELECT id FROM existing
+UNION ALL
+SELECT id FROM inserted
+LIMIT 1`, name).Scan(&id)
+	return id, err
+}
+
+type AddCandidateSkillParams struct {
+	CandidateID int64
+	SkillID     int64
+}
+
+func (q *Queries) AddCandidateSkill(ctx context.Context, p AddCandidateSkillParams) error {
+	_, err := q.db.Exec(ctx, `
+INSERT INTO candidate_skills (candidate_id, skill_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING`, p.CandidateID, p.SkillID)
+	return err
+}
+
+type UpsertCandidateTelegramContactParams struct {
+	CandidateID int64
+	TgUsername  string
+}
+
+func (q *Queries) UpsertCandidateTelegramContact(ctx context.Context, p UpsertCandidateTelegramContactParams) error {
+	_, err := q.db.Exec(ctx, `
+INSERT INTO candidate_contacts (candidate_id, tg_username)
+VALUES ($1, $2)
+ON CONFLICT (candidate_id) DO UPDATE
+SET tg_username = EXCLUDED.tg_username, updated_at = now()`, p.CandidateID, p.TgUsername)
+	return err
+}
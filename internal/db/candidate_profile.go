@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Candidate Self-Service ====================
+
+type CandidateProfileRow struct {
+	ID                   int64
+	PublicSlug           string
+	DisplayName          string
+	DesiredRole          pgtype.Text
+	EnglishLevel         pgtype.Text
+	ExpectedSalaryMinCny pgtype.Int4
+	ExpectedSalaryMaxCny pgtype.Int4
+	AvailabilityDays     pgtype.Int4
+	Timezone             pgtype.Text
+	Status               string
+	NotifyOnUnlock       bool
+	ContactMode          string
+	TgUsername           pgtype.Text
+}
+
+func (q *Queries) GetCandidateProfileByTelegramID(ctx context.Context, tgUserID int64) (CandidateProfileRow, error) {
+	var r CandidateProfileRow
+	err := q.db.QueryRow(ctx, `
+SELECT
+  c.id, c.public_slug, c.display_name, c.desired_role, c.english_level,
+  c.expected_salary_min_cny, c.expected_salary_max_cny, c.availability_days, c.timezone,
+  c.status, c.notify_on_unlock, c.contact_mode, cc.tg_username
+FROM candidates c
+LEFT JOIN candidate_contacts cc ON cc.candidate_id = c.id
+WHERE c.tg_user_id = $1 AND c.status <> 'deleted'
+LIMIT 1`, tgUserID).Scan(
+		&r.ID, &r.PublicSlug, &r.DisplayName, &r.DesiredRole, &r.EnglishLevel,
+		&r.ExpectedSalaryMinCny, &r.ExpectedSalaryMaxCny, &r.AvailabilityDays, &r.Timezone,
+		&r.Status, &r.NotifyOnUnlock, &r.ContactMode, &r.TgUsername,
+	)
+	return r, err
+}
+
+type UpdateCandidateStatusByIDParams struct {
+	ID     int64
+	Status string
+}
+
+func (q *Queries) UpdateCandidateStatusByID(ctx context.Context, p UpdateCandidateStatusByIDParams) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE candidates
+SET status = $2, updated_at = now()
+WHERE id = $1 AND status <> 'deleted'`, p.ID, p.Status)
+	return err
+}
+
+type UpdateOnboardedCandidateParams struct {
+	ID                   int64
+	DisplayName          string
+	DesiredRole          string
+	EnglishLevel         string
+	ExpectedSalaryMinCny int32
+	ExpectedSalaryMaxCny int32
+	AvailabilityDays     int32
+	Timezone             string
+}
+
+func (q *Queries) UpdateOnboardedCandidate(ctx context.Context, p UpdateOnboardedCandidateParams) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE candidates
+SET display_name = $2,
+    desired_role = $3,
+    english_level = $4,
+    expected_salary_min_cny = $5,
+    expected_salary_max_cny = $6,
+    availability_days = $7,
+    timezone = $8,
+    updated_at = now()
+WHERE id = $1 AND status <> 'deleted'`,
+		p.ID, p.DisplayName, p.DesiredRole, p.EnglishLevel,
+		p.ExpectedSalaryMinCny, p.ExpectedSalaryMaxCny, p.AvailabilityDays, p.Timezone)
+	return err
+}
+
+func (q *Queries) DeleteCandidateSkills(ctx context.Context, candidateID int64) error {
+	_, err := q.db.Exec(ctx, `DELETE FROM candidate_skills WHERE candidate_id = $1`, candidateID)
+	return err
+}
+
+func (q *Queries) DeleteCandidateContact(ctx context.Context, candidateID int64) error {
+	_, err := q.db.Exec(ctx, `DELETE FROM candidate_contacts WHERE candidate_id = $1`, candidateID)
+	return err
+}
+
+// MarkCandidateDeleted detaches the Telegram account so it can onboard again later.
+func (q *Queries) MarkCandidateDeleted(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE candidates
+SET status = 'deleted', tg_user_id = NULL, contact_consent_at = NULL, updated_at = now()
+WHERE id = $1`, id)
+	return err
+}
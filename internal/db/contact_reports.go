@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Contact Reports ====================
+
+type ContactUnlockRow struct {
+	ID        int64
+	Cost      int32
+	CreatedAt time.Time
+}
+
+type GetContactUnlockParams struct {
+	CompanyID   int64
+	CandidateID int64
+}
+
+// GetContactUnlock returns the company's contact unlock of the candidate; ErrNoRows if none.
+func (q *Queries) GetContactUnlock(ctx context.Context, p GetContactUnlockParams) (ContactUnlockRow, error) {
+	var r ContactUnlockRow
+	err := q.db.QueryRow(ctx, `
+SELECT id, cost, created_at
+FROM unlocks
+WHERE company_id = $1 AND candidate_id = $2 AND unlock_type = 'contact'`, p.CompanyID, p.CandidateID).Scan(&r.ID, &r.Cost, &r.CreatedAt)
+	return r, err
+}
+
+type ContactReportRow struct {
+	ID            int64
+	CompanyID     int64
+	CandidateID   int64
+	CandidateSlug string
+	UnlockID      int64
+	UnlockCost    int32
+	UnlockedAt    time.Time
+	HrUserID      pgtype.Int8
+	Reason        string
+	Details       string
+	Status        string
+	RefundAmount  int32
+	ReviewedBy    pgtype.Int8
+	ReviewNote    string
+	CreatedAt     time.Time
+	ReviewedAt    pgtype.Timestamptz
+}
+
+const contactReportColumns = `
+  r.id, r.company_id, r.candidate_id, c.public_slug, r.unlock_id, u.cost, u.created_at,
+  r.hr_user_id, r.reason, r.details, r.status, r.refund_amount, r.reviewed_by, r.review_note,
+  r.created_at, r.reviewed_at`
+
+// contactReportJoins follows "FROM contact_reports r" (or a CTE named r returning its rows)
+const contactReportJoins = `
+JOIN candidates c ON c.id = r.candidate_id
+JOIN unlocks u ON u.id = r.unlock_id`
+
+func scanContactReport(row interface{ Scan(...any) error }, extra ...any) (ContactReportRow, error) {
+	var r ContactReportRow
+	dest := []any{&r.ID, &r.CompanyID, &r.CandidateID, &r.CandidateSlug, &r.UnlockID, &r.UnlockCost, &r.UnlockedAt,
+		&r.HrUserID, &r.Reason, &r.Details, &r.Status, &r.RefundAmount, &r.ReviewedBy, &r.ReviewNote,
+		&r.CreatedAt, &r.ReviewedAt}
+	err := row.Scan(append(dest, extra...)...)
+	return r, err
+}
+
+type CreateContactReportParams struct {
+	CompanyID   int64
+	CandidateID int64
+	UnlockID    int64
+	HrUserID    int64
+	Reason      string
+	Details     string
+}
+
+// CreateContactReport returns ErrNoRows if the company already reported the candidate.
+func (q *Queries) CreateContactReport(ctx context.Context, p CreateContactReportParams) (ContactReportRow, error) {
+	return scanContactReport(q.db.QueryRow(ctx, `
+WITH r AS (
+  INSERT INTO contact_reports (company_id, candidate_id, unlock_id, hr_user_id, reason, details)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  ON CONFLICT (company_id, candidate_id) DO NOTHING
+  RETURNING *
+)
+SELECT`+contactReportColumns+`
+FROM r`+contactReportJoins, p.CompanyID, p.CandidateID, p.UnlockID, p.HrUserID, p.Reason, p.Details))
+}
+
+type GetContactReportParams struct {
+	CompanyID   int64
+	CandidateID int64
+}
+
+func (q *Queries) GetContactReport(ctx context.Context, p GetContactReportParams) (ContactReportRow, error) {
+	return scanContactReport(q.db.QueryRow(ctx, `
+SELECT`+contactReportColumns+`
+FROM contact_reports r`+contactReportJoins+`
+WHERE r.company_id = $1 AND r.candidate_id = $2`, p.CompanyID, p.CandidateID))
+}
+
+func (q *Queries) GetContactReportByID(ctx context.Context, id int64) (ContactReportRow, error) {
+	return scanContactReport(q.db.QueryRow(ctx, `
+SELECT`+contactReportColumns+`
+FROM contact_reports r`+contactReportJoins+`
+WHERE r.id = $1`, id))
+}
+
+// LockContactReport re-reads a report FOR UPDATE inside a transaction.
+func (q *Queries) LockContactReport(ctx context.Context, id int64) (ContactReportRow, error) {
+	return scanContactReport(q.db.QueryRow(ctx, `
+SELECT`+contactReportColumns+`
+FROM contact_reports r`+contactReportJoins+`
+WHERE r.id = $1
+FOR UPDATE OF r`, id))
+}
+
+type ResolveContactReportParams struct {
+	ID           int64
+	Status       string
+	RefundAmount int32
+	ReviewedBy   int64
+	ReviewNote   string
+}
+
+// ResolveContactReport closes a pending report; ErrNoRows if it is no longer pending.
+func (q *Queries) ResolveContactReport(ctx context.Context, p ResolveContactReportParams) (ContactReportRow, error) {
+	return scanContactReport(q.db.QueryRow(ctx, `
+WITH r AS (
+  UPDATE contact_reports
+  SET status = $2, refund_amount = $3, reviewed_by = $4, review_note = $5, reviewed_at = now()
+  WHERE id = $1 AND status = 'pending'
+  RETURNING *
+)
+SELECT`+contactReportColumns+`
+FROM r`+contactReportJoins, p.ID, p.Status, p.RefundAmount, p.ReviewedBy, p.ReviewNote))
+}
+
+type AdminListContactReportsParams struct {
+	Status *string
+	Limit  int32
+	Offset int32
+}
+
+type AdminListContactReportsRow struct {
+	ContactReportRow
+	CompanyName   string
+	CandidateName string
+	ReporterName  pgtype.Text
+	// the reported contact as it is now, for the reviewer to check
+	TgUsername pgtype.Text
+	Email      pgtype.Text
+	Phone      pgtype.Text
+}
+
+// AdminListContactReports lists reports across companies, oldest first so the review queue
+// is worked in order.
+func (q *Queries) AdminListContactReports(ctx context.Context, p AdminListContactReportsParams) ([]AdminListContactReportsRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT`+contactReportColumns+`,
+  co.name, c.display_name, h.display_name,
+  NULLIF(cc.tg_username, '')::text, NULLIF(cc.email::text, ''), NULLIF(cc.phone, '')::text
+FROM contact_reports r`+contactReportJoins+`
+JOIN companies co ON co.id = r.company_id
+LEFT JOIN hr_users h ON h.id = r.hr_user_id
+LEFT JOIN candidate_contacts cc ON cc.candidate_id = r.candidate_id
+WHERE ($1::text IS NULL OR r.status = $1::text)
+ORDER BY r.id
+LIMIT $2 OFFSET $3`, p.Status, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]AdminListContactReportsRow, 0)
+	for rows.Next() {
+		var r AdminListContactReportsRow
+		r.ContactReportRow, err = scanContactReport(rows, &r.CompanyName, &r.CandidateName, &r.ReporterName,
+			&r.TgUsername, &r.Email, &r.Phone)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
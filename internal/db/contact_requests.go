@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Contact Requests ====================
+
+type GetContactGateParams struct {
+	CompanyID   int64
+	CandidateID int64
+}
+
+type GetContactGateRow struct {
+	ContactMode string
+	Unlocked    bool
+	UnlockCost  int32
+}
+
+// GetContactGate returns the candidate's contact mode, unlock cost and whether the company
+// already unlocked the contact.
+func (q *Queries) GetContactGate(ctx context.Context, p GetContactGateParams) (GetContactGateRow, error) {
+	var r GetContactGateRow
+	err := q.db.QueryRow(ctx, `
+SELECT c.contact_mode,
+       EXISTS (
+         SELECT 1 FROM unlocks u
+         WHERE u.company_id = $1 AND u.candidate_id = c.id AND u.unlock_type = 'contact'
+       ),
+       c.unlock_cost
+FROM candidates c
+WHERE c.id = $2`, p.CompanyID, p.CandidateID).Scan(&r.ContactMode, &r.Unlocked, &r.UnlockCost)
+	return r, err
+}
+
+type UpsertContactRequestParams struct {
+	CompanyID   int64
+	CandidateID int64
+	HrUserID    int64
+	ExpiresAt   time.Time
+}
+
+// UpsertContactRequest creates a pending request, or renews an expired one. It returns
+// ErrNoRows when a live (pending, accepted or declined) request already exists.
+func (q *Queries) UpsertContactRequest(ctx context.Context, p UpsertContactRequestParams) (int64, error) {
+	var id int64
+	err := q.db.QueryRow(ctx, `
+INSERT INTO contact_requests (company_id, candidate_id, hr_user_id, status, expires_at)
+VALUES ($1, $2, $3, 'pending', $4)
+ON CONFLICT (company_id, candidate_id) DO UPDATE
+SET hr_user_id = EXCLUDED.hr_user_id,
+    status = 'pending',
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at,
+    decided_at = NULL
+WHERE contact_requests.status = 'pending' AND contact_requests.expires_at <= now()
+RETURNING id`, p.CompanyID, p.CandidateID, p.HrUserID, p.ExpiresAt).Scan(&id)
+	return id, err
+}
+
+type ContactRequestRow struct {
+	ID          int64
+	CompanyID   int64
+	CandidateID int64
+	HrUserID    int64
+	Status      string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	DecidedAt   pgtype.Timestamptz
+}
+
+const contactRequestColumns = `
+  cr.id, cr.company_id, cr.candidate_id, cr.hr_user_id, cr.status,
+  cr.created_at, cr.expires_at, cr.decided_at`
+
+func scanContactRequest(row interface{ Scan(...any) error }) (ContactRequestRow, error) {
+	var r ContactRequestRow
+	err := row.Scan(&r.ID, &r.CompanyID, &r.CandidateID, &r.HrUserID, &r.Status,
+		&r.CreatedAt, &r.ExpiresAt, &r.DecidedAt)
+	return r, err
+}
+
+type GetContactRequestParams struct {
+	CompanyID   int64
+	CandidateID int64
+}
+
+func (q *Queries) GetContactRequest(ctx context.Context, p GetContactRequestParams) (ContactRequestRow, error) {
+	return scanContactRequest(q.db.QueryRow(ctx, `
+SELECT`+contactRequestColumns+`
+FROM contact_requests cr
+WHERE cr.company_id = $1 AND cr.candidate_id = $2`, p.CompanyID, p.CandidateID))
+}
+
+type GetCandidateContactRequestParams struct {
+	ID       int64
+	TgUserID int64
+}
+
+// GetCandidateContactRequest loads a request addressed to the candidate linked to tgUserID.
+func (q *Queries) GetCandidateContactRequest(ctx context.Context, p GetCandidateContactRequestParams) (ContactRequestRow, error) {
+	return scanContactRequest(q.db.QueryRow(ctx, `
+SELECT`+contactRequestColumns+`
+FROM contact_requests cr
+JOIN candidates c ON c.id = cr.candidate_id
+WHERE cr.id = $1 AND c.tg_user_id = $2 AND c.status <> 'deleted'`, p.ID, p.TgUserID))
+}
+
+// LockContactRequest re-reads a request FOR UPDATE inside a transaction.
+func (q *Queries) LockContactRequest(ctx context.Context, id int64) (ContactRequestRow, error) {
+	return scanContactRequest(q.db.QueryRow(ctx, `
+SELECT`+contactRequestColumns+`
+FROM contact_requests cr
+WHERE cr.id = $1
+FOR UPDATE`, id))
+}
+
+type DecideContactRequestParams struct {
+	ID     int64
+	Status string
+}
+
+// DecideContactRequest answers a pending, unexpired request; ErrNoRows otherwise.
+func (q *Queries) DecideContactRequest(ctx context.Context, p DecideContactRequestParams) (int64, error) {
+	var id int64
+	err := q.db.QueryRow(ctx, `
+UPDATE contact_requests
+SET status = $2, decided_at = now()
+WHERE id = $1 AND status = 'pending' AND expires_at > now()
+RETURNING id`, p.ID, p.Status).Scan(&id)
+	return id, err
+}
+
+type EnqueueContactRequestNotificationParams struct {
+	RequestID int64
+	Kind      string
+}
+
+// EnqueueContactRequestNotification queues the accept/decline prompt for the candidate.
+func (q *Queries) EnqueueContactRequestNotification(ctx context.Context, p EnqueueContactRequestNotificationParams) error {
+	_, err := q.db.Exec(ctx, `
+INSERT INTO bot_outbox (chat_id, kind, payload)
+SELECT c.tg_user_id, $2, jsonb_build_object(
+  'request_id', cr.id,
+  'company_name', co.name,
+  'desired_role', COALESCE(c.desired_role, '')
+)
+FROM contact_requests cr
+JOIN candidates c ON c.id = cr.candidate_id
+JOIN companies co ON co.id = cr.company_id
+WHERE cr.id = $1 AND c.tg_user_id IS NOT NULL`, p.RequestID, p.Kind)
+	return err
+}
+
+type SetCandidateContactModeParams struct {
+	ID          int64
+	ContactMode string
+}
+
+func (q *Queries) SetCandidateContactMode(ctx context.Context, p SetCandidateContactModeParams) error {
+	_, err := q.db.Exec(ctx, `
+UPDATE candidates
+SET contact_mode = $2
+WHERE id = $1 AND status <> 'deleted'`, p.ID, p.ContactMode)
+	return err
+}
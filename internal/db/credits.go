@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Credit Ledger ====================
+
+type CreditEntryRow struct {
+	ID            int64
+	CompanyID     int64
+	Kind          string
+	Amount        int32
+	BalanceAfter  int32
+	CandidateID   pgtype.Int8
+	CandidateSlug pgtype.Text
+	HrUserID      pgtype.Int8
+	Ref           pgtype.Text
+	Note          string
+	CreatedAt     time.Time
+}
+
+type AddCreditsParams struct {
+	CompanyID int64
+	Kind      string
+	// Amount is signed: negative for debits
+	Amount      int32
+	CandidateID *int64
+	HrUserID    *int64
+	Ref         *string
+	Note        string
+}
+
+// AddCredits changes the company's credit balance and appends the matching ledger entry in
+// one statement. Callers hold the quota row lock (LockCompanyQuota) so entries are ordered and
+// checks against the balance stay valid. Returns pgx.ErrNoRows if the company has no quota row.
+func (q *Queries) AddCredits(ctx context.Context, p AddCreditsParams) (CreditEntryRow, error) {
+	sql := `
+WITH bal AS (
+  UPDATE company_quotas
+  SET credit_balance = credit_balance + $3, updated_at = now()
+  WHERE company_id = $1
+  RETURNING credit_balance
+)
+INSERT INTO credit_ledger (company_id, kind, amount, balance_after, candidate_id, hr_user_id, ref, note)
+SELECT $1, $2, $3, bal.credit_balance, $4, $5, $6, $7
+FROM bal
+RETURNING id, company_id, kind, amount, balance_after, candidate_id, NULL::text, hr_user_id, ref, note, created_at`
+	return scanCreditEntry(q.db.QueryRow(ctx, sql, p.CompanyID, p.Kind, p.Amount, p.CandidateID, p.HrUserID, p.Ref, p.Note))
+}
+
+func scanCreditEntry(row interface{ Scan(...any) error }) (CreditEntryRow, error) {
+	var r CreditEntryRow
+	err := row.Scan(&r.ID, &r.CompanyID, &r.Kind, &r.Amount, &r.BalanceAfter, &r.CandidateID, &r.CandidateSlug,
+		&r.HrUserID, &r.Ref, &r.Note, &r.CreatedAt)
+	return r, err
+}
+
+type GetCreditEntryByRefParams struct {
+	CompanyID int64
+	Kind      string
+	Ref       string
+}
+
+// GetCreditEntryByRef finds an earlier entry for the same external reference (idempotent top-ups).
+func (q *Queries) GetCreditEntryByRef(ctx context.Context, p GetCreditEntryByRefParams) (CreditEntryRow, error) {
+	sql := `
+SELECT l.id, l.company_id, l.kind, l.amount, l.balance_after, l.candidate_id, c.public_slug,
+       l.hr_user_id, l.ref, l.note, l.created_at
+FROM credit_ledger l
+LEFT JOIN candidates c ON c.id = l.candidate_id
+WHERE l.company_id = $1 AND l.kind = $2 AND l.ref = $3`
+	return scanCreditEntry(q.db.QueryRow(ctx, sql, p.CompanyID, p.Kind, p.Ref))
+}
+
+type ListCreditEntriesParams struct {
+	CompanyID int64
+	Limit     int32
+	Offset    int32
+}
+
+// ListCreditEntries returns the company's ledger, newest first.
+func (q *Queries) ListCreditEntries(ctx context.Context, p ListCreditEntriesParams) ([]CreditEntryRow, error) {
+	sql := `
+SELECT l.id, l.company_id, l.kind, l.amount, l.balance_after, l.candidate_id, c.public_slug,
+       l.hr_user_id, l.ref, l.note, l.created_at
+FROM credit_ledger l
+LEFT JOIN candidates c ON c.id = l.candidate_id
+WHERE l.company_id = $1
+ORDER BY l.id DESC
+LIMIT $2 OFFSET $3`
+	rows, err := q.db.Query(ctx, sql, p.CompanyID, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CreditEntryRow
+	for rows.Next() {
+		r, err := scanCreditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type GetCreditBalanceRow struct {
+	CompanyQuotaDetailRow
+	// LedgerBalance is the sum of all ledger entries; it equals CreditBalance unless the
+	// balance was changed outside AddCredits
+	LedgerBalance int64
+}
+
+func (q *Queries) GetCreditBalance(ctx context.Context, companyID int64) (GetCreditBalanceRow, error) {
+	sql := `
+SELECT q.company_id, q.unlock_quota_total, q.unlock_quota_used, q.period_start, q.period_end, q.credit_balance,
+       COALESCE((SELECT sum(amount) FROM credit_ledger WHERE company_id = q.company_id), 0)::bigint
+FROM company_quotas q
+WHERE q.company_id = $1`
+	var r GetCreditBalanceRow
+	err := q.db.QueryRow(ctx, sql, companyID).Scan(
+		&r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd, &r.CreditBalance,
+		&r.LedgerBalance,
+	)
+	return r, err
+}
+
+type SetCandidateUnlockCostParams struct {
+	PublicSlug string
+	Cost       int32
+}
+
+// SetCandidateUnlockCost returns the previous cost; pgx.ErrNoRows for unknown slugs.
+func (q *Queries) SetCandidateUnlockCost(ctx context.Context, p SetCandidateUnlockCostParams) (int32, error) {
+	sql := `
+UPDATE candidates c
+SET unlock_cost = $2
+FROM candidates old
+WHERE c.public_slug = $1 AND old.id = c.id
+RETURNING old.unlock_cost`
+	var prev int32
+	err := q.db.QueryRow(ctx, sql, p.PublicSlug, p.Cost).Scan(&prev)
+	return prev, err
+}
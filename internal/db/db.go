@@ -2,9 +2,11 @@ package db
 
 import (
 	"context"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // This project is structured to be compatible with sqlc.
@@ -15,11 +17,19 @@ import (
 // For convenience, we provide a small hand-written layer that mirrors the method signatures
 // used by the service/repo so the project builds without requiring sqlc installed.
 
+// DBTX is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, so the same Queries
+// can run against the pool or inside a transaction (see WithTx).
+type DBTX interface {
+    Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+    Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+    QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type Queries struct {
-    pool *pgxpool.Pool
+    db DBTX
 }
 
-func New(pool *pgxpool.Pool) *Queries { return &Queries{pool: pool} }
+func New(db DBTX) *Queries { return &Queries{db: db} }
 
 type ListCandidatesPageParams struct {
     CompanyID    int64
@@ -30,6 +40,10 @@ type ListCandidatesPageParams struct {
     SalaryMax    *int32
     Skill        *string
     Q            *string
+    // Tag is one of the company's candidate tags (case-insensitive)
+    Tag          *string
+    // UpdatedSince keeps candidates updated after this time (saved search digests)
+    UpdatedSince *time.Time
     Limit        int32
     Offset       int32
 }
@@ -48,32 +62,86 @@ type ListCandidatesPageRow struct {
     Summary              pgtype.Text
     Rating               pgtype.Int4
     UnlockedContact      bool
+    UnlockCost           int32
 }
 
 func (q *Queries) ListCandidatesPage(ctx context.Context, p ListCandidatesPageParams) ([]ListCandidatesPageRow, error) {
-    // NOTE: For full-text + skill filter + unlock join, see docs/schema.sql and internal/db/queries/*.sql
-    // This placeholder implements a basic version (without q rank) to keep the project runnable.
+    // Mirrors internal/db/queries/candidates.sql (ListCandidatesPage).
+    // q: full-text match on search_tsv (ranked by ts_rank) with a pg_trgm word-similarity
+    // fallback on display_name/desired_role so typos and partial words still match.
+    // skill: case-insensitive match through candidate_skills/skills.
+    // tag: case-insensitive match on the company's own candidate_tags.
     sql := `
+WITH filtered AS (
+  SELECT c.*
+  FROM candidates c
+  WHERE c.status = 'active'
+    AND ($2::text IS NULL OR $2::text = '' OR c.english_level = $2::text)
+    AND ($3::boolean IS NULL OR c.bc_experience = $3::boolean)
+    AND ($4::int IS NULL OR c.availability_days <= $4::int)
+    AND ($5::int IS NULL OR c.expected_salary_max_cny IS NULL OR c.expected_salary_max_cny >= $5::int)
+    AND ($6::int IS NULL OR c.expected_salary_min_cny IS NULL OR c.expected_salary_min_cny <= $6::int)
+    AND (
+      $7::text IS NULL OR $7::text = '' OR
+      EXISTS (
+        SELECT 1
+        FROM candidate_skills cs
+        JOIN skills s ON s.id = cs.skill_id
+        WHERE cs.candidate_id = c.id AND lower(s.name) = lower($7::text)
+      )
+    )
+    AND ($11::timestamptz IS NULL OR c.updated_at > $11::timestamptz)
+    AND (
+      $12::text IS NULL OR $12::text = '' OR
+      EXISTS (
+        SELECT 1
+        FROM candidate_tags ct
+        WHERE ct.company_id = $1 AND ct.candidate_id = c.id AND lower(ct.tag) = lower($12::text)
+      )
+    )
+),
+ranked AS (
+  SELECT
+    f.*,
+    CASE
+      WHEN $8::text IS NULL OR $8::text = '' THEN 0
+      ELSE ts_rank(f.search_tsv, websearch_to_tsquery('simple', $8::text))
+    END AS ts_rank,
+    CASE
+      WHEN $8::text IS NULL OR $8::text = '' THEN 0
+      ELSE GREATEST(
+        word_similarity($8::text, f.display_name),
+        word_similarity($8::text, coalesce(f.desired_role, ''))
+      )
+    END AS trgm_rank
+  FROM filtered f
+  WHERE ($8::text IS NULL OR $8::text = '')
+     OR f.search_tsv @@ websearch_to_tsquery('simple', $8::text)
+     OR $8::text <% f.display_name
+     OR $8::text <% f.desired_role
+)
 SELECT
-  c.id, c.public_slug, c.display_name,
-  c.desired_role, c.english_level,
-  c.expected_salary_min_cny, c.expected_salary_max_cny,
-  c.availability_days, c.timezone,
-  c.bc_experience, c.summary, c.rating,
-  (u.id IS NOT NULL) AS unlocked_contact
-FROM candidates c
+  r.id, r.public_slug, r.display_name,
+  r.desired_role, r.english_level,
+  r.expected_salary_min_cny, r.expected_salary_max_cny,
+  r.availability_days, r.timezone,
+  r.bc_experience, r.summary, r.rating,
+  (u.id IS NOT NULL) AS unlocked_contact,
+  r.unlock_cost
+FROM ranked r
 LEFT JOIN unlocks u
-  ON u.company_id = $1 AND u.candidate_id = c.id AND u.unlock_type = 'contact'
-WHERE c.status = 'active'
-  AND ($2::text IS NULL OR $2 = '' OR c.english_level = $2)
-  AND ($3::boolean IS NULL OR c.bc_experience = $3)
-  AND ($4::int IS NULL OR c.availability_days <= $4)
-  AND ($5::int IS NULL OR c.expected_salary_max_cny IS NULL OR c.expected_salary_max_cny >= $5)
-  AND ($6::int IS NULL OR c.expected_salary_min_cny IS NULL OR c.expected_salary_min_cny <= $6)
-ORDER BY c.updated_at DESC
-LIMIT $7 OFFSET $8;
+  ON u.company_id = $1 AND u.candidate_id = r.id AND u.unlock_type = 'contact'
+ORDER BY
+  r.ts_rank DESC NULLS LAST,
+  r.trgm_rank DESC,
+  r.rating DESC,
+  r.updated_at DESC
+LIMIT $9 OFFSET $10;
 `
-    rows, err := q.pool.Query(ctx, sql, p.CompanyID, p.EnglishLevel, p.BcExperience, p.AvailMax, p.SalaryMin, p.SalaryMax, p.Limit, p.Offset)
+    rows, err := q.db.Query(ctx, sql,
+        p.CompanyID, p.EnglishLevel, p.BcExperience, p.AvailMax, p.SalaryMin, p.SalaryMax,
+        p.Skill, p.Q, p.Limit, p.Offset, p.UpdatedSince, p.Tag,
+    )
     if err != nil { return nil, err }
     defer rows.Close()
 
@@ -86,7 +154,7 @@ LIMIT $7 OFFSET $8;
             &r.ExpectedSalaryMinCny, &r.ExpectedSalaryMaxCny,
             &r.AvailabilityDays, &r.Timezone,
             &r.BcExperience, &r.Summary, &r.Rating,
-            &r.UnlockedContact,
+            &r.UnlockedContact, &r.UnlockCost,
         )
         if err != nil { return nil, err }
         out = append(out, r)
@@ -113,6 +181,7 @@ type GetCandidateBySlugWithUnlockedRow struct {
     Summary              pgtype.Text
     Rating               pgtype.Int4
     UnlockedContact      bool
+    UnlockCost           int32
 }
 
 func (q *Queries) GetCandidateBySlugWithUnlocked(ctx context.Context, p GetCandidateBySlugWithUnlockedParams) (GetCandidateBySlugWithUnlockedRow, error) {
@@ -122,26 +191,27 @@ SELECT
   c.desired_role, c.english_level,
   c.expected_salary_min_cny, c.expected_salary_max_cny,
   c.availability_days, c.timezone, c.bc_experience, c.summary, c.rating,
-  (u.id IS NOT NULL) AS unlocked_contact
+  (u.id IS NOT NULL) AS unlocked_contact,
+  c.unlock_cost
 FROM candidates c
 LEFT JOIN unlocks u
   ON u.company_id = $1 AND u.candidate_id = c.id AND u.unlock_type = 'contact'
 WHERE c.public_slug = $2 AND c.status = 'active'
 LIMIT 1;`
     var r GetCandidateBySlugWithUnlockedRow
-    err := q.pool.QueryRow(ctx, sql, p.CompanyID, p.PublicSlug).Scan(
+    err := q.db.QueryRow(ctx, sql, p.CompanyID, p.PublicSlug).Scan(
         &r.ID, &r.PublicSlug, &r.DisplayName,
         &r.DesiredRole, &r.EnglishLevel,
         &r.ExpectedSalaryMinCny, &r.ExpectedSalaryMaxCny,
         &r.AvailabilityDays, &r.Timezone, &r.BcExperience, &r.Summary, &r.Rating,
-        &r.UnlockedContact,
+        &r.UnlockedContact, &r.UnlockCost,
     )
     return r, err
 }
 
 func (q *Queries) GetCandidateIDBySlug(ctx context.Context, slug string) (int64, error) {
     var id int64
-    err := q.pool.QueryRow(ctx, `SELECT id FROM candidates WHERE public_slug=$1 AND status='active' LIMIT 1`, slug).Scan(&id)
+    err := q.db.QueryRow(ctx, `SELECT id FROM candidates WHERE public_slug=$1 AND status='active' LIMIT 1`, slug).Scan(&id)
     return id, err
 }
 
@@ -157,7 +227,7 @@ FROM candidate_skills cs
 JOIN skills s ON s.id = cs.skill_id
 WHERE cs.candidate_id = ANY($1::bigint[])
 ORDER BY cs.candidate_id;`
-    rows, err := q.pool.Query(ctx, sql, ids)
+    rows, err := q.db.Query(ctx, sql, ids)
     if err != nil { return nil, err }
     defer rows.Close()
     out := make([]ListSkillsByCandidateIDsRow, 0)
@@ -185,7 +255,7 @@ FROM candidate_contacts
 WHERE candidate_id = $1
 LIMIT 1;`
     var r GetCandidateContactByIDRow
-    err := q.pool.QueryRow(ctx, sql, candidateID).Scan(&r.TgUsername, &r.Email, &r.Phone)
+    err := q.db.QueryRow(ctx, sql, candidateID).Scan(&r.TgUsername, &r.Email, &r.Phone)
     return r, err
 }
 
@@ -193,17 +263,19 @@ type UnlockCandidateContactIdempotentParams struct {
     CompanyID   int64
     HrUserID    int64
     CandidateID int64
+    // Cost in credits, recorded on the unlock
+    Cost int32
 }
 
 func (q *Queries) UnlockCandidateContactIdempotent(ctx context.Context, p UnlockCandidateContactIdempotentParams) (int64, error) {
     // returns unlock id if inserted; if already exists -> ErrNoRows
     sql := `
 INSERT INTO unlocks(company_id, hr_user_id, candidate_id, unlock_type, cost)
-VALUES ($1,$2,$3,'contact',1)
+VALUES ($1,$2,$3,'contact',$4)
 ON CONFLICT (company_id, candidate_id, unlock_type) DO NOTHING
 RETURNING id;`
     var id int64
-    err := q.pool.QueryRow(ctx, sql, p.CompanyID, p.HrUserID, p.CandidateID).Scan(&id)
+    err := q.db.QueryRow(ctx, sql, p.CompanyID, p.HrUserID, p.CandidateID, p.Cost).Scan(&id)
     return id, err
 }
 
@@ -211,6 +283,9 @@ type CompanyQuotaRow struct {
     CompanyID        int64
     UnlockQuotaTotal int32
     UnlockQuotaUsed  int32
+    PeriodStart      pgtype.Date
+    PeriodEnd        pgtype.Date
+    CreditBalance    int32
 }
 
 type CompanyRow struct {
@@ -225,35 +300,36 @@ type CompanyQuotaDetailRow struct {
     UnlockQuotaUsed  int32
     PeriodStart      pgtype.Date
     PeriodEnd        pgtype.Date
+    CreditBalance    int32
 }
 
 func (q *Queries) LockCompanyQuota(ctx context.Context, companyID int64) (CompanyQuotaRow, error) {
     sql := `
-SELECT company_id, unlock_quota_total, unlock_quota_used
+SELECT company_id, unlock_quota_total, unlock_quota_used, period_start, period_end, credit_balance
 FROM company_quotas
 WHERE company_id=$1
 FOR UPDATE;`
     var r CompanyQuotaRow
-    err := q.pool.QueryRow(ctx, sql, companyID).Scan(&r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed)
+    err := q.db.QueryRow(ctx, sql, companyID).Scan(&r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd, &r.CreditBalance)
     return r, err
 }
 
 func (q *Queries) GetCompanyByID(ctx context.Context, companyID int64) (CompanyRow, error) {
     sql := `SELECT id, name, status FROM companies WHERE id = $1 LIMIT 1;`
     var r CompanyRow
-    err := q.pool.QueryRow(ctx, sql, companyID).Scan(&r.ID, &r.Name, &r.Status)
+    err := q.db.QueryRow(ctx, sql, companyID).Scan(&r.ID, &r.Name, &r.Status)
     return r, err
 }
 
 func (q *Queries) GetCompanyQuotaDetail(ctx context.Context, companyID int64) (CompanyQuotaDetailRow, error) {
     sql := `
-SELECT company_id, unlock_quota_total, unlock_quota_used, period_start, period_end
+SELECT company_id, unlock_quota_total, unlock_quota_used, period_start, period_end, credit_balance
 FROM company_quotas
 WHERE company_id = $1
 LIMIT 1;`
     var r CompanyQuotaDetailRow
-    err := q.pool.QueryRow(ctx, sql, companyID).Scan(
-        &r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd,
+    err := q.db.QueryRow(ctx, sql, companyID).Scan(
+        &r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd, &r.CreditBalance,
     )
     return r, err
 }
@@ -264,31 +340,54 @@ type IncrementCompanyQuotaUsedParams struct {
 }
 
 func (q *Queries) IncrementCompanyQuotaUsed(ctx context.Context, p IncrementCompanyQuotaUsedParams) error {
-    _, err := q.pool.Exec(ctx, `UPDATE company_quotas SET unlock_quota_used = unlock_quota_used + $2, updated_at=now() WHERE company_id=$1`, p.CompanyID, p.Delta)
+    _, err := q.db.Exec(ctx, `UPDATE company_quotas SET unlock_quota_used = unlock_quota_used + $2, updated_at=now() WHERE company_id=$1`, p.CompanyID, p.Delta)
     return err
 }
 
-// WithTx provides a minimal "sqlc-like" API.
-// In this runnable template, we keep it simple by returning a new Queries bound to the same pool;
-// the repo uses pgx tx directly, so WithTx isn't needed here.
-func (q *Queries) WithTx(_ any) *Queries { return q }
+// WithTx returns a Queries bound to tx, matching the sqlc-generated API.
+func (q *Queries) WithTx(tx pgx.Tx) *Queries { return &Queries{db: tx} }
 
 // ==================== HR Users ====================
 
 type FindHRUserByTelegramIDRow struct {
-    ID        int64
-    CompanyID int64
-    Status    string
-    Role      string
+    ID             int64
+    CompanyID      int64
+    Status         string
+    Role           string
+    SessionVersion int32
 }
 
 func (q *Queries) FindHRUserByTelegramID(ctx context.Context, tgUserID int64) (FindHRUserByTelegramIDRow, error) {
-    sql := `SELECT id, company_id, status, role FROM hr_users WHERE tg_user_id = $1 LIMIT 1;`
+    sql := `SELECT id, company_id, status, role, session_version FROM hr_users WHERE tg_user_id = $1 LIMIT 1;`
     var r FindHRUserByTelegramIDRow
-    err := q.pool.QueryRow(ctx, sql, tgUserID).Scan(&r.ID, &r.CompanyID, &r.Status, &r.Role)
+    err := q.db.QueryRow(ctx, sql, tgUserID).Scan(&r.ID, &r.CompanyID, &r.Status, &r.Role, &r.SessionVersion)
+    return r, err
+}
+
+type GetHRUserStateRow struct {
+    ID             int64
+    CompanyID      int64
+    Status         string
+    Role           string
+    SessionVersion int32
+}
+
+func (q *Queries) GetHRUserState(ctx context.Context, id int64) (GetHRUserStateRow, error) {
+    sql := `SELECT id, company_id, status, role, session_version FROM hr_users WHERE id = $1 LIMIT 1;`
+    var r GetHRUserStateRow
+    err := q.db.QueryRow(ctx, sql, id).Scan(&r.ID, &r.CompanyID, &r.Status, &r.Role, &r.SessionVersion)
     return r, err
 }
 
+// BumpHRUserSessionVersion invalidates every token issued to the user so far.
+func (q *Queries) BumpHRUserSessionVersion(ctx context.Context, id int64) (int32, error) {
+    var v int32
+    err := q.db.QueryRow(ctx,
+        `UPDATE hr_users SET session_version = session_version + 1, updated_at = now() WHERE id = $1 RETURNING session_version`,
+        id).Scan(&v)
+    return v, err
+}
+
 type GetHRUserByIDRow struct {
     ID          int64
     CompanyID   int64
@@ -301,7 +400,7 @@ type GetHRUserByIDRow struct {
 func (q *Queries) GetHRUserByID(ctx context.Context, id int64) (GetHRUserByIDRow, error) {
     sql := `SELECT id, company_id, status, role, display_name, tg_username FROM hr_users WHERE id = $1 LIMIT 1;`
     var r GetHRUserByIDRow
-    err := q.pool.QueryRow(ctx, sql, id).Scan(&r.ID, &r.CompanyID, &r.Status, &r.Role, &r.DisplayName, &r.TgUsername)
+    err := q.db.QueryRow(ctx, sql, id).Scan(&r.ID, &r.CompanyID, &r.Status, &r.Role, &r.DisplayName, &r.TgUsername)
     return r, err
 }
 
@@ -318,22 +417,32 @@ func (q *Queries) CreateHRUser(ctx context.Context, p CreateHRUserParams) (int64
     sql := `INSERT INTO hr_users (company_id, tg_user_id, tg_username, display_name, role, status)
             VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`
     var id int64
-    err := q.pool.QueryRow(ctx, sql, p.CompanyID, p.TgUserID, p.TgUsername, p.DisplayName, p.Role, p.Status).Scan(&id)
+    err := q.db.QueryRow(ctx, sql, p.CompanyID, p.TgUserID, p.TgUsername, p.DisplayName, p.Role, p.Status).Scan(&id)
     return id, err
 }
 
 func (q *Queries) CreateDefaultCompany(ctx context.Context) (int64, error) {
     sql := `INSERT INTO companies (name, status) VALUES (gen_random_uuid()::text, 'active') RETURNING id;`
     var id int64
-    err := q.pool.QueryRow(ctx, sql).Scan(&id)
+    err := q.db.QueryRow(ctx, sql).Scan(&id)
     return id, err
 }
 
+// CreateCompanyQuotaIfNotExists starts the company's first period with its plan's allowance,
+// granted as credits.
 func (q *Queries) CreateCompanyQuotaIfNotExists(ctx context.Context, companyID int64) error {
-    _, err := q.pool.Exec(ctx, `
-INSERT INTO company_quotas (company_id)
-VALUES ($1)
-ON CONFLICT (company_id) DO NOTHING;`, companyID)
+    _, err := q.db.Exec(ctx, `
+WITH created AS (
+  INSERT INTO company_quotas (company_id, unlock_quota_total, credit_balance)
+  SELECT $1, a.total, a.total
+  FROM (SELECT COALESCE((SELECT unlock_quota_monthly FROM plans WHERE id = company_plan_id($1, CURRENT_DATE)), 20) AS total) a
+  ON CONFLICT (company_id) DO NOTHING
+  RETURNING company_id, credit_balance
+)
+INSERT INTO credit_ledger (company_id, kind, amount, balance_after, note)
+SELECT company_id, 'grant', credit_balance, credit_balance, 'period allowance'
+FROM created
+WHERE credit_balance > 0;`, companyID)
     return err
 }
 
@@ -343,10 +452,135 @@ type UpdateHRUserStatusParams struct {
 }
 
 func (q *Queries) UpdateHRUserStatus(ctx context.Context, p UpdateHRUserStatusParams) error {
-    _, err := q.pool.Exec(ctx, `UPDATE hr_users SET status = $2, updated_at = now() WHERE id = $1`, p.ID, p.Status)
+    _, err := q.db.Exec(ctx, `UPDATE hr_users SET status = $2, updated_at = now() WHERE id = $1`, p.ID, p.Status)
     return err
 }
 
+type CompanyMemberRow struct {
+    ID          int64
+    TgUserID    pgtype.Int8
+    TgUsername  pgtype.Text
+    DisplayName pgtype.Text
+    Role        string
+    Status      string
+    CreatedAt   pgtype.Timestamptz
+}
+
+// ListCompanyMembers returns every non-removed HR user of a company.
+func (q *Queries) ListCompanyMembers(ctx context.Context, companyID int64) ([]CompanyMemberRow, error) {
+    sql := `SELECT id, tg_user_id, tg_username, display_name, role, status, created_at
+            FROM hr_users
+            WHERE company_id = $1 AND status <> 'removed'
+            ORDER BY created_at ASC;`
+    rows, err := q.db.Query(ctx, sql, companyID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    out := make([]CompanyMemberRow, 0)
+    for rows.Next() {
+        var r CompanyMemberRow
+        if err := rows.Scan(&r.ID, &r.TgUserID, &r.TgUsername, &r.DisplayName, &r.Role, &r.Status, &r.CreatedAt); err != nil {
+            return nil, err
+        }
+        out = append(out, r)
+    }
+    return out, rows.Err()
+}
+
+type LockCompanyMemberParams struct {
+    ID        int64
+    CompanyID int64
+}
+
+type LockCompanyMemberRow struct {
+    ID     int64
+    Role   string
+    Status string
+}
+
+// LockCompanyMember locks one member row scoped to the company (ErrNoRows if not a member).
+func (q *Queries) LockCompanyMember(ctx context.Context, p LockCompanyMemberParams) (LockCompanyMemberRow, error) {
+    sql := `SELECT id, role, status FROM hr_users
+            WHERE id = $1 AND company_id = $2 AND status <> 'removed'
+            FOR UPDATE;`
+    var r LockCompanyMemberRow
+    err := q.db.QueryRow(ctx, sql, p.ID, p.CompanyID).Scan(&r.ID, &r.Role, &r.Status)
+    return r, err
+}
+
+// LockCompanyForUpdate serializes membership changes within one company.
+func (q *Queries) LockCompanyForUpdate(ctx context.Context, companyID int64) error {
+    var id int64
+    return q.db.QueryRow(ctx, `SELECT id FROM companies WHERE id = $1 FOR UPDATE`, companyID).Scan(&id)
+}
+
+// LockHRUserForUpdate serializes per-user limits such as CreateSavedSearch's.
+func (q *Queries) LockHRUserForUpdate(ctx context.Context, id int64) error {
+    var locked int64
+    return q.db.QueryRow(ctx, `SELECT id FROM hr_users WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+}
+
+func (q *Queries) CountActiveCompanyOwners(ctx context.Context, companyID int64) (int64, error) {
+    var n int64
+    err := q.db.QueryRow(ctx,
+        `SELECT count(*) FROM hr_users WHERE company_id = $1 AND role = 'owner' AND status = 'active'`,
+        companyID).Scan(&n)
+    return n, err
+}
+
+type UpdateHRUserRoleParams struct {
+    ID   int64
+    Role string
+}
+
+func (q *Queries) UpdateHRUserRole(ctx context.Context, p UpdateHRUserRoleParams) error {
+    _, err := q.db.Exec(ctx, `UPDATE hr_users SET role = $2, updated_at = now() WHERE id = $1`, p.ID, p.Role)
+    return err
+}
+
+// RemoveHRUser detaches the Telegram account (so it can log in fresh or accept another invite)
+// but keeps the row, since unlocks/audit logs reference hr_users.id.
+func (q *Queries) RemoveHRUser(ctx context.Context, id int64) error {
+    _, err := q.db.Exec(ctx, `UPDATE hr_users SET status = 'removed', tg_user_id = NULL, session_version = session_version + 1, updated_at = now() WHERE id = $1`, id)
+    return err
+}
+
+type IsSoleMemberOfEmptyCompanyParams struct {
+    CompanyID int64
+    HrUserID  int64
+}
+
+// IsSoleMemberOfEmptyCompany reports whether HrUserID is the company's only member and the
+// company has nothing to lose: no unlocks and no paid credits.
+func (q *Queries) IsSoleMemberOfEmptyCompany(ctx context.Context, p IsSoleMemberOfEmptyCompanyParams) (bool, error) {
+    sql := `SELECT NOT EXISTS (SELECT 1 FROM hr_users WHERE company_id = $1 AND id <> $2 AND status <> 'removed')
+               AND NOT EXISTS (SELECT 1 FROM unlocks WHERE company_id = $1)
+               AND NOT EXISTS (SELECT 1 FROM payments WHERE company_id = $1 AND status = 'succeeded');`
+    var ok bool
+    err := q.db.QueryRow(ctx, sql, p.CompanyID, p.HrUserID).Scan(&ok)
+    return ok, err
+}
+
+type MoveHRUserToCompanyParams struct {
+    ID        int64
+    CompanyID int64
+    Role      string
+}
+
+// MoveHRUserToCompany makes the user an active member of another company, ending the
+// sessions of the old membership. Returns the new session_version.
+func (q *Queries) MoveHRUserToCompany(ctx context.Context, p MoveHRUserToCompanyParams) (int32, error) {
+    sql := `UPDATE hr_users
+            SET company_id = $2, role = $3, status = 'active', session_version = session_version + 1, updated_at = now()
+            WHERE id = $1
+            RETURNING session_version;`
+    var v int32
+    err := q.db.QueryRow(ctx, sql, p.ID, p.CompanyID, p.Role).Scan(&v)
+    return v, err
+}
+
 // ==================== Audit Logs ====================
 
 type InsertAuditLogParams struct {
@@ -359,7 +593,7 @@ type InsertAuditLogParams struct {
 }
 
 func (q *Queries) InsertAuditLog(ctx context.Context, p InsertAuditLogParams) error {
-    _, err := q.pool.Exec(ctx,
+    _, err := q.db.Exec(ctx,
         `INSERT INTO audit_logs (company_id, hr_user_id, action, target_type, target_id, meta, created_at)
          VALUES ($1, $2, $3, $4, $5, $6, now())`,
         p.CompanyID, p.HrUserID, p.Action, p.TargetType, p.TargetID, p.Meta)
@@ -389,7 +623,7 @@ func (q *Queries) GetAuditLogs(ctx context.Context, p GetAuditLogsParams) ([]Get
             WHERE company_id = $1
             ORDER BY created_at DESC
             LIMIT $2 OFFSET $3;`
-    rows, err := q.pool.Query(ctx, sql, p.CompanyID, p.Limit, p.Offset)
+    rows, err := q.db.Query(ctx, sql, p.CompanyID, p.Limit, p.Offset)
     if err != nil {
         return nil, err
     }
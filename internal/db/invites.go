@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Company Invites ====================
+
+type CompanyInviteRow struct {
+	ID        int64
+	CompanyID int64
+	Code      string
+	Role      string
+	MaxUses   int32
+	UsedCount int32
+	CreatedBy pgtype.Int8
+	ExpiresAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+const companyInviteColumns = `id, company_id, code, role, max_uses, used_count, created_by, expires_at, revoked_at, created_at`
+
+func scanCompanyInvite(row interface{ Scan(dest ...any) error }) (CompanyInviteRow, error) {
+	var r CompanyInviteRow
+	err := row.Scan(&r.ID, &r.CompanyID, &r.Code, &r.Role, &r.MaxUses, &r.UsedCount, &r.CreatedBy, &r.ExpiresAt, &r.RevokedAt, &r.CreatedAt)
+	return r, err
+}
+
+type CreateCompanyInviteParams struct {
+	CompanyID int64
+	Code      string
+	Role      string
+	MaxUses   int32
+	CreatedBy int64
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateCompanyInvite(ctx context.Context, p CreateCompanyInviteParams) (CompanyInviteRow, error) {
+	sql := `INSERT INTO company_invites (company_id, code, role, max_uses, created_by, expires_at)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING ` + companyInviteColumns + `;`
+	return scanCompanyInvite(q.db.QueryRow(ctx, sql, p.CompanyID, p.Code, p.Role, p.MaxUses, p.CreatedBy, p.ExpiresAt))
+}
+
+type ListCompanyInvitesParams struct {
+	CompanyID int64
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListCompanyInvites(ctx context.Context, p ListCompanyInvitesParams) ([]CompanyInviteRow, error) {
+	sql := `SELECT ` + companyInviteColumns + `
+            FROM company_invites
+            WHERE company_id = $1
+            ORDER BY created_at DESC
+            LIMIT $2 OFFSET $3;`
+	rows, err := q.db.Query(ctx, sql, p.CompanyID, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]CompanyInviteRow, 0)
+	for rows.Next() {
+		r, err := scanCompanyInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type RevokeCompanyInviteParams struct {
+	ID        int64
+	CompanyID int64
+}
+
+// RevokeCompanyInvite returns the number of affected rows (0 if not found or already revoked).
+func (q *Queries) RevokeCompanyInvite(ctx context.Context, p RevokeCompanyInviteParams) (int64, error) {
+	tag, err := q.db.Exec(ctx,
+		`UPDATE company_invites SET revoked_at = now()
+         WHERE id = $1 AND company_id = $2 AND revoked_at IS NULL`,
+		p.ID, p.CompanyID)
+	return tag.RowsAffected(), err
+}
+
+type UpdateCompanyInviteExpiryParams struct {
+	ID        int64
+	CompanyID int64
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpdateCompanyInviteExpiry(ctx context.Context, p UpdateCompanyInviteExpiryParams) (int64, error) {
+	tag, err := q.db.Exec(ctx,
+		`UPDATE company_invites SET expires_at = $3
+         WHERE id = $1 AND company_id = $2 AND revoked_at IS NULL`,
+		p.ID, p.CompanyID, p.ExpiresAt)
+	return tag.RowsAffected(), err
+}
+
+type LockRedeemableInviteByCodeRow struct {
+	ID        int64
+	CompanyID int64
+	Role      string
+}
+
+// LockRedeemableInviteByCode must run inside a transaction; returns ErrNoRows when the
+// code is unknown, revoked, expired or used up.
+func (q *Queries) LockRedeemableInviteByCode(ctx context.Context, code string) (LockRedeemableInviteByCodeRow, error) {
+	sql := `
+SELECT id, company_id, role
+FROM company_invites
+WHERE code = $1
+  AND revoked_at IS NULL
+  AND expires_at > now()
+  AND used_count < max_uses
+FOR UPDATE;`
+	var r LockRedeemableInviteByCodeRow
+	err := q.db.QueryRow(ctx, sql, code).Scan(&r.ID, &r.CompanyID, &r.Role)
+	return r, err
+}
+
+func (q *Queries) MarkCompanyInviteUsed(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, `UPDATE company_invites SET used_count = used_count + 1, last_used_at = now() WHERE id = $1`, id)
+	return err
+}
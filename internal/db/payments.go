@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Payments ====================
+
+type CreditPackRow struct {
+	ID         int64
+	Code       string
+	Name       string
+	Credits    int32
+	PriceMinor int32
+	Currency   string
+	PriceStars int32
+}
+
+const creditPackColumns = `id, code, name, credits, price_minor, currency, price_stars`
+
+func scanCreditPack(row interface{ Scan(...any) error }) (CreditPackRow, error) {
+	var r CreditPackRow
+	err := row.Scan(&r.ID, &r.Code, &r.Name, &r.Credits, &r.PriceMinor, &r.Currency, &r.PriceStars)
+	return r, err
+}
+
+// ListCreditPacks returns the packs on sale, smallest first.
+func (q *Queries) ListCreditPacks(ctx context.Context) ([]CreditPackRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT `+creditPackColumns+`
+FROM credit_packs
+WHERE active
+ORDER BY credits, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]CreditPackRow, 0)
+	for rows.Next() {
+		r, err := scanCreditPack(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// GetCreditPack returns an active pack; ErrNoRows for unknown or retired packs.
+func (q *Queries) GetCreditPack(ctx context.Context, id int64) (CreditPackRow, error) {
+	return scanCreditPack(q.db.QueryRow(ctx, `
+SELECT `+creditPackColumns+`
+FROM credit_packs
+WHERE id = $1 AND active`, id))
+}
+
+type PaymentRow struct {
+	ID            int64
+	CompanyID     int64
+	HrUserID      pgtype.Int8
+	PackID        int64
+	PackCode      string
+	Provider      string
+	Credits       int32
+	Amount        int32
+	Currency      string
+	Status        string
+	ProviderRef   pgtype.Text
+	CheckoutURL   string
+	FailureReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	PaidAt        pgtype.Timestamptz
+}
+
+const paymentColumns = `
+  p.id, p.company_id, p.hr_user_id, p.pack_id, k.code, p.provider, p.credits, p.amount, p.currency,
+  p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at`
+
+// paymentJoins follows "FROM payments p" (or a CTE named p returning its rows)
+const paymentJoins = `
+JOIN credit_packs k ON k.id = p.pack_id`
+
+func scanPayment(row interface{ Scan(...any) error }) (PaymentRow, error) {
+	var r PaymentRow
+	err := row.Scan(&r.ID, &r.CompanyID, &r.HrUserID, &r.PackID, &r.PackCode, &r.Provider, &r.Credits, &r.Amount,
+		&r.Currency, &r.Status, &r.ProviderRef, &r.CheckoutURL, &r.FailureReason, &r.CreatedAt, &r.UpdatedAt, &r.PaidAt)
+	return r, err
+}
+
+type CreatePaymentParams struct {
+	CompanyID int64
+	HrUserID  int64
+	PackID    int64
+	Provider  string
+	Credits   int32
+	Amount    int32
+	Currency  string
+}
+
+// CreatePayment starts a pending payment; the checkout is attached with SetPaymentCheckout.
+func (q *Queries) CreatePayment(ctx context.Context, p CreatePaymentParams) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+WITH p AS (
+  INSERT INTO payments (company_id, hr_user_id, pack_id, provider, credits, amount, currency)
+  VALUES ($1, $2, $3, $4, $5, $6, $7)
+  RETURNING *
+)
+SELECT`+paymentColumns+`
+FROM p`+paymentJoins, p.CompanyID, p.HrUserID, p.PackID, p.Provider, p.Credits, p.Amount, p.Currency))
+}
+
+type SetPaymentCheckoutParams struct {
+	ID          int64
+	ProviderRef *string
+	CheckoutURL string
+}
+
+func (q *Queries) SetPaymentCheckout(ctx context.Context, p SetPaymentCheckoutParams) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+WITH p AS (
+  UPDATE payments
+  SET provider_ref = $2, checkout_url = $3, updated_at = now()
+  WHERE id = $1
+  RETURNING *
+)
+SELECT`+paymentColumns+`
+FROM p`+paymentJoins, p.ID, p.ProviderRef, p.CheckoutURL))
+}
+
+func (q *Queries) GetPayment(ctx context.Context, id int64) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+SELECT`+paymentColumns+`
+FROM payments p`+paymentJoins+`
+WHERE p.id = $1`, id))
+}
+
+type GetPaymentByProviderRefParams struct {
+	Provider    string
+	ProviderRef string
+}
+
+func (q *Queries) GetPaymentByProviderRef(ctx context.Context, p GetPaymentByProviderRefParams) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+SELECT`+paymentColumns+`
+FROM payments p`+paymentJoins+`
+WHERE p.provider = $1 AND p.provider_ref = $2`, p.Provider, p.ProviderRef))
+}
+
+type GetCompanyPaymentParams struct {
+	CompanyID int64
+	ID        int64
+}
+
+func (q *Queries) GetCompanyPayment(ctx context.Context, p GetCompanyPaymentParams) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+SELECT`+paymentColumns+`
+FROM payments p`+paymentJoins+`
+WHERE p.company_id = $1 AND p.id = $2`, p.CompanyID, p.ID))
+}
+
+// LockPayment re-reads a payment FOR UPDATE inside a transaction.
+func (q *Queries) LockPayment(ctx context.Context, id int64) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+SELECT`+paymentColumns+`
+FROM payments p`+paymentJoins+`
+WHERE p.id = $1
+FOR UPDATE OF p`, id))
+}
+
+type MarkPaymentSucceededParams struct {
+	ID int64
+	// ProviderRef replaces the stored reference when set (Telegram only names the charge
+	// once it succeeded)
+	ProviderRef *string
+}
+
+func (q *Queries) MarkPaymentSucceeded(ctx context.Context, p MarkPaymentSucceededParams) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+WITH p AS (
+  UPDATE payments
+  SET status = 'succeeded', provider_ref = COALESCE($2, provider_ref), failure_reason = '',
+      paid_at = now(), updated_at = now()
+  WHERE id = $1
+  RETURNING *
+)
+SELECT`+paymentColumns+`
+FROM p`+paymentJoins, p.ID, p.ProviderRef))
+}
+
+type MarkPaymentFailedParams struct {
+	ID     int64
+	Reason string
+}
+
+// MarkPaymentFailed fails a pending payment; ErrNoRows if it is no longer pending.
+func (q *Queries) MarkPaymentFailed(ctx context.Context, p MarkPaymentFailedParams) (PaymentRow, error) {
+	return scanPayment(q.db.QueryRow(ctx, `
+WITH p AS (
+  UPDATE payments
+  SET status = 'failed', failure_reason = $2, updated_at = now()
+  WHERE id = $1 AND status = 'pending'
+  RETURNING *
+)
+SELECT`+paymentColumns+`
+FROM p`+paymentJoins, p.ID, p.Reason))
+}
+
+type ListCompanyPaymentsParams struct {
+	CompanyID int64
+	Limit     int32
+	Offset    int32
+}
+
+// ListCompanyPayments returns the company's payments, newest first.
+func (q *Queries) ListCompanyPayments(ctx context.Context, p ListCompanyPaymentsParams) ([]PaymentRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT`+paymentColumns+`
+FROM payments p`+paymentJoins+`
+WHERE p.company_id = $1
+ORDER BY p.id DESC
+LIMIT $2 OFFSET $3`, p.CompanyID, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]PaymentRow, 0)
+	for rows.Next() {
+		r, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type InsertPaymentEventParams struct {
+	Provider  string
+	EventID   string
+	PaymentID *int64
+	Type      string
+	Payload   []byte // JSON
+}
+
+// InsertPaymentEvent records a provider notification; ErrNoRows if it was recorded before.
+func (q *Queries) InsertPaymentEvent(ctx context.Context, p InsertPaymentEventParams) (int64, error) {
+	var id int64
+	err := q.db.QueryRow(ctx, `
+INSERT INTO payment_events (provider, event_id, payment_id, type, payload)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (provider, event_id) DO NOTHING
+RETURNING id`, p.Provider, p.EventID, p.PaymentID, p.Type, p.Payload).Scan(&id)
+	return id, err
+}
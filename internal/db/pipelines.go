@@ -0,0 +1,335 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Pipelines ====================
+
+type PipelineRow struct {
+	ID        int64
+	CompanyID int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+const pipelineColumns = `p.id, p.company_id, p.name, p.created_at, p.updated_at`
+
+func scanPipeline(row interface{ Scan(...any) error }) (PipelineRow, error) {
+	var r PipelineRow
+	err := row.Scan(&r.ID, &r.CompanyID, &r.Name, &r.CreatedAt, &r.UpdatedAt)
+	return r, err
+}
+
+type CreatePipelineParams struct {
+	CompanyID int64
+	Name      string
+	CreatedBy int64
+}
+
+func (q *Queries) CreatePipeline(ctx context.Context, p CreatePipelineParams) (PipelineRow, error) {
+	return scanPipeline(q.db.QueryRow(ctx, `
+INSERT INTO pipelines AS p (company_id, name, created_by)
+VALUES ($1, $2, $3)
+RETURNING `+pipelineColumns, p.CompanyID, p.Name, p.CreatedBy))
+}
+
+func (q *Queries) ListPipelines(ctx context.Context, companyID int64) ([]PipelineRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT `+pipelineColumns+`
+FROM pipelines p
+WHERE p.company_id = $1
+ORDER BY p.created_at, p.id`, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]PipelineRow, 0)
+	for rows.Next() {
+		r, err := scanPipeline(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type GetPipelineParams struct {
+	ID        int64
+	CompanyID int64
+}
+
+func (q *Queries) GetPipeline(ctx context.Context, p GetPipelineParams) (PipelineRow, error) {
+	return scanPipeline(q.db.QueryRow(ctx, `
+SELECT `+pipelineColumns+`
+FROM pipelines p
+WHERE p.id = $1 AND p.company_id = $2`, p.ID, p.CompanyID))
+}
+
+// LockPipeline re-reads a pipeline FOR UPDATE inside a transaction; it serializes stage
+// changes of the same pipeline.
+func (q *Queries) LockPipeline(ctx context.Context, p GetPipelineParams) (PipelineRow, error) {
+	return scanPipeline(q.db.QueryRow(ctx, `
+SELECT `+pipelineColumns+`
+FROM pipelines p
+WHERE p.id = $1 AND p.company_id = $2
+FOR UPDATE`, p.ID, p.CompanyID))
+}
+
+type RenamePipelineParams struct {
+	ID        int64
+	CompanyID int64
+	Name      string
+}
+
+func (q *Queries) RenamePipeline(ctx context.Context, p RenamePipelineParams) (PipelineRow, error) {
+	return scanPipeline(q.db.QueryRow(ctx, `
+UPDATE pipelines p
+SET name = $3, updated_at = now()
+WHERE p.id = $1 AND p.company_id = $2
+RETURNING `+pipelineColumns, p.ID, p.CompanyID, p.Name))
+}
+
+func (q *Queries) TouchPipeline(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, `UPDATE pipelines SET updated_at = now() WHERE id = $1`, id)
+	return err
+}
+
+type DeletePipelineParams struct {
+	ID        int64
+	CompanyID int64
+}
+
+// DeletePipeline returns the number of deleted rows (0 if not found); stages and
+// memberships are removed with it.
+func (q *Queries) DeletePipeline(ctx context.Context, p DeletePipelineParams) (int64, error) {
+	tag, err := q.db.Exec(ctx, `DELETE FROM pipelines WHERE id = $1 AND company_id = $2`, p.ID, p.CompanyID)
+	return tag.RowsAffected(), err
+}
+
+// ==================== Pipeline Stages ====================
+
+type PipelineStageRow struct {
+	ID             int64
+	PipelineID     int64
+	Name           string
+	Position       int32
+	CandidateCount int32
+}
+
+type CreatePipelineStageParams struct {
+	PipelineID int64
+	Name       string
+	Position   int32
+}
+
+func (q *Queries) CreatePipelineStage(ctx context.Context, p CreatePipelineStageParams) (int64, error) {
+	var id int64
+	err := q.db.QueryRow(ctx, `
+INSERT INTO pipeline_stages (pipeline_id, name, position)
+VALUES ($1, $2, $3)
+RETURNING id`, p.PipelineID, p.Name, p.Position).Scan(&id)
+	return id, err
+}
+
+// ListPipelineStages returns the stages of the given pipelines in order, with the number of
+// (non-deleted) candidates in each.
+func (q *Queries) ListPipelineStages(ctx context.Context, pipelineIDs []int64) ([]PipelineStageRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT ps.id, ps.pipeline_id, ps.name, ps.position,
+       (SELECT count(*)
+        FROM pipeline_candidates pc
+        JOIN candidates c ON c.id = pc.candidate_id
+        WHERE pc.stage_id = ps.id AND c.status <> 'deleted')::int
+FROM pipeline_stages ps
+WHERE ps.pipeline_id = ANY($1::bigint[])
+ORDER BY ps.pipeline_id, ps.position, ps.id`, pipelineIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]PipelineStageRow, 0)
+	for rows.Next() {
+		var r PipelineStageRow
+		if err := rows.Scan(&r.ID, &r.PipelineID, &r.Name, &r.Position, &r.CandidateCount); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type UpdatePipelineStageParams struct {
+	ID       int64
+	Name     string
+	Position int32
+}
+
+func (q *Queries) UpdatePipelineStage(ctx context.Context, p UpdatePipelineStageParams) error {
+	_, err := q.db.Exec(ctx, `UPDATE pipeline_stages SET name = $2, position = $3 WHERE id = $1`, p.ID, p.Name, p.Position)
+	return err
+}
+
+// PurgeDeletedPipelineStageCandidates drops deleted candidates from a stage, so they do
+// not keep it from being deleted.
+func (q *Queries) PurgeDeletedPipelineStageCandidates(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, `
+DELETE FROM pipeline_candidates pc
+USING candidates c
+WHERE pc.stage_id = $1 AND c.id = pc.candidate_id AND c.status = 'deleted'`, id)
+	return err
+}
+
+// DeleteEmptyPipelineStage deletes a stage no candidate is in; it returns 0 otherwise.
+func (q *Queries) DeleteEmptyPipelineStage(ctx context.Context, id int64) (int64, error) {
+	tag, err := q.db.Exec(ctx, `
+DELETE FROM pipeline_stages ps
+WHERE ps.id = $1
+  AND NOT EXISTS (SELECT 1 FROM pipeline_candidates pc WHERE pc.stage_id = ps.id)`, id)
+	return tag.RowsAffected(), err
+}
+
+// ==================== Pipeline Candidates ====================
+
+type MovePipelineCandidateParams struct {
+	PipelineID  int64
+	StageID     int64
+	CandidateID int64
+	HrUserID    int64
+}
+
+// MovePipelineCandidate adds the candidate to the stage, or moves them there if they are
+// already in the pipeline. It returns the previous stage (NULL when newly added), or
+// ErrNoRows when the stage is not one of the pipeline's.
+func (q *Queries) MovePipelineCandidate(ctx context.Context, p MovePipelineCandidateParams) (pgtype.Int8, error) {
+	var prev pgtype.Int8
+	err := q.db.QueryRow(ctx, `
+WITH prev AS (
+  SELECT stage_id FROM pipeline_candidates
+  WHERE pipeline_id = $1 AND candidate_id = $3
+)
+INSERT INTO pipeline_candidates (pipeline_id, candidate_id, stage_id, added_by)
+SELECT ps.pipeline_id, $3, ps.id, $4
+FROM pipeline_stages ps
+WHERE ps.id = $2 AND ps.pipeline_id = $1
+ON CONFLICT (pipeline_id, candidate_id) DO UPDATE
+SET stage_id = EXCLUDED.stage_id,
+    moved_at = CASE WHEN pipeline_candidates.stage_id = EXCLUDED.stage_id
+                    THEN pipeline_candidates.moved_at ELSE now() END
+RETURNING (SELECT stage_id FROM prev)`, p.PipelineID, p.StageID, p.CandidateID, p.HrUserID).Scan(&prev)
+	return prev, err
+}
+
+type RemovePipelineCandidateParams struct {
+	PipelineID int64
+	Slug       string
+}
+
+type RemovePipelineCandidateRow struct {
+	CandidateID int64
+	StageID     int64
+}
+
+// RemovePipelineCandidate takes a candidate (hidden ones included) out of the pipeline;
+// ErrNoRows if they were not in it.
+func (q *Queries) RemovePipelineCandidate(ctx context.Context, p RemovePipelineCandidateParams) (RemovePipelineCandidateRow, error) {
+	var r RemovePipelineCandidateRow
+	err := q.db.QueryRow(ctx, `
+DELETE FROM pipeline_candidates pc
+USING candidates c
+WHERE pc.pipeline_id = $1 AND c.id = pc.candidate_id AND c.public_slug = $2
+RETURNING pc.candidate_id, pc.stage_id`, p.PipelineID, p.Slug).Scan(&r.CandidateID, &r.StageID)
+	return r, err
+}
+
+type ListPipelineCandidatesRow struct {
+	PublicSlug      string
+	DisplayName     string
+	DesiredRole     pgtype.Text
+	UnlockedContact bool
+	StageID         int64
+	CreatedAt       time.Time
+	MovedAt         time.Time
+}
+
+type ListPipelineCandidatesParams struct {
+	PipelineID int64
+	CompanyID  int64
+}
+
+// ListPipelineCandidates lists the pipeline's candidates by stage order, most recently moved first.
+func (q *Queries) ListPipelineCandidates(ctx context.Context, p ListPipelineCandidatesParams) ([]ListPipelineCandidatesRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT c.public_slug, c.display_name, c.desired_role,
+       EXISTS (
+         SELECT 1 FROM unlocks u
+         WHERE u.company_id = $2 AND u.candidate_id = c.id AND u.unlock_type = 'contact'
+       ),
+       pc.stage_id, pc.created_at, pc.moved_at
+FROM pipeline_candidates pc
+JOIN pipeline_stages ps ON ps.id = pc.stage_id
+JOIN candidates c ON c.id = pc.candidate_id
+WHERE pc.pipeline_id = $1 AND c.status <> 'deleted'
+ORDER BY ps.position, ps.id, pc.moved_at DESC`, p.PipelineID, p.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]ListPipelineCandidatesRow, 0)
+	for rows.Next() {
+		var r ListPipelineCandidatesRow
+		if err := rows.Scan(&r.PublicSlug, &r.DisplayName, &r.DesiredRole, &r.UnlockedContact,
+			&r.StageID, &r.CreatedAt, &r.MovedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type ListCandidatePipelineStagesParams struct {
+	CompanyID    int64
+	CandidateIDs []int64
+}
+
+type ListCandidatePipelineStagesRow struct {
+	CandidateID  int64
+	PipelineID   int64
+	PipelineName string
+	StageID      int64
+	StageName    string
+}
+
+// ListCandidatePipelineStages returns the stages the candidates are in across the
+// company's pipelines (for candidate cards).
+func (q *Queries) ListCandidatePipelineStages(ctx context.Context, p ListCandidatePipelineStagesParams) ([]ListCandidatePipelineStagesRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT pc.candidate_id, p.id, p.name, ps.id, ps.name
+FROM pipeline_candidates pc
+JOIN pipelines p ON p.id = pc.pipeline_id
+JOIN pipeline_stages ps ON ps.id = pc.stage_id
+WHERE p.company_id = $1 AND pc.candidate_id = ANY($2::bigint[])
+ORDER BY pc.candidate_id, p.created_at, p.id`, p.CompanyID, p.CandidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]ListCandidatePipelineStagesRow, 0)
+	for rows.Next() {
+		var r ListCandidatePipelineStagesRow
+		if err := rows.Scan(&r.CandidateID, &r.PipelineID, &r.PipelineName, &r.StageID, &r.StageName); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
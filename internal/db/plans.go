@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Plans ====================
+
+type PlanRow struct {
+	ID                 int64
+	Code               string
+	Name               string
+	UnlockQuotaMonthly int32
+	SeatLimit          int32
+	Features           []byte
+	IsDefault          bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+const planColumns = `
+  p.id, p.code, p.name, p.unlock_quota_monthly, p.seat_limit, p.features, p.is_default,
+  p.created_at, p.updated_at`
+
+func scanPlan(row interface{ Scan(...any) error }, extra ...any) (PlanRow, error) {
+	var r PlanRow
+	dest := append([]any{&r.ID, &r.Code, &r.Name, &r.UnlockQuotaMonthly, &r.SeatLimit, &r.Features,
+		&r.IsDefault, &r.CreatedAt, &r.UpdatedAt}, extra...)
+	err := row.Scan(dest...)
+	return r, err
+}
+
+func (q *Queries) ListPlans(ctx context.Context) ([]PlanRow, error) {
+	rows, err := q.db.Query(ctx, `SELECT`+planColumns+` FROM plans p ORDER BY p.unlock_quota_monthly, p.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []PlanRow
+	for rows.Next() {
+		r, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (q *Queries) GetPlan(ctx context.Context, id int64) (PlanRow, error) {
+	return scanPlan(q.db.QueryRow(ctx, `SELECT`+planColumns+` FROM plans p WHERE p.id = $1`, id))
+}
+
+type CreatePlanParams struct {
+	Code               string
+	Name               string
+	UnlockQuotaMonthly int32
+	SeatLimit          int32
+	Features           []byte
+}
+
+// CreatePlan returns pgx.ErrNoRows if the code is taken.
+func (q *Queries) CreatePlan(ctx context.Context, p CreatePlanParams) (PlanRow, error) {
+	sql := `
+INSERT INTO plans AS p (code, name, unlock_quota_monthly, seat_limit, features)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (code) DO NOTHING
+RETURNING` + planColumns
+	return scanPlan(q.db.QueryRow(ctx, sql, p.Code, p.Name, p.UnlockQuotaMonthly, p.SeatLimit, p.Features))
+}
+
+// UpdatePlanParams: nil fields are left unchanged.
+type UpdatePlanParams struct {
+	ID                 int64
+	Name               *string
+	UnlockQuotaMonthly *int32
+	SeatLimit          *int32
+	Features           []byte
+}
+
+func (q *Queries) UpdatePlan(ctx context.Context, p UpdatePlanParams) (PlanRow, error) {
+	sql := `
+UPDATE plans p
+SET name                 = COALESCE($2::text, p.name),
+    unlock_quota_monthly = COALESCE($3::int, p.unlock_quota_monthly),
+    seat_limit           = COALESCE($4::int, p.seat_limit),
+    features             = COALESCE($5::jsonb, p.features),
+    updated_at = now()
+WHERE p.id = $1
+RETURNING` + planColumns
+	return scanPlan(q.db.QueryRow(ctx, sql, p.ID, p.Name, p.UnlockQuotaMonthly, p.SeatLimit, p.Features))
+}
+
+// SetDefaultPlan makes id the plan of companies without an assignment.
+func (q *Queries) SetDefaultPlan(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, `UPDATE plans SET is_default = (id = $1), updated_at = now() WHERE is_default OR id = $1`, id)
+	return err
+}
+
+type CompanyPlanRow struct {
+	PlanRow
+	// EffectiveFrom is invalid when the company falls back to the default plan
+	EffectiveFrom pgtype.Date
+}
+
+// GetCompanyPlan returns the plan in effect for the company today.
+func (q *Queries) GetCompanyPlan(ctx context.Context, companyID int64) (CompanyPlanRow, error) {
+	sql := `
+SELECT` + planColumns + `, cp.effective_from
+FROM plans p
+LEFT JOIN LATERAL (
+  SELECT effective_from FROM company_plans
+  WHERE company_id = $1 AND effective_from <= CURRENT_DATE
+  ORDER BY effective_from DESC
+  LIMIT 1
+) cp ON true
+WHERE p.id = company_plan_id($1, CURRENT_DATE)`
+	var r CompanyPlanRow
+	var err error
+	r.PlanRow, err = scanPlan(q.db.QueryRow(ctx, sql, companyID), &r.EffectiveFrom)
+	return r, err
+}
+
+type CompanyPlanAssignmentRow struct {
+	PlanID        int64
+	PlanCode      string
+	EffectiveFrom pgtype.Date
+	AssignedBy    pgtype.Int8
+	CreatedAt     time.Time
+}
+
+// ListCompanyPlanAssignments returns past and scheduled assignments, latest first.
+func (q *Queries) ListCompanyPlanAssignments(ctx context.Context, companyID int64) ([]CompanyPlanAssignmentRow, error) {
+	sql := `
+SELECT cp.plan_id, p.code, cp.effective_from, cp.assigned_by, cp.created_at
+FROM company_plans cp
+JOIN plans p ON p.id = cp.plan_id
+WHERE cp.company_id = $1
+ORDER BY cp.effective_from DESC`
+	rows, err := q.db.Query(ctx, sql, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CompanyPlanAssignmentRow
+	for rows.Next() {
+		var r CompanyPlanAssignmentRow
+		if err := rows.Scan(&r.PlanID, &r.PlanCode, &r.EffectiveFrom, &r.AssignedBy, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type AssignCompanyPlanParams struct {
+	CompanyID     int64
+	PlanID        int64
+	EffectiveFrom pgtype.Date
+	AssignedBy    int64
+}
+
+// AssignCompanyPlan schedules a plan from EffectiveFrom on, replacing an assignment on the same day.
+func (q *Queries) AssignCompanyPlan(ctx context.Context, p AssignCompanyPlanParams) error {
+	_, err := q.db.Exec(ctx, `
+INSERT INTO company_plans (company_id, plan_id, effective_from, assigned_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (company_id, effective_from) DO UPDATE
+SET plan_id = EXCLUDED.plan_id, assigned_by = EXCLUDED.assigned_by, created_at = now()`,
+		p.CompanyID, p.PlanID, p.EffectiveFrom, p.AssignedBy)
+	return err
+}
+
+// ApplyCompanyPlanQuota sets the current period's allowance to that of today's plan, keeping
+// the usage. Returns pgx.ErrNoRows if the company has no quota row.
+func (q *Queries) ApplyCompanyPlanQuota(ctx context.Context, companyID int64) (CompanyQuotaDetailRow, error) {
+	sql := `
+UPDATE company_quotas q
+SET unlock_quota_total = p.unlock_quota_monthly, updated_at = now()
+FROM plans p
+WHERE q.company_id = $1 AND p.id = company_plan_id($1, CURRENT_DATE)
+RETURNING q.company_id, q.unlock_quota_total, q.unlock_quota_used, q.period_start, q.period_end, q.credit_balance`
+	var r CompanyQuotaDetailRow
+	err := q.db.QueryRow(ctx, sql, companyID).Scan(
+		&r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd, &r.CreditBalance,
+	)
+	return r, err
+}
+
+type CompanySeatsRow struct {
+	// SeatLimit of today's plan; 0 = unlimited
+	SeatLimit   int32
+	ActiveSeats int64
+}
+
+// GetCompanySeats counts active HR users against the plan's seat limit.
+func (q *Queries) GetCompanySeats(ctx context.Context, companyID int64) (CompanySeatsRow, error) {
+	sql := `
+SELECT
+  COALESCE((SELECT seat_limit FROM plans WHERE id = company_plan_id($1, CURRENT_DATE)), 0),
+  (SELECT count(*) FROM hr_users WHERE company_id = $1 AND status = 'active')`
+	var r CompanySeatsRow
+	err := q.db.QueryRow(ctx, sql, companyID).Scan(&r.SeatLimit, &r.ActiveSeats)
+	return r, err
+}
@@ -0,0 +1,47 @@
+-- Platform admin (cross-tenant) queries
+
+-- name: AdminListCompanies :many
+SELECT
+  c.id, c.name, c.status, c.created_at,
+  (SELECT count(*) FROM hr_users h WHERE h.company_id = c.id AND h.status <> 'removed') AS member_count,
+  (q.company_id IS NOT NULL) AS quota_configured,
+  COALESCE(q.unlock_quota_total, 0) AS unlock_quota_total,
+  COALESCE(q.unlock_quota_used, 0) AS unlock_quota_used,
+  (SELECT code FROM plans WHERE id = company_plan_id(c.id, CURRENT_DATE)) AS plan_code
+FROM companies c
+LEFT JOIN company_quotas q ON q.company_id = c.id
+WHERE (sqlc.narg('status')::text IS NULL OR c.status = sqlc.narg('status'))
+  AND (sqlc.narg('q')::text IS NULL OR c.name ILIKE '%' || sqlc.narg('q') || '%')
+ORDER BY c.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateCompanyStatus :execrows
+UPDATE companies
+SET status = sqlc.arg('status'), updated_at = now()
+WHERE id = sqlc.arg('id');
+
+-- name: AdminListHRUsers :many
+SELECT h.id, h.company_id, c.name AS company_name, h.tg_username, h.display_name, h.role, h.status, h.created_at
+FROM hr_users h
+JOIN companies c ON c.id = h.company_id
+WHERE h.status <> 'removed'
+  AND (sqlc.narg('status')::text IS NULL OR h.status = sqlc.narg('status'))
+  AND (sqlc.narg('company_id')::bigint IS NULL OR h.company_id = sqlc.narg('company_id'))
+ORDER BY h.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: AdminUpsertCompanyQuota :one
+INSERT INTO company_quotas (company_id, unlock_quota_total, unlock_quota_used, period_end)
+VALUES (
+  sqlc.arg('company_id'),
+  COALESCE(sqlc.narg('unlock_quota_total')::int,
+           (SELECT unlock_quota_monthly FROM plans WHERE id = company_plan_id(sqlc.arg('company_id'), CURRENT_DATE)), 20),
+  COALESCE(sqlc.narg('unlock_quota_used')::int, 0),
+  COALESCE(sqlc.narg('period_end')::date, CURRENT_DATE + INTERVAL '30 days')
+)
+ON CONFLICT (company_id) DO UPDATE SET
+  unlock_quota_total = COALESCE(sqlc.narg('unlock_quota_total')::int, company_quotas.unlock_quota_total),
+  unlock_quota_used  = COALESCE(sqlc.narg('unlock_quota_used')::int, company_quotas.unlock_quota_used),
+  period_end         = COALESCE(sqlc.narg('period_end')::date, company_quotas.period_end),
+  updated_at = now()
+RETURNING company_id, unlock_quota_total, unlock_quota_used, period_start, period_end, credit_balance;
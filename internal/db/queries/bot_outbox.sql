@@ -0,0 +1,50 @@
+-- name: EnqueueUnlockNotification :exec
+INSERT INTO bot_outbox (chat_id, kind, payload)
+SELECT c.tg_user_id, sqlc.arg('kind'), jsonb_build_object(
+  'candidate_id', c.id,
+  'company_name', co.name,
+  'desired_role', COALESCE(c.desired_role, ''),
+  'timezone', COALESCE(c.timezone, '')
+)
+FROM candidates c
+JOIN companies co ON co.id = sqlc.arg('company_id')
+WHERE c.id = sqlc.arg('candidate_id')
+  AND c.notify_on_unlock
+  AND c.tg_user_id IS NOT NULL
+  AND c.status <> 'deleted';
+
+-- name: ClaimDueBotOutbox :many
+UPDATE bot_outbox
+SET attempts = attempts + 1,
+    next_attempt_at = now() + (sqlc.arg('lease_seconds')::int * interval '1 second')
+WHERE id IN (
+  SELECT id FROM bot_outbox
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY next_attempt_at
+  LIMIT sqlc.arg('limit')
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, chat_id, kind, payload, attempts, created_at;
+
+-- name: MarkBotOutboxSent :exec
+UPDATE bot_outbox
+SET status = 'sent', sent_at = now(), last_error = NULL
+WHERE id = sqlc.arg('id');
+
+-- name: RetryBotOutbox :exec
+UPDATE bot_outbox
+SET next_attempt_at = now() + (sqlc.arg('delay_seconds')::int * interval '1 second'),
+    last_error = sqlc.arg('last_error')
+WHERE id = sqlc.arg('id');
+
+-- name: FailBotOutbox :exec
+UPDATE bot_outbox
+SET status = 'failed', last_error = sqlc.arg('last_error')
+WHERE id = sqlc.arg('id');
+
+-- Preference only: updated_at is left alone so saved search digests don't report the
+-- candidate as updated.
+-- name: SetCandidateNotifyOnUnlock :exec
+UPDATE candidates
+SET notify_on_unlock = sqlc.arg('notify')
+WHERE id = sqlc.arg('id') AND status <> 'deleted';
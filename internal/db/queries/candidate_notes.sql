@@ -0,0 +1,66 @@
+-- name: CreateCandidateNote :one
+WITH n AS (
+  INSERT INTO candidate_notes (company_id, candidate_id, hr_user_id, body)
+  VALUES (sqlc.arg('company_id'), sqlc.arg('candidate_id'), sqlc.arg('hr_user_id'), sqlc.arg('body'))
+  RETURNING *
+)
+SELECT n.id, n.hr_user_id, COALESCE(h.display_name, h.tg_username, '') AS author_name,
+       n.body, n.created_at, n.updated_at
+FROM n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id;
+
+-- name: ListCandidateNotes :many
+SELECT n.id, n.hr_user_id, COALESCE(h.display_name, h.tg_username, '') AS author_name,
+       n.body, n.created_at, n.updated_at
+FROM candidate_notes n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id
+WHERE n.company_id = sqlc.arg('company_id') AND n.candidate_id = sqlc.arg('candidate_id')
+ORDER BY n.created_at DESC, n.id DESC;
+
+-- name: GetCandidateNote :one
+SELECT n.id, n.hr_user_id, COALESCE(h.display_name, h.tg_username, '') AS author_name,
+       n.body, n.created_at, n.updated_at
+FROM candidate_notes n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id
+WHERE n.id = sqlc.arg('id') AND n.company_id = sqlc.arg('company_id') AND n.candidate_id = sqlc.arg('candidate_id');
+
+-- name: UpdateCandidateNote :one
+WITH n AS (
+  UPDATE candidate_notes
+  SET body = sqlc.arg('body'), updated_at = now()
+  WHERE id = sqlc.arg('id') AND hr_user_id = sqlc.arg('hr_user_id')
+  RETURNING *
+)
+SELECT n.id, n.hr_user_id, COALESCE(h.display_name, h.tg_username, '') AS author_name,
+       n.body, n.created_at, n.updated_at
+FROM n
+LEFT JOIN hr_users h ON h.id = n.hr_user_id;
+
+-- name: DeleteCandidateNote :execrows
+DELETE FROM candidate_notes WHERE id = sqlc.arg('id') AND hr_user_id = sqlc.arg('hr_user_id');
+
+-- name: AddCandidateTag :execrows
+INSERT INTO candidate_tags (company_id, candidate_id, tag, created_by)
+SELECT sqlc.arg('company_id'), sqlc.arg('candidate_id'), sqlc.arg('tag'), sqlc.arg('created_by')
+WHERE (SELECT count(*) FROM candidate_tags
+       WHERE company_id = sqlc.arg('company_id') AND candidate_id = sqlc.arg('candidate_id')) < sqlc.arg('max_per_candidate')
+ON CONFLICT (company_id, candidate_id, lower(tag)) DO NOTHING;
+
+-- name: RemoveCandidateTag :execrows
+DELETE FROM candidate_tags
+WHERE company_id = sqlc.arg('company_id') AND candidate_id = sqlc.arg('candidate_id')
+  AND lower(tag) = lower(sqlc.arg('tag'));
+
+-- name: ListCandidateTags :many
+SELECT candidate_id, tag
+FROM candidate_tags
+WHERE company_id = sqlc.arg('company_id') AND candidate_id = ANY(sqlc.arg('candidate_ids')::bigint[])
+ORDER BY candidate_id, created_at, tag;
+
+-- name: ListCompanyTags :many
+SELECT min(ct.tag) AS tag, count(*)::int AS count
+FROM candidate_tags ct
+JOIN candidates c ON c.id = ct.candidate_id
+WHERE ct.company_id = sqlc.arg('company_id') AND c.status = 'active'
+GROUP BY lower(ct.tag)
+ORDER BY count(*) DESC, lower(ct.tag);
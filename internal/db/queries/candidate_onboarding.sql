@@ -0,0 +1,58 @@
+-- name: GetOnboardingSession :one
+SELECT chat_id, tg_user_id, step, data, updated_at
+FROM candidate_onboarding_sessions
+WHERE chat_id = sqlc.arg('chat_id');
+
+-- name: UpsertOnboardingSession :exec
+INSERT INTO candidate_onboarding_sessions (chat_id, tg_user_id, step, data)
+VALUES (sqlc.arg('chat_id'), sqlc.arg('tg_user_id'), sqlc.arg('step'), sqlc.arg('data'))
+ON CONFLICT (chat_id) DO UPDATE
+SET tg_user_id = EXCLUDED.tg_user_id, step = EXCLUDED.step, data = EXCLUDED.data, updated_at = now();
+
+-- name: DeleteOnboardingSession :exec
+DELETE FROM candidate_onboarding_sessions WHERE chat_id = sqlc.arg('chat_id');
+
+-- name: FindCandidateIDByTelegramID :one
+SELECT id FROM candidates
+WHERE tg_user_id = sqlc.arg('tg_user_id') AND status <> 'deleted'
+LIMIT 1;
+
+-- name: CreateOnboardedCandidate :one
+-- Returns no row if the Telegram account already has a candidate.
+INSERT INTO candidates (
+  public_slug, tg_user_id, display_name, desired_role, english_level,
+  expected_salary_min_cny, expected_salary_max_cny, availability_days, timezone,
+  contact_consent_at, status
+) VALUES (
+  sqlc.arg('public_slug'), sqlc.arg('tg_user_id'), sqlc.arg('display_name'), sqlc.arg('desired_role'), sqlc.arg('english_level'),
+  sqlc.arg('expected_salary_min_cny'), sqlc.arg('expected_salary_max_cny'), sqlc.arg('availability_days'), sqlc.arg('timezone'),
+  now(), 'active'
+)
+ON CONFLICT (tg_user_id) DO NOTHING
+RETURNING id;
+
+-- name: GetOrCreateSkillID :one
+-- Skills are matched case-insensitively so "golang" reuses an existing "Golang".
+WITH existing AS (
+  SELECT id FROM skills WHERE lower(name) = lower(sqlc.arg('name')) ORDER BY id LIMIT 1
+), inserted AS (
+  INSERT INTO skills (name)
+  SELECT sqlc.arg('name') WHERE NOT EXISTS (SELECT 1 FROM existing)
+  ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+  RETURNING id
+)
+SELECT id FROM existing
+UNION ALL
+SELECT id FROM inserted
+LIMIT 1;
+
+-- name: AddCandidateSkill :exec
+INSERT INTO candidate_skills (candidate_id, skill_id)
+VALUES (sqlc.arg('candidate_id'), sqlc.arg('skill_id'))
+ON CONFLICT DO NOTHING;
+
+-- name: UpsertCandidateTelegramContact :exec
+INSERT INTO candidate_contacts (candidate_id, tg_username)
+VALUES (sqlc.arg('candidate_id'), sqlc.arg('tg_username'))
+ON CONFLICT (candidate_id) DO UPDATE
+SET tg_username = EXCLUDED.tg_username, updated_at = now();
@@ -0,0 +1,38 @@
+-- name: GetCandidateProfileByTelegramID :one
+SELECT
+  c.id, c.public_slug, c.display_name, c.desired_role, c.english_level,
+  c.expected_salary_min_cny, c.expected_salary_max_cny, c.availability_days, c.timezone,
+  c.status, c.notify_on_unlock, c.contact_mode, cc.tg_username
+FROM candidates c
+LEFT JOIN candidate_contacts cc ON cc.candidate_id = c.id
+WHERE c.tg_user_id = sqlc.arg('tg_user_id') AND c.status <> 'deleted'
+LIMIT 1;
+
+-- name: UpdateCandidateStatusByID :exec
+UPDATE candidates
+SET status = sqlc.arg('status'), updated_at = now()
+WHERE id = sqlc.arg('id') AND status <> 'deleted';
+
+-- name: UpdateOnboardedCandidate :exec
+UPDATE candidates
+SET display_name = sqlc.arg('display_name'),
+    desired_role = sqlc.arg('desired_role'),
+    english_level = sqlc.arg('english_level'),
+    expected_salary_min_cny = sqlc.arg('expected_salary_min_cny'),
+    expected_salary_max_cny = sqlc.arg('expected_salary_max_cny'),
+    availability_days = sqlc.arg('availability_days'),
+    timezone = sqlc.arg('timezone'),
+    updated_at = now()
+WHERE id = sqlc.arg('id') AND status <> 'deleted';
+
+-- name: DeleteCandidateSkills :exec
+DELETE FROM candidate_skills WHERE candidate_id = sqlc.arg('candidate_id');
+
+-- name: DeleteCandidateContact :exec
+DELETE FROM candidate_contacts WHERE candidate_id = sqlc.arg('candidate_id');
+
+-- name: MarkCandidateDeleted :exec
+-- Detaches the Telegram account so it can onboard again later.
+UPDATE candidates
+SET status = 'deleted', tg_user_id = NULL, contact_consent_at = NULL, updated_at = now()
+WHERE id = sqlc.arg('id');
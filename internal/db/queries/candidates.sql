@@ -20,7 +20,16 @@ WITH filtered AS (
         SELECT 1
         FROM candidate_skills cs
         JOIN skills s ON s.id = cs.skill_id
-        WHERE cs.candidate_id = c.id AND s.name = sqlc.narg('skill')
+        WHERE cs.candidate_id = c.id AND lower(s.name) = lower(sqlc.narg('skill'))
+      )
+    )
+    AND (sqlc.narg('updated_since')::timestamptz IS NULL OR c.updated_at > sqlc.narg('updated_since'))
+    AND (
+      sqlc.narg('tag')::text IS NULL OR sqlc.narg('tag') = '' OR
+      EXISTS (
+        SELECT 1
+        FROM candidate_tags ct
+        WHERE ct.company_id = sqlc.arg('company_id') AND ct.candidate_id = c.id AND lower(ct.tag) = lower(sqlc.narg('tag'))
       )
     )
 ),
@@ -29,11 +38,20 @@ ranked AS (
     f.*,
     CASE
       WHEN sqlc.narg('q')::text IS NULL OR sqlc.narg('q') = '' THEN 0
-      ELSE ts_rank_cd(f.search_tsv, websearch_to_tsquery('simple', sqlc.narg('q')))
-    END AS ts_rank
+      ELSE ts_rank(f.search_tsv, websearch_to_tsquery('simple', sqlc.narg('q')))
+    END AS ts_rank,
+    CASE
+      WHEN sqlc.narg('q')::text IS NULL OR sqlc.narg('q') = '' THEN 0
+      ELSE GREATEST(
+        word_similarity(sqlc.narg('q'), f.display_name),
+        word_similarity(sqlc.narg('q'), coalesce(f.desired_role, ''))
+      )
+    END AS trgm_rank
   FROM filtered f
   WHERE (sqlc.narg('q')::text IS NULL OR sqlc.narg('q') = '')
-     OR (f.search_tsv @@ websearch_to_tsquery('simple', sqlc.narg('q')))
+     OR f.search_tsv @@ websearch_to_tsquery('simple', sqlc.narg('q'))
+     OR sqlc.narg('q') <% f.display_name
+     OR sqlc.narg('q') <% f.desired_role
 )
 SELECT
   r.id,
@@ -48,15 +66,16 @@ SELECT
   r.bc_experience,
   r.summary,
   r.rating,
-  (u.id IS NOT NULL) AS unlocked_contact
+  (u.id IS NOT NULL) AS unlocked_contact,
+  r.unlock_cost
 FROM ranked r
 LEFT JOIN unlocks u
   ON u.company_id = sqlc.arg('company_id')
  AND u.candidate_id = r.id
  AND u.unlock_type = 'contact'
 ORDER BY
-  CASE WHEN sqlc.narg('q')::text IS NULL OR sqlc.narg('q') = '' THEN 0 ELSE 1 END DESC,
-  r.ts_rank DESC,
+  r.ts_rank DESC NULLS LAST,
+  r.trgm_rank DESC,
   r.rating DESC,
   r.updated_at DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -76,7 +95,8 @@ SELECT
   c.bc_experience,
   c.summary,
   c.rating,
-  (u.id IS NOT NULL) AS unlocked_contact
+  (u.id IS NOT NULL) AS unlocked_contact,
+  c.unlock_cost
 FROM candidates c
 LEFT JOIN unlocks u
   ON u.company_id = sqlc.arg('company_id')
@@ -107,6 +127,6 @@ LIMIT 1;
 
 -- name: UnlockCandidateContactIdempotent :one
 INSERT INTO unlocks(company_id, hr_user_id, candidate_id, unlock_type, cost)
-VALUES (sqlc.arg('company_id'), sqlc.arg('hr_user_id'), sqlc.arg('candidate_id'), 'contact', 1)
+VALUES (sqlc.arg('company_id'), sqlc.arg('hr_user_id'), sqlc.arg('candidate_id'), 'contact', sqlc.arg('cost'))
 ON CONFLICT (company_id, candidate_id, unlock_type) DO NOTHING
 RETURNING id;
@@ -0,0 +1,82 @@
+-- name: GetContactUnlock :one
+SELECT id, cost, created_at
+FROM unlocks
+WHERE company_id = sqlc.arg('company_id') AND candidate_id = sqlc.arg('candidate_id') AND unlock_type = 'contact';
+
+-- name: CreateContactReport :one
+-- No row if the company already reported the candidate.
+WITH r AS (
+  INSERT INTO contact_reports (company_id, candidate_id, unlock_id, hr_user_id, reason, details)
+  VALUES (sqlc.arg('company_id'), sqlc.arg('candidate_id'), sqlc.arg('unlock_id'), sqlc.arg('hr_user_id'),
+          sqlc.arg('reason'), sqlc.arg('details'))
+  ON CONFLICT (company_id, candidate_id) DO NOTHING
+  RETURNING *
+)
+SELECT r.id, r.company_id, r.candidate_id, c.public_slug, r.unlock_id, u.cost, u.created_at,
+       r.hr_user_id, r.reason, r.details, r.status, r.refund_amount, r.reviewed_by, r.review_note,
+       r.created_at, r.reviewed_at
+FROM r
+JOIN candidates c ON c.id = r.candidate_id
+JOIN unlocks u ON u.id = r.unlock_id;
+
+-- name: GetContactReport :one
+SELECT r.id, r.company_id, r.candidate_id, c.public_slug, r.unlock_id, u.cost, u.created_at,
+       r.hr_user_id, r.reason, r.details, r.status, r.refund_amount, r.reviewed_by, r.review_note,
+       r.created_at, r.reviewed_at
+FROM contact_reports r
+JOIN candidates c ON c.id = r.candidate_id
+JOIN unlocks u ON u.id = r.unlock_id
+WHERE r.company_id = sqlc.arg('company_id') AND r.candidate_id = sqlc.arg('candidate_id');
+
+-- name: GetContactReportByID :one
+SELECT r.id, r.company_id, r.candidate_id, c.public_slug, r.unlock_id, u.cost, u.created_at,
+       r.hr_user_id, r.reason, r.details, r.status, r.refund_amount, r.reviewed_by, r.review_note,
+       r.created_at, r.reviewed_at
+FROM contact_reports r
+JOIN candidates c ON c.id = r.candidate_id
+JOIN unlocks u ON u.id = r.unlock_id
+WHERE r.id = sqlc.arg('id');
+
+-- name: LockContactReport :one
+SELECT r.id, r.company_id, r.candidate_id, c.public_slug, r.unlock_id, u.cost, u.created_at,
+       r.hr_user_id, r.reason, r.details, r.status, r.refund_amount, r.reviewed_by, r.review_note,
+       r.created_at, r.reviewed_at
+FROM contact_reports r
+JOIN candidates c ON c.id = r.candidate_id
+JOIN unlocks u ON u.id = r.unlock_id
+WHERE r.id = sqlc.arg('id')
+FOR UPDATE OF r;
+
+-- name: ResolveContactReport :one
+-- No row if the report is no longer pending.
+WITH r AS (
+  UPDATE contact_reports
+  SET status = sqlc.arg('status'), refund_amount = sqlc.arg('refund_amount'), reviewed_by = sqlc.arg('reviewed_by'),
+      review_note = sqlc.arg('review_note'), reviewed_at = now()
+  WHERE id = sqlc.arg('id') AND status = 'pending'
+  RETURNING *
+)
+SELECT r.id, r.company_id, r.candidate_id, c.public_slug, r.unlock_id, u.cost, u.created_at,
+       r.hr_user_id, r.reason, r.details, r.status, r.refund_amount, r.reviewed_by, r.review_note,
+       r.created_at, r.reviewed_at
+FROM r
+JOIN candidates c ON c.id = r.candidate_id
+JOIN unlocks u ON u.id = r.unlock_id;
+
+-- name: AdminListContactReports :many
+SELECT r.id, r.company_id, r.candidate_id, c.public_slug, r.unlock_id, u.cost, u.created_at,
+       r.hr_user_id, r.reason, r.details, r.status, r.refund_amount, r.reviewed_by, r.review_note,
+       r.created_at, r.reviewed_at,
+       co.name AS company_name, c.display_name AS candidate_name, h.display_name AS reporter_name,
+       NULLIF(cc.tg_username, '')::text AS tg_username,
+       NULLIF(cc.email::text, '')       AS email,
+       NULLIF(cc.phone, '')::text       AS phone
+FROM contact_reports r
+JOIN candidates c ON c.id = r.candidate_id
+JOIN unlocks u ON u.id = r.unlock_id
+JOIN companies co ON co.id = r.company_id
+LEFT JOIN hr_users h ON h.id = r.hr_user_id
+LEFT JOIN candidate_contacts cc ON cc.candidate_id = r.candidate_id
+WHERE (sqlc.narg('status')::text IS NULL OR r.status = sqlc.narg('status')::text)
+ORDER BY r.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
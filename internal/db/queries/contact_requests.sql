@@ -0,0 +1,65 @@
+-- name: GetContactGate :one
+SELECT c.contact_mode,
+       EXISTS (
+         SELECT 1 FROM unlocks u
+         WHERE u.company_id = sqlc.arg('company_id') AND u.candidate_id = c.id AND u.unlock_type = 'contact'
+       ) AS unlocked,
+       c.unlock_cost
+FROM candidates c
+WHERE c.id = sqlc.arg('candidate_id');
+
+-- name: UpsertContactRequest :one
+INSERT INTO contact_requests (company_id, candidate_id, hr_user_id, status, expires_at)
+VALUES (sqlc.arg('company_id'), sqlc.arg('candidate_id'), sqlc.arg('hr_user_id'), 'pending', sqlc.arg('expires_at'))
+ON CONFLICT (company_id, candidate_id) DO UPDATE
+SET hr_user_id = EXCLUDED.hr_user_id,
+    status = 'pending',
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at,
+    decided_at = NULL
+WHERE contact_requests.status = 'pending' AND contact_requests.expires_at <= now()
+RETURNING id;
+
+-- name: GetContactRequest :one
+SELECT cr.id, cr.company_id, cr.candidate_id, cr.hr_user_id, cr.status,
+       cr.created_at, cr.expires_at, cr.decided_at
+FROM contact_requests cr
+WHERE cr.company_id = sqlc.arg('company_id') AND cr.candidate_id = sqlc.arg('candidate_id');
+
+-- name: GetCandidateContactRequest :one
+SELECT cr.id, cr.company_id, cr.candidate_id, cr.hr_user_id, cr.status,
+       cr.created_at, cr.expires_at, cr.decided_at
+FROM contact_requests cr
+JOIN candidates c ON c.id = cr.candidate_id
+WHERE cr.id = sqlc.arg('id') AND c.tg_user_id = sqlc.arg('tg_user_id') AND c.status <> 'deleted';
+
+-- name: LockContactRequest :one
+SELECT cr.id, cr.company_id, cr.candidate_id, cr.hr_user_id, cr.status,
+       cr.created_at, cr.expires_at, cr.decided_at
+FROM contact_requests cr
+WHERE cr.id = sqlc.arg('id')
+FOR UPDATE;
+
+-- name: DecideContactRequest :one
+UPDATE contact_requests
+SET status = sqlc.arg('status'), decided_at = now()
+WHERE id = sqlc.arg('id') AND status = 'pending' AND expires_at > now()
+RETURNING id;
+
+-- name: EnqueueContactRequestNotification :exec
+INSERT INTO bot_outbox (chat_id, kind, payload)
+SELECT c.tg_user_id, sqlc.arg('kind'), jsonb_build_object(
+  'request_id', cr.id,
+  'company_name', co.name,
+  'desired_role', COALESCE(c.desired_role, '')
+)
+FROM contact_requests cr
+JOIN candidates c ON c.id = cr.candidate_id
+JOIN companies co ON co.id = cr.company_id
+WHERE cr.id = sqlc.arg('request_id') AND c.tg_user_id IS NOT NULL;
+
+-- Preference only, like SetCandidateNotifyOnUnlock: updated_at is left alone.
+-- name: SetCandidateContactMode :exec
+UPDATE candidates
+SET contact_mode = sqlc.arg('contact_mode')
+WHERE id = sqlc.arg('id') AND status <> 'deleted';
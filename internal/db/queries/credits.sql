@@ -0,0 +1,46 @@
+-- name: AddCredits :one
+-- Changes the company's credit balance and appends the matching ledger entry. Callers hold
+-- the quota row lock (LockCompanyQuota).
+WITH bal AS (
+  UPDATE company_quotas
+  SET credit_balance = credit_balance + sqlc.arg('amount'), updated_at = now()
+  WHERE company_id = sqlc.arg('company_id')
+  RETURNING credit_balance
+)
+INSERT INTO credit_ledger (company_id, kind, amount, balance_after, candidate_id, hr_user_id, ref, note)
+SELECT sqlc.arg('company_id'), sqlc.arg('kind'), sqlc.arg('amount'), bal.credit_balance,
+       sqlc.narg('candidate_id'), sqlc.narg('hr_user_id'), sqlc.narg('ref'), sqlc.arg('note')
+FROM bal
+RETURNING id, company_id, kind, amount, balance_after, candidate_id, NULL::text AS candidate_slug,
+          hr_user_id, ref, note, created_at;
+
+-- name: GetCreditEntryByRef :one
+SELECT l.id, l.company_id, l.kind, l.amount, l.balance_after, l.candidate_id, c.public_slug AS candidate_slug,
+       l.hr_user_id, l.ref, l.note, l.created_at
+FROM credit_ledger l
+LEFT JOIN candidates c ON c.id = l.candidate_id
+WHERE l.company_id = sqlc.arg('company_id') AND l.kind = sqlc.arg('kind') AND l.ref = sqlc.arg('ref');
+
+-- name: ListCreditEntries :many
+SELECT l.id, l.company_id, l.kind, l.amount, l.balance_after, l.candidate_id, c.public_slug AS candidate_slug,
+       l.hr_user_id, l.ref, l.note, l.created_at
+FROM credit_ledger l
+LEFT JOIN candidates c ON c.id = l.candidate_id
+WHERE l.company_id = sqlc.arg('company_id')
+ORDER BY l.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetCreditBalance :one
+SELECT q.company_id, q.unlock_quota_total, q.unlock_quota_used, q.period_start, q.period_end, q.credit_balance,
+       COALESCE((SELECT sum(amount) FROM credit_ledger WHERE company_id = q.company_id), 0)::bigint AS ledger_balance
+FROM company_quotas q
+WHERE q.company_id = sqlc.arg('company_id');
+
+-- name: SetCandidateUnlockCost :one
+-- Pricing only: updated_at is left alone so saved search digests don't report the
+-- candidate as updated.
+UPDATE candidates c
+SET unlock_cost = sqlc.arg('cost')
+FROM candidates old
+WHERE c.public_slug = sqlc.arg('public_slug') AND old.id = c.id
+RETURNING old.unlock_cost;
@@ -1,6 +1,6 @@
 
 -- name: FindHRUserByTelegramID :one
-SELECT id, company_id, status, role
+SELECT id, company_id, status, role, session_version
 FROM hr_users
 WHERE tg_user_id = sqlc.arg('tg_user_id')
 LIMIT 1;
@@ -25,3 +25,61 @@ RETURNING id;
 UPDATE hr_users
 SET status = sqlc.arg('status'), updated_at = now()
 WHERE id = sqlc.arg('id');
+
+-- name: ListCompanyMembers :many
+SELECT id, tg_user_id, tg_username, display_name, role, status, created_at
+FROM hr_users
+WHERE company_id = sqlc.arg('company_id') AND status <> 'removed'
+ORDER BY created_at ASC;
+
+-- name: LockCompanyMember :one
+SELECT id, role, status
+FROM hr_users
+WHERE id = sqlc.arg('id') AND company_id = sqlc.arg('company_id') AND status <> 'removed'
+FOR UPDATE;
+
+-- name: LockCompanyForUpdate :one
+SELECT id FROM companies WHERE id = sqlc.arg('id') FOR UPDATE;
+
+-- name: LockHRUserForUpdate :one
+SELECT id FROM hr_users WHERE id = sqlc.arg('id') FOR UPDATE;
+
+-- name: CountActiveCompanyOwners :one
+SELECT count(*)
+FROM hr_users
+WHERE company_id = sqlc.arg('company_id') AND role = 'owner' AND status = 'active';
+
+-- name: UpdateHRUserRole :exec
+UPDATE hr_users
+SET role = sqlc.arg('role'), updated_at = now()
+WHERE id = sqlc.arg('id');
+
+-- name: RemoveHRUser :exec
+UPDATE hr_users
+SET status = 'removed', tg_user_id = NULL, session_version = session_version + 1, updated_at = now()
+WHERE id = sqlc.arg('id');
+
+-- name: IsSoleMemberOfEmptyCompany :one
+SELECT NOT EXISTS (SELECT 1 FROM hr_users
+                   WHERE company_id = sqlc.arg('company_id') AND id <> sqlc.arg('hr_user_id') AND status <> 'removed')
+   AND NOT EXISTS (SELECT 1 FROM unlocks WHERE company_id = sqlc.arg('company_id'))
+   AND NOT EXISTS (SELECT 1 FROM payments WHERE company_id = sqlc.arg('company_id') AND status = 'succeeded');
+
+-- name: MoveHRUserToCompany :one
+UPDATE hr_users
+SET company_id = sqlc.arg('company_id'), role = sqlc.arg('role'), status = 'active',
+    session_version = session_version + 1, updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING session_version;
+
+-- name: GetHRUserState :one
+SELECT id, company_id, status, role, session_version
+FROM hr_users
+WHERE id = sqlc.arg('id')
+LIMIT 1;
+
+-- name: BumpHRUserSessionVersion :one
+UPDATE hr_users
+SET session_version = session_version + 1, updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING session_version;
@@ -0,0 +1,35 @@
+-- name: CreateCompanyInvite :one
+INSERT INTO company_invites (company_id, code, role, max_uses, created_by, expires_at)
+VALUES (sqlc.arg('company_id'), sqlc.arg('code'), sqlc.arg('role'), sqlc.arg('max_uses'), sqlc.arg('created_by'), sqlc.arg('expires_at'))
+RETURNING id, company_id, code, role, max_uses, used_count, created_by, expires_at, revoked_at, created_at;
+
+-- name: ListCompanyInvites :many
+SELECT id, company_id, code, role, max_uses, used_count, created_by, expires_at, revoked_at, created_at
+FROM company_invites
+WHERE company_id = sqlc.arg('company_id')
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: RevokeCompanyInvite :execrows
+UPDATE company_invites
+SET revoked_at = now()
+WHERE id = sqlc.arg('id') AND company_id = sqlc.arg('company_id') AND revoked_at IS NULL;
+
+-- name: UpdateCompanyInviteExpiry :execrows
+UPDATE company_invites
+SET expires_at = sqlc.arg('expires_at')
+WHERE id = sqlc.arg('id') AND company_id = sqlc.arg('company_id') AND revoked_at IS NULL;
+
+-- name: LockRedeemableInviteByCode :one
+SELECT id, company_id, role
+FROM company_invites
+WHERE code = sqlc.arg('code')
+  AND revoked_at IS NULL
+  AND expires_at > now()
+  AND used_count < max_uses
+FOR UPDATE;
+
+-- name: MarkCompanyInviteUsed :exec
+UPDATE company_invites
+SET used_count = used_count + 1, last_used_at = now()
+WHERE id = sqlc.arg('id');
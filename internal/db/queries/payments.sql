@@ -0,0 +1,106 @@
+-- name: ListCreditPacks :many
+SELECT id, code, name, credits, price_minor, currency, price_stars
+FROM credit_packs
+WHERE active
+ORDER BY credits, id;
+
+-- name: GetCreditPack :one
+-- No row for unknown or retired packs.
+SELECT id, code, name, credits, price_minor, currency, price_stars
+FROM credit_packs
+WHERE id = sqlc.arg('id') AND active;
+
+-- name: CreatePayment :one
+WITH p AS (
+  INSERT INTO payments (company_id, hr_user_id, pack_id, provider, credits, amount, currency)
+  VALUES (sqlc.arg('company_id'), sqlc.arg('hr_user_id'), sqlc.arg('pack_id'), sqlc.arg('provider'),
+          sqlc.arg('credits'), sqlc.arg('amount'), sqlc.arg('currency'))
+  RETURNING *
+)
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM p
+JOIN credit_packs k ON k.id = p.pack_id;
+
+-- name: SetPaymentCheckout :one
+WITH p AS (
+  UPDATE payments
+  SET provider_ref = sqlc.narg('provider_ref'), checkout_url = sqlc.arg('checkout_url'), updated_at = now()
+  WHERE id = sqlc.arg('id')
+  RETURNING *
+)
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM p
+JOIN credit_packs k ON k.id = p.pack_id;
+
+-- name: GetPayment :one
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM payments p
+JOIN credit_packs k ON k.id = p.pack_id
+WHERE p.id = sqlc.arg('id');
+
+-- name: GetPaymentByProviderRef :one
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM payments p
+JOIN credit_packs k ON k.id = p.pack_id
+WHERE p.provider = sqlc.arg('provider') AND p.provider_ref = sqlc.arg('provider_ref');
+
+-- name: GetCompanyPayment :one
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM payments p
+JOIN credit_packs k ON k.id = p.pack_id
+WHERE p.company_id = sqlc.arg('company_id') AND p.id = sqlc.arg('id');
+
+-- name: LockPayment :one
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM payments p
+JOIN credit_packs k ON k.id = p.pack_id
+WHERE p.id = sqlc.arg('id')
+FOR UPDATE OF p;
+
+-- name: MarkPaymentSucceeded :one
+WITH p AS (
+  UPDATE payments
+  SET status = 'succeeded', provider_ref = COALESCE(sqlc.narg('provider_ref'), provider_ref), failure_reason = '',
+      paid_at = now(), updated_at = now()
+  WHERE id = sqlc.arg('id')
+  RETURNING *
+)
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM p
+JOIN credit_packs k ON k.id = p.pack_id;
+
+-- name: MarkPaymentFailed :one
+-- No row if the payment is no longer pending.
+WITH p AS (
+  UPDATE payments
+  SET status = 'failed', failure_reason = sqlc.arg('reason'), updated_at = now()
+  WHERE id = sqlc.arg('id') AND status = 'pending'
+  RETURNING *
+)
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM p
+JOIN credit_packs k ON k.id = p.pack_id;
+
+-- name: ListCompanyPayments :many
+SELECT p.id, p.company_id, p.hr_user_id, p.pack_id, k.code AS pack_code, p.provider, p.credits, p.amount,
+       p.currency, p.status, p.provider_ref, p.checkout_url, p.failure_reason, p.created_at, p.updated_at, p.paid_at
+FROM payments p
+JOIN credit_packs k ON k.id = p.pack_id
+WHERE p.company_id = sqlc.arg('company_id')
+ORDER BY p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: InsertPaymentEvent :one
+-- No row if the event was recorded before.
+INSERT INTO payment_events (provider, event_id, payment_id, type, payload)
+VALUES (sqlc.arg('provider'), sqlc.arg('event_id'), sqlc.narg('payment_id'), sqlc.arg('type'), sqlc.arg('payload'))
+ON CONFLICT (provider, event_id) DO NOTHING
+RETURNING id;
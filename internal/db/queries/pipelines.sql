@@ -0,0 +1,103 @@
+-- name: CreatePipeline :one
+INSERT INTO pipelines AS p (company_id, name, created_by)
+VALUES (sqlc.arg('company_id'), sqlc.arg('name'), sqlc.arg('created_by'))
+RETURNING p.id, p.company_id, p.name, p.created_at, p.updated_at;
+
+-- name: ListPipelines :many
+SELECT p.id, p.company_id, p.name, p.created_at, p.updated_at
+FROM pipelines p
+WHERE p.company_id = sqlc.arg('company_id')
+ORDER BY p.created_at, p.id;
+
+-- name: GetPipeline :one
+SELECT p.id, p.company_id, p.name, p.created_at, p.updated_at
+FROM pipelines p
+WHERE p.id = sqlc.arg('id') AND p.company_id = sqlc.arg('company_id');
+
+-- name: LockPipeline :one
+SELECT p.id, p.company_id, p.name, p.created_at, p.updated_at
+FROM pipelines p
+WHERE p.id = sqlc.arg('id') AND p.company_id = sqlc.arg('company_id')
+FOR UPDATE;
+
+-- name: RenamePipeline :one
+UPDATE pipelines p
+SET name = sqlc.arg('name'), updated_at = now()
+WHERE p.id = sqlc.arg('id') AND p.company_id = sqlc.arg('company_id')
+RETURNING p.id, p.company_id, p.name, p.created_at, p.updated_at;
+
+-- name: TouchPipeline :exec
+UPDATE pipelines SET updated_at = now() WHERE id = sqlc.arg('id');
+
+-- name: DeletePipeline :execrows
+DELETE FROM pipelines WHERE id = sqlc.arg('id') AND company_id = sqlc.arg('company_id');
+
+-- name: CreatePipelineStage :one
+INSERT INTO pipeline_stages (pipeline_id, name, position)
+VALUES (sqlc.arg('pipeline_id'), sqlc.arg('name'), sqlc.arg('position'))
+RETURNING id;
+
+-- name: ListPipelineStages :many
+SELECT ps.id, ps.pipeline_id, ps.name, ps.position,
+       (SELECT count(*)
+        FROM pipeline_candidates pc
+        JOIN candidates c ON c.id = pc.candidate_id
+        WHERE pc.stage_id = ps.id AND c.status <> 'deleted')::int AS candidate_count
+FROM pipeline_stages ps
+WHERE ps.pipeline_id = ANY(sqlc.arg('pipeline_ids')::bigint[])
+ORDER BY ps.pipeline_id, ps.position, ps.id;
+
+-- name: UpdatePipelineStage :exec
+UPDATE pipeline_stages SET name = sqlc.arg('name'), position = sqlc.arg('position') WHERE id = sqlc.arg('id');
+
+-- name: PurgeDeletedPipelineStageCandidates :exec
+DELETE FROM pipeline_candidates pc
+USING candidates c
+WHERE pc.stage_id = sqlc.arg('id') AND c.id = pc.candidate_id AND c.status = 'deleted';
+
+-- name: DeleteEmptyPipelineStage :execrows
+DELETE FROM pipeline_stages ps
+WHERE ps.id = sqlc.arg('id')
+  AND NOT EXISTS (SELECT 1 FROM pipeline_candidates pc WHERE pc.stage_id = ps.id);
+
+-- name: MovePipelineCandidate :one
+WITH prev AS (
+  SELECT stage_id FROM pipeline_candidates
+  WHERE pipeline_id = sqlc.arg('pipeline_id') AND candidate_id = sqlc.arg('candidate_id')
+)
+INSERT INTO pipeline_candidates (pipeline_id, candidate_id, stage_id, added_by)
+SELECT ps.pipeline_id, sqlc.arg('candidate_id'), ps.id, sqlc.arg('hr_user_id')
+FROM pipeline_stages ps
+WHERE ps.id = sqlc.arg('stage_id') AND ps.pipeline_id = sqlc.arg('pipeline_id')
+ON CONFLICT (pipeline_id, candidate_id) DO UPDATE
+SET stage_id = EXCLUDED.stage_id,
+    moved_at = CASE WHEN pipeline_candidates.stage_id = EXCLUDED.stage_id
+                    THEN pipeline_candidates.moved_at ELSE now() END
+RETURNING (SELECT stage_id FROM prev) AS previous_stage_id;
+
+-- name: RemovePipelineCandidate :one
+DELETE FROM pipeline_candidates pc
+USING candidates c
+WHERE pc.pipeline_id = sqlc.arg('pipeline_id') AND c.id = pc.candidate_id AND c.public_slug = sqlc.arg('slug')
+RETURNING pc.candidate_id, pc.stage_id;
+
+-- name: ListPipelineCandidates :many
+SELECT c.public_slug, c.display_name, c.desired_role,
+       EXISTS (
+         SELECT 1 FROM unlocks u
+         WHERE u.company_id = sqlc.arg('company_id') AND u.candidate_id = c.id AND u.unlock_type = 'contact'
+       ) AS unlocked_contact,
+       pc.stage_id, pc.created_at, pc.moved_at
+FROM pipeline_candidates pc
+JOIN pipeline_stages ps ON ps.id = pc.stage_id
+JOIN candidates c ON c.id = pc.candidate_id
+WHERE pc.pipeline_id = sqlc.arg('pipeline_id') AND c.status <> 'deleted'
+ORDER BY ps.position, ps.id, pc.moved_at DESC;
+
+-- name: ListCandidatePipelineStages :many
+SELECT pc.candidate_id, p.id AS pipeline_id, p.name AS pipeline_name, ps.id AS stage_id, ps.name AS stage_name
+FROM pipeline_candidates pc
+JOIN pipelines p ON p.id = pc.pipeline_id
+JOIN pipeline_stages ps ON ps.id = pc.stage_id
+WHERE p.company_id = sqlc.arg('company_id') AND pc.candidate_id = ANY(sqlc.arg('candidate_ids')::bigint[])
+ORDER BY pc.candidate_id, p.created_at, p.id;
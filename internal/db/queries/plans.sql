@@ -0,0 +1,67 @@
+-- name: ListPlans :many
+SELECT id, code, name, unlock_quota_monthly, seat_limit, features, is_default, created_at, updated_at
+FROM plans
+ORDER BY unlock_quota_monthly, id;
+
+-- name: GetPlan :one
+SELECT id, code, name, unlock_quota_monthly, seat_limit, features, is_default, created_at, updated_at
+FROM plans
+WHERE id = sqlc.arg('id');
+
+-- name: CreatePlan :one
+-- Returns no row if the code is taken.
+INSERT INTO plans (code, name, unlock_quota_monthly, seat_limit, features)
+VALUES (sqlc.arg('code'), sqlc.arg('name'), sqlc.arg('unlock_quota_monthly'), sqlc.arg('seat_limit'), sqlc.arg('features'))
+ON CONFLICT (code) DO NOTHING
+RETURNING id, code, name, unlock_quota_monthly, seat_limit, features, is_default, created_at, updated_at;
+
+-- name: UpdatePlan :one
+UPDATE plans
+SET name                 = COALESCE(sqlc.narg('name')::text, name),
+    unlock_quota_monthly = COALESCE(sqlc.narg('unlock_quota_monthly')::int, unlock_quota_monthly),
+    seat_limit           = COALESCE(sqlc.narg('seat_limit')::int, seat_limit),
+    features             = COALESCE(sqlc.narg('features')::jsonb, features),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING id, code, name, unlock_quota_monthly, seat_limit, features, is_default, created_at, updated_at;
+
+-- name: SetDefaultPlan :exec
+UPDATE plans SET is_default = (id = sqlc.arg('id')), updated_at = now()
+WHERE is_default OR id = sqlc.arg('id');
+
+-- name: GetCompanyPlan :one
+SELECT p.id, p.code, p.name, p.unlock_quota_monthly, p.seat_limit, p.features, p.is_default,
+       p.created_at, p.updated_at, cp.effective_from
+FROM plans p
+LEFT JOIN LATERAL (
+  SELECT effective_from FROM company_plans
+  WHERE company_id = sqlc.arg('company_id') AND effective_from <= CURRENT_DATE
+  ORDER BY effective_from DESC
+  LIMIT 1
+) cp ON true
+WHERE p.id = company_plan_id(sqlc.arg('company_id'), CURRENT_DATE);
+
+-- name: ListCompanyPlanAssignments :many
+SELECT cp.plan_id, p.code AS plan_code, cp.effective_from, cp.assigned_by, cp.created_at
+FROM company_plans cp
+JOIN plans p ON p.id = cp.plan_id
+WHERE cp.company_id = sqlc.arg('company_id')
+ORDER BY cp.effective_from DESC;
+
+-- name: AssignCompanyPlan :exec
+INSERT INTO company_plans (company_id, plan_id, effective_from, assigned_by)
+VALUES (sqlc.arg('company_id'), sqlc.arg('plan_id'), sqlc.arg('effective_from'), sqlc.arg('assigned_by'))
+ON CONFLICT (company_id, effective_from) DO UPDATE
+SET plan_id = EXCLUDED.plan_id, assigned_by = EXCLUDED.assigned_by, created_at = now();
+
+-- name: ApplyCompanyPlanQuota :one
+UPDATE company_quotas q
+SET unlock_quota_total = p.unlock_quota_monthly, updated_at = now()
+FROM plans p
+WHERE q.company_id = sqlc.arg('company_id') AND p.id = company_plan_id(sqlc.arg('company_id'), CURRENT_DATE)
+RETURNING q.company_id, q.unlock_quota_total, q.unlock_quota_used, q.period_start, q.period_end, q.credit_balance;
+
+-- name: GetCompanySeats :one
+SELECT
+  COALESCE((SELECT seat_limit FROM plans WHERE id = company_plan_id(sqlc.arg('company_id'), CURRENT_DATE)), 0)::int AS seat_limit,
+  (SELECT count(*) FROM hr_users WHERE company_id = sqlc.arg('company_id') AND status = 'active') AS active_seats;
@@ -1,5 +1,5 @@
 -- name: LockCompanyQuota :one
-SELECT company_id, unlock_quota_total, unlock_quota_used
+SELECT company_id, unlock_quota_total, unlock_quota_used, period_start, period_end, credit_balance
 FROM company_quotas
 WHERE company_id = sqlc.arg('company_id')
 FOR UPDATE;
@@ -9,3 +9,63 @@ UPDATE company_quotas
 SET unlock_quota_used = unlock_quota_used + sqlc.arg('delta'),
     updated_at = now()
 WHERE company_id = sqlc.arg('company_id');
+
+-- name: LockDueCompanyQuotas :many
+-- Locks quota rows whose period_end has been reached by today (periods are
+-- [period_start, period_end)) so they can be rolled over; locked rows are skipped.
+SELECT company_id, unlock_quota_total, unlock_quota_used, period_start, period_end, credit_balance
+FROM company_quotas
+WHERE period_end <= sqlc.arg('today')::date
+ORDER BY company_id
+LIMIT sqlc.arg('limit')
+FOR UPDATE SKIP LOCKED;
+
+-- name: RolloverCompanyQuota :one
+-- Archives the company's finished quota period and starts the given one, which must not
+-- start before the finished period ends. The new period's allowance comes from the plan in
+-- effect on its first day and is granted as credits, after the unspent part of the old
+-- grant expired. The caller holds the row lock.
+WITH due AS (
+  SELECT company_id, period_start, period_end, unlock_quota_total, unlock_quota_used, credit_balance,
+         -- unlocks spend the grant first, so what is left of it is total - used
+         LEAST(credit_balance, GREATEST(unlock_quota_total - unlock_quota_used, 0)) AS expiring
+  FROM company_quotas
+  WHERE company_id = sqlc.arg('company_id') AND period_end <= sqlc.arg('period_start')::date
+), archived AS (
+  INSERT INTO company_quota_periods (company_id, period_start, period_end, unlock_quota_total, unlock_quota_used)
+  SELECT company_id, period_start, period_end, unlock_quota_total, unlock_quota_used
+  FROM due
+  ON CONFLICT (company_id, period_start) DO NOTHING
+), upcoming AS (
+  SELECT company_id, credit_balance - expiring AS kept,
+         COALESCE(
+           (SELECT unlock_quota_monthly FROM plans WHERE id = company_plan_id(company_id, sqlc.arg('period_start')::date)),
+           unlock_quota_total) AS grant_total
+  FROM due
+), ledger AS (
+  INSERT INTO credit_ledger (company_id, kind, amount, balance_after, note)
+  SELECT company_id, 'expire', -expiring, credit_balance - expiring, 'period ended'
+  FROM due
+  WHERE expiring > 0
+  UNION ALL
+  SELECT company_id, 'grant', grant_total, kept + grant_total, 'period allowance'
+  FROM upcoming
+  WHERE grant_total > 0
+)
+UPDATE company_quotas q
+SET period_start = sqlc.arg('period_start')::date,
+    period_end   = sqlc.arg('period_end')::date,
+    unlock_quota_total = n.grant_total,
+    unlock_quota_used = 0,
+    credit_balance = n.kept + n.grant_total,
+    updated_at = now()
+FROM upcoming n
+WHERE q.company_id = n.company_id
+RETURNING q.company_id, q.unlock_quota_total, q.unlock_quota_used, q.period_start, q.period_end, q.credit_balance;
+
+-- name: ListCompanyQuotaPeriods :many
+SELECT period_start, period_end, unlock_quota_total, unlock_quota_used, closed_at
+FROM company_quota_periods
+WHERE company_id = sqlc.arg('company_id')
+ORDER BY period_start DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
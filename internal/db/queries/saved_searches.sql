@@ -0,0 +1,82 @@
+-- name: CreateSavedSearch :one
+-- Run after LockHRUserForUpdate in the same transaction, so concurrent creates can't both
+-- pass the count.
+INSERT INTO saved_searches AS s (company_id, hr_user_id, name, filters, frequency, next_run_at)
+SELECT sqlc.arg('company_id'), sqlc.arg('hr_user_id'), sqlc.arg('name'), sqlc.arg('filters')::jsonb,
+       sqlc.arg('frequency'), now() + (sqlc.arg('interval_seconds')::int * interval '1 second')
+WHERE (SELECT count(*) FROM saved_searches WHERE hr_user_id = sqlc.arg('hr_user_id')) < sqlc.arg('max_per_user')
+RETURNING s.id, s.company_id, s.hr_user_id, s.name, s.filters, s.frequency, s.muted,
+          s.last_run_at, s.next_run_at, s.created_at, s.updated_at;
+
+-- name: ListSavedSearches :many
+SELECT s.id, s.company_id, s.hr_user_id, s.name, s.filters, s.frequency, s.muted,
+       s.last_run_at, s.next_run_at, s.created_at, s.updated_at
+FROM saved_searches s
+WHERE s.hr_user_id = sqlc.arg('hr_user_id')
+ORDER BY s.created_at DESC;
+
+-- name: GetSavedSearch :one
+SELECT s.id, s.company_id, s.hr_user_id, s.name, s.filters, s.frequency, s.muted,
+       s.last_run_at, s.next_run_at, s.created_at, s.updated_at
+FROM saved_searches s
+WHERE s.id = sqlc.arg('id') AND s.hr_user_id = sqlc.arg('hr_user_id');
+
+-- name: GetTelegramSavedSearch :one
+SELECT s.id, s.company_id, s.hr_user_id, s.name, s.filters, s.frequency, s.muted,
+       s.last_run_at, s.next_run_at, s.created_at, s.updated_at
+FROM saved_searches s
+JOIN hr_users h ON h.id = s.hr_user_id
+WHERE s.id = sqlc.arg('id') AND h.tg_user_id = sqlc.arg('tg_user_id') AND h.status = 'active';
+
+-- name: UpdateSavedSearch :one
+UPDATE saved_searches s
+SET name = sqlc.arg('name'),
+    filters = sqlc.arg('filters')::jsonb,
+    frequency = sqlc.arg('frequency'),
+    muted = sqlc.arg('muted'),
+    last_run_at = CASE WHEN s.muted AND NOT sqlc.arg('muted') THEN now() ELSE s.last_run_at END,
+    next_run_at = CASE WHEN s.muted AND NOT sqlc.arg('muted') THEN now() ELSE s.last_run_at END
+                  + (sqlc.arg('interval_seconds')::int * interval '1 second'),
+    updated_at = now()
+WHERE s.id = sqlc.arg('id') AND s.hr_user_id = sqlc.arg('hr_user_id')
+RETURNING s.id, s.company_id, s.hr_user_id, s.name, s.filters, s.frequency, s.muted,
+          s.last_run_at, s.next_run_at, s.created_at, s.updated_at;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches WHERE id = sqlc.arg('id') AND hr_user_id = sqlc.arg('hr_user_id');
+
+-- name: ClaimDueSavedSearches :many
+UPDATE saved_searches s
+SET next_run_at = now() + (sqlc.arg('lease_seconds')::int * interval '1 second')
+FROM hr_users h
+WHERE h.id = s.hr_user_id
+  AND s.id IN (
+    SELECT ss.id
+    FROM saved_searches ss
+    JOIN hr_users hu ON hu.id = ss.hr_user_id
+    JOIN companies co ON co.id = ss.company_id
+    WHERE NOT ss.muted
+      AND ss.next_run_at <= now()
+      AND hu.status = 'active'
+      AND hu.tg_user_id IS NOT NULL
+      AND co.status = 'active'
+    ORDER BY ss.next_run_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE OF ss SKIP LOCKED
+  )
+RETURNING s.id, s.company_id, s.hr_user_id, s.name, s.filters, s.frequency, s.muted,
+          s.last_run_at, s.next_run_at, s.created_at, s.updated_at,
+          h.tg_user_id AS chat_id, now()::timestamptz AS run_at;
+
+-- name: CompleteSavedSearchRun :exec
+WITH run AS (
+  UPDATE saved_searches
+  SET last_run_at = sqlc.arg('run_at'),
+      next_run_at = sqlc.arg('run_at') + (sqlc.arg('interval_seconds')::int * interval '1 second')
+  WHERE id = sqlc.arg('id')
+  RETURNING id
+)
+INSERT INTO bot_outbox (chat_id, kind, payload)
+SELECT sqlc.arg('chat_id'), sqlc.arg('kind'), sqlc.narg('digest')::jsonb
+FROM run
+WHERE sqlc.narg('digest')::jsonb IS NOT NULL;
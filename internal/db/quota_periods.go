@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==================== Quota Periods ====================
+
+type LockDueCompanyQuotasParams struct {
+	Today time.Time
+	Limit int32
+}
+
+// LockDueCompanyQuotas locks quota rows whose period_end has been reached by today (periods
+// are [period_start, period_end)) so they can be rolled over. Rows locked by other
+// transactions are skipped.
+func (q *Queries) LockDueCompanyQuotas(ctx context.Context, p LockDueCompanyQuotasParams) ([]CompanyQuotaRow, error) {
+	sql := `
+SELECT company_id, unlock_quota_total, unlock_quota_used, period_start, period_end, credit_balance
+FROM company_quotas
+WHERE period_end <= $1::date
+ORDER BY company_id
+LIMIT $2
+FOR UPDATE SKIP LOCKED;`
+	rows, err := q.db.Query(ctx, sql, p.Today, p.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CompanyQuotaRow
+	for rows.Next() {
+		var r CompanyQuotaRow
+		if err := rows.Scan(&r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd, &r.CreditBalance); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type RolloverCompanyQuotaParams struct {
+	CompanyID   int64
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+}
+
+// RolloverCompanyQuota archives the company's finished quota period and starts the given
+// one, which must not start before the finished period ends (pgx.ErrNoRows otherwise). The
+// new period's allowance comes from the plan in effect on its first day (the previous total
+// if there is no plan) and is granted as credits, after the unspent part of the old grant
+// expired. The caller must hold the row lock (LockCompanyQuota, LockDueCompanyQuotas).
+func (q *Queries) RolloverCompanyQuota(ctx context.Context, p RolloverCompanyQuotaParams) (CompanyQuotaDetailRow, error) {
+	sql := `
+WITH due AS (
+  SELECT company_id, period_start, period_end, unlock_quota_total, unlock_quota_used, credit_balance,
+         -- unlocks spend the grant first, so what is left of it is total - used
+         LEAST(credit_balance, GREATEST(unlock_quota_total - unlock_quota_used, 0)) AS expiring
+  FROM company_quotas
+  WHERE company_id = $1 AND period_end <= $2::date
+), archived AS (
+  INSERT INTO company_quota_periods (company_id, period_start, period_end, unlock_quota_total, unlock_quota_used)
+  SELECT company_id, period_start, period_end, unlock_quota_total, unlock_quota_used
+  FROM due
+  ON CONFLICT (company_id, period_start) DO NOTHING
+), upcoming AS (
+  SELECT company_id, credit_balance - expiring AS kept,
+         COALESCE(
+           (SELECT unlock_quota_monthly FROM plans WHERE id = company_plan_id(company_id, $2::date)),
+           unlock_quota_total) AS grant_total
+  FROM due
+), ledger AS (
+  INSERT INTO credit_ledger (company_id, kind, amount, balance_after, note)
+  SELECT company_id, 'expire', -expiring, credit_balance - expiring, 'period ended'
+  FROM due
+  WHERE expiring > 0
+  UNION ALL
+  SELECT company_id, 'grant', grant_total, kept + grant_total, 'period allowance'
+  FROM upcoming
+  WHERE grant_total > 0
+)
+UPDATE company_quotas q
+SET period_start = $2::date,
+    period_end   = $3::date,
+    unlock_quota_total = n.grant_total,
+    unlock_quota_used = 0,
+    credit_balance = n.kept + n.grant_total,
+    updated_at = now()
+FROM upcoming n
+WHERE q.company_id = n.company_id
+RETURNING q.company_id, q.unlock_quota_total, q.unlock_quota_used, q.period_start, q.period_end, q.credit_balance;`
+	var r CompanyQuotaDetailRow
+	err := q.db.QueryRow(ctx, sql, p.CompanyID, p.PeriodStart, p.PeriodEnd).Scan(
+		&r.CompanyID, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.PeriodStart, &r.PeriodEnd, &r.CreditBalance)
+	return r, err
+}
+
+type ListCompanyQuotaPeriodsParams struct {
+	CompanyID int64
+	Limit     int32
+	Offset    int32
+}
+
+type CompanyQuotaPeriodRow struct {
+	PeriodStart      pgtype.Date
+	PeriodEnd        pgtype.Date
+	UnlockQuotaTotal int32
+	UnlockQuotaUsed  int32
+	ClosedAt         time.Time
+}
+
+func (q *Queries) ListCompanyQuotaPeriods(ctx context.Context, p ListCompanyQuotaPeriodsParams) ([]CompanyQuotaPeriodRow, error) {
+	sql := `
+SELECT period_start, period_end, unlock_quota_total, unlock_quota_used, closed_at
+FROM company_quota_periods
+WHERE company_id = $1
+ORDER BY period_start DESC
+LIMIT $2 OFFSET $3;`
+	rows, err := q.db.Query(ctx, sql, p.CompanyID, p.Limit, p.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CompanyQuotaPeriodRow
+	for rows.Next() {
+		var r CompanyQuotaPeriodRow
+		if err := rows.Scan(&r.PeriodStart, &r.PeriodEnd, &r.UnlockQuotaTotal, &r.UnlockQuotaUsed, &r.ClosedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ==================== Saved Searches ====================
+
+type SavedSearchRow struct {
+	ID        int64
+	CompanyID int64
+	HrUserID  int64
+	Name      string
+	Filters   []byte
+	Frequency string
+	Muted     bool
+	LastRunAt time.Time
+	NextRunAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+const savedSearchColumns = `
+  s.id, s.company_id, s.hr_user_id, s.name, s.filters, s.frequency, s.muted,
+  s.last_run_at, s.next_run_at, s.created_at, s.updated_at`
+
+func scanSavedSearch(row interface{ Scan(...any) error }, extra ...any) (SavedSearchRow, error) {
+	var r SavedSearchRow
+	dest := append([]any{&r.ID, &r.CompanyID, &r.HrUserID, &r.Name, &r.Filters, &r.Frequency, &r.Muted,
+		&r.LastRunAt, &r.NextRunAt, &r.CreatedAt, &r.UpdatedAt}, extra...)
+	err := row.Scan(dest...)
+	return r, err
+}
+
+type CreateSavedSearchParams struct {
+	CompanyID       int64
+	HrUserID        int64
+	Name            string
+	Filters         []byte
+	Frequency       string
+	IntervalSeconds int32
+	// MaxPerUser caps the HR user's searches; ErrNoRows is returned when it is reached
+	MaxPerUser int32
+}
+
+// CreateSavedSearch must run after LockHRUserForUpdate in the same transaction; otherwise
+// concurrent creates can all pass the MaxPerUser count.
+func (q *Queries) CreateSavedSearch(ctx context.Context, p CreateSavedSearchParams) (SavedSearchRow, error) {
+	return scanSavedSearch(q.db.QueryRow(ctx, `
+INSERT INTO saved_searches AS s (company_id, hr_user_id, name, filters, frequency, next_run_at)
+SELECT $1, $2, $3, $4::jsonb, $5, now() + ($6::int * interval '1 second')
+WHERE (SELECT count(*) FROM saved_searches WHERE hr_user_id = $2) < $7
+RETURNING`+savedSearchColumns,
+		p.CompanyID, p.HrUserID, p.Name, p.Filters, p.Frequency, p.IntervalSeconds, p.MaxPerUser))
+}
+
+func (q *Queries) ListSavedSearches(ctx context.Context, hrUserID int64) ([]SavedSearchRow, error) {
+	rows, err := q.db.Query(ctx, `
+SELECT`+savedSearchColumns+`
+FROM saved_searches s
+WHERE s.hr_user_id = $1
+ORDER BY s.created_at DESC`, hrUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]SavedSearchRow, 0)
+	for rows.Next() {
+		r, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type GetSavedSearchParams struct {
+	ID       int64
+	HrUserID int64
+}
+
+func (q *Queries) GetSavedSearch(ctx context.Context, p GetSavedSearchParams) (SavedSearchRow, error) {
+	return scanSavedSearch(q.db.QueryRow(ctx, `
+SELECT`+savedSearchColumns+`
+FROM saved_searches s
+WHERE s.id = $1 AND s.hr_user_id = $2`, p.ID, p.HrUserID))
+}
+
+type GetTelegramSavedSearchParams struct {
+	ID       int64
+	TgUserID int64
+}
+
+// GetTelegramSavedSearch loads a search owned by the active HR user linked to tgUserID.
+func (q *Queries) GetTelegramSavedSearch(ctx context.Context, p GetTelegramSavedSearchParams) (SavedSearchRow, error) {
+	return scanSavedSearch(q.db.QueryRow(ctx, `
+SELECT`+savedSearchColumns+`
+FROM saved_searches s
+JOIN hr_users h ON h.id = s.hr_user_id
+WHERE s.id = $1 AND h.tg_user_id = $2 AND h.status = 'active'`, p.ID, p.TgUserID))
+}
+
+type UpdateSavedSearchParams struct {
+	ID              int64
+	HrUserID        int64
+	Name            string
+	Filters         []byte
+	Frequency       string
+	Muted           bool
+	IntervalSeconds int32
+}
+
+// UpdateSavedSearch replaces the search's settings and reschedules it one interval after
+// its last run. Unmuting restarts the window at now(), so the next digest does not replay
+// everything that changed while the search was muted.
+func (q *Queries) UpdateSavedSearch(ctx context.Context, p UpdateSavedSearchParams) (SavedSearchRow, error) {
+	return scanSavedSearch(q.db.QueryRow(ctx, `
+UPDATE saved_searches s
+SET name = $3,
+    filters = $4::jsonb,
+    frequency = $5,
+    muted = $6,
+    last_run_at = CASE WHEN s.muted AND NOT $6 THEN now() ELSE s.last_run_at END,
+    next_run_at = CASE WHEN s.muted AND NOT $6 THEN now() ELSE s.last_run_at END
+                  + ($7::int * interval '1 second'),
+    updated_at = now()
+WHERE s.id = $1 AND s.hr_user_id = $2
+RETURNING`+savedSearchColumns,
+		p.ID, p.HrUserID, p.Name, p.Filters, p.Frequency, p.Muted, p.IntervalSeconds))
+}
+
+type DeleteSavedSearchParams struct {
+	ID       int64
+	HrUserID int64
+}
+
+// DeleteSavedSearch returns the number of deleted rows (0 if not found).
+func (q *Queries) DeleteSavedSearch(ctx context.Context, p DeleteSavedSearchParams) (int64, error) {
+	tag, err := q.db.Exec(ctx, `DELETE FROM saved_searches WHERE id = $1 AND hr_user_id = $2`, p.ID, p.HrUserID)
+	return tag.RowsAffected(), err
+}
+
+type ClaimDueSavedSearchesParams struct {
+	Limit        int32
+	LeaseSeconds int32
+}
+
+type ClaimDueSavedSearchesRow struct {
+	SavedSearchRow
+	// ChatID is the owner's Telegram user ID
+	ChatID int64
+	// RunAt is the database time of the claim; the next window starts there
+	RunAt time.Time
+}
+
+// ClaimDueSavedSearches leases due, unmuted searches of active HR users (linked to Telegram)
+// in active companies by pushing next_run_at out by the lease; a crashed worker's searches
+// become due again when it expires.
+func (q *Queries) ClaimDueSavedSearches(ctx context.Context, p ClaimDueSavedSearchesParams) ([]ClaimDueSavedSearchesRow, error) {
+	rows, err := q.db.Query(ctx, `
+UPDATE saved_searches s
+SET next_run_at = now() + ($2::int * interval '1 second')
+FROM hr_users h
+WHERE h.id = s.hr_user_id
+  AND s.id IN (
+    SELECT ss.id
+    FROM saved_searches ss
+    JOIN hr_users hu ON hu.id = ss.hr_user_id
+    JOIN companies co ON co.id = ss.company_id
+    WHERE NOT ss.muted
+      AND ss.next_run_at <= now()
+      AND hu.status = 'active'
+      AND hu.tg_user_id IS NOT NULL
+      AND co.status = 'active'
+    ORDER BY ss.next_run_at
+    LIMIT $1
+    FOR UPDATE OF ss SKIP LOCKED
+  )
+RETURNING`+savedSearchColumns+`, h.tg_user_id, now()`, p.Limit, p.LeaseSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ClaimDueSavedSearchesRow
+	for rows.Next() {
+		var r ClaimDueSavedSearchesRow
+		r.SavedSearchRow, err = scanSavedSearch(rows, &r.ChatID, &r.RunAt)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+type CompleteSavedSearchRunParams struct {
+	ID              int64
+	RunAt           time.Time
+	IntervalSeconds int32
+	// Digest is queued to ChatID as a bot_outbox message of Kind; nil queues nothing
+	ChatID int64
+	Kind   string
+	Digest []byte
+}
+
+// CompleteSavedSearchRun moves the search's window to RunAt and queues its digest in the
+// same statement, so a digest is never sent twice nor lost between the two.
+func (q *Queries) CompleteSavedSearchRun(ctx context.Context, p CompleteSavedSearchRunParams) error {
+	_, err := q.db.Exec(ctx, `
+WITH run AS (
+  UPDATE saved_searches
+  SET last_run_at = $2,
+      next_run_at = $2 + ($3::int * interval '1 second')
+  WHERE id = $1
+  RETURNING id
+)
+INSERT INTO bot_outbox (chat_id, kind, payload)
+SELECT $4, $5, $6::jsonb
+FROM run
+WHERE $6::jsonb IS NOT NULL`, p.ID, p.RunAt, p.IntervalSeconds, p.ChatID, p.Kind, p.Digest)
+	return err
+}
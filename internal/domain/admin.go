@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// AdminClaims identify a platform operator (allowlisted Telegram account).
+// They are not tied to any company.
+type AdminClaims struct {
+	TgUserID int64  `json:"tg_user_id"`
+	Username string `json:"username"`
+}
+
+type AdminCompany struct {
+	ID               int64     `json:"id"`
+	Name             string    `json:"name"`
+	Status           string    `json:"status"`
+	MemberCount      int64     `json:"member_count"`
+	QuotaConfigured  bool      `json:"quota_configured"`
+	UnlockQuotaTotal int32     `json:"unlock_quota_total"`
+	UnlockQuotaUsed  int32     `json:"unlock_quota_used"`
+	PlanCode         string    `json:"plan_code"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type AdminHRUser struct {
+	ID          int64     `json:"id"`
+	CompanyID   int64     `json:"company_id"`
+	CompanyName string    `json:"company_name"`
+	TgUsername  string    `json:"tg_username"`
+	DisplayName string    `json:"display_name"`
+	Role        string    `json:"role"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
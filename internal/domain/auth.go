@@ -5,4 +5,31 @@ type HRClaims struct {
     CompanyID int64  `json:"company_id"`
     Status    string `json:"status"` // pending/active/blocked
     Role      string `json:"role"`   // owner/admin/recruiter
+    // SessionVersion must match hr_users.session_version ("sv" claim)
+    SessionVersion int32 `json:"session_version"`
+}
+
+// HR user roles (hr_users.role)
+const (
+    RoleOwner     = "owner"
+    RoleAdmin     = "admin"
+    RoleRecruiter = "recruiter"
+)
+
+func IsValidRole(role string) bool {
+    switch role {
+    case RoleOwner, RoleAdmin, RoleRecruiter:
+        return true
+    }
+    return false
+}
+
+// HRUserState is the authoritative, per-request view of an HR user (hr_users row),
+// used to override whatever status/role a token was issued with.
+type HRUserState struct {
+    HRUserID       int64  `json:"hr_user_id"`
+    CompanyID      int64  `json:"company_id"`
+    Status         string `json:"status"`
+    Role           string `json:"role"`
+    SessionVersion int32  `json:"session_version"`
 }
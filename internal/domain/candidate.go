@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type CandidateCard struct {
     Slug              string   `json:"slug"`
     DisplayName       string   `json:"display_name"`
@@ -12,7 +14,12 @@ type CandidateCard struct {
     BCExperience      bool     `json:"bc_experience"`
     Summary           string   `json:"summary"`
     UnlockedContact   bool     `json:"unlocked_contact"`
+    UnlockCost        int32    `json:"unlock_cost"` // credits a first unlock spends
     Skills            []string `json:"skills"`
+    // Pipelines lists the stages the candidate is in across the company's pipelines
+    Pipelines []CandidatePipelineStage `json:"pipelines"`
+    // Tags are the company's private tags on the candidate
+    Tags []string `json:"tags"`
 }
 
 type CandidateContact struct {
@@ -24,17 +31,52 @@ type CandidateContact struct {
 type CandidateDetail struct {
     CandidateCard
     Contact *CandidateContact `json:"contact,omitempty"`
+    // ContactMode is "request" when unlocking needs the candidate's approval
+    ContactMode    string          `json:"contact_mode"`
+    ContactRequest *ContactRequest `json:"contact_request,omitempty"`
+    // ContactReport is the company's report of this contact as invalid, if any
+    ContactReport *ContactReport `json:"contact_report,omitempty"`
+    // Notes are the company's private recruiter notes, newest first
+    Notes []CandidateNote `json:"notes"`
+}
+
+// CandidateNote is an internal note left by a recruiter; only their company sees it.
+type CandidateNote struct {
+    ID         int64     `json:"id"`
+    Body       string    `json:"body"`
+    AuthorID   int64     `json:"author_id"`
+    AuthorName string    `json:"author_name"`
+    CreatedAt  time.Time `json:"created_at"`
+    UpdatedAt  time.Time `json:"updated_at"`
 }
 
+// CompanyTag is a tag in use by a company, with the number of (visible) candidates carrying it.
+type CompanyTag struct {
+    Tag   string `json:"tag"`
+    Count int32  `json:"count"`
+}
+
+const (
+    MaxCandidateNoteLen = 2000
+    MaxCandidateTagLen  = 30
+    MaxCandidateTags    = 20
+)
+
+// CandidateListFilter: the JSON form (same names as the GET /api/candidates query params)
+// is what saved searches store.
 type CandidateListFilter struct {
-    CompanyID int64
-    Q         *string
-    Skill     *string
-    English   *string
-    SalaryMin *int32
-    SalaryMax *int32
-    AvailMax  *int32
-    BC        *bool
-    Limit     int32
-    Offset    int32
+    CompanyID int64   `json:"-"`
+    Q         *string `json:"q,omitempty"`
+    Skill     *string `json:"skill,omitempty"`
+    English   *string `json:"english,omitempty"`
+    SalaryMin *int32  `json:"salary_min,omitempty"`
+    SalaryMax *int32  `json:"salary_max,omitempty"`
+    AvailMax  *int32  `json:"availability_days_max,omitempty"`
+    BC        *bool   `json:"bc_experience,omitempty"`
+    // Tag filters by one of the company's own candidate tags
+    Tag       *string `json:"tag,omitempty"`
+    // UpdatedSince limits results to candidates updated (or newly active) after it
+    UpdatedSince *time.Time `json:"-"`
+    Limit        int32      `json:"-"`
+    Offset       int32      `json:"-"`
 }
@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// CompanyInvite lets an owner attach new Telegram logins to their company.
+// Status is derived: active/expired/revoked/used_up.
+type CompanyInvite struct {
+	ID        int64      `json:"id"`
+	Code      string     `json:"code"`
+	Link      string     `json:"link,omitempty"`
+	Role      string     `json:"role"`
+	MaxUses   int32      `json:"max_uses"`
+	UsedCount int32      `json:"used_count"`
+	Status    string     `json:"status"`
+	CreatedBy int64      `json:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+const (
+	InviteStatusActive  = "active"
+	InviteStatusExpired = "expired"
+	InviteStatusRevoked = "revoked"
+	InviteStatusUsedUp  = "used_up"
+)
+
+// CompanyMember is an HR user as seen by their company's owners.
+type CompanyMember struct {
+	ID          int64     `json:"id"`
+	TgUsername  string    `json:"tg_username"`
+	DisplayName string    `json:"display_name"`
+	Role        string    `json:"role"`
+	Status      string    `json:"status"` // pending/active/blocked
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// HR user statuses (hr_users.status). Removed members keep their row for history
+// but lose their Telegram link.
+const (
+	HRStatusPending = "pending"
+	HRStatusActive  = "active"
+	HRStatusBlocked = "blocked"
+	HRStatusRemoved = "removed"
+)
+
+// Company statuses (companies.status)
+const (
+	CompanyStatusActive  = "active"
+	CompanyStatusPending = "pending"
+	CompanyStatusBlocked = "blocked"
+)
+
+func IsValidCompanyStatus(status string) bool {
+	switch status {
+	case CompanyStatusActive, CompanyStatusPending, CompanyStatusBlocked:
+		return true
+	}
+	return false
+}
+
+// QuotaPeriod is a finished unlock quota period of a company (dates are YYYY-MM-DD,
+// period_end exclusive).
+type QuotaPeriod struct {
+	PeriodStart      string    `json:"period_start"`
+	PeriodEnd        string    `json:"period_end"`
+	UnlockQuotaTotal int32     `json:"unlock_quota_total"`
+	UnlockQuotaUsed  int32     `json:"unlock_quota_used"`
+	ClosedAt         time.Time `json:"closed_at"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Reasons a company can give for reporting an unlocked contact (contact_reports.reason)
+const (
+	ContactReportTelegramInvalid = "telegram_invalid"
+	ContactReportPhoneInvalid    = "phone_invalid"
+	ContactReportEmailInvalid    = "email_invalid"
+	ContactReportOther           = "other"
+)
+
+// Contact report statuses
+const (
+	ContactReportPending = "pending"
+	// ContactReportRefunded: the report was upheld and the unlock's cost given back
+	ContactReportRefunded = "refunded"
+	ContactReportRejected = "rejected"
+)
+
+// ContactReportWindow is how long after an unlock its contact can be reported.
+const ContactReportWindow = 30 * 24 * time.Hour
+
+// MaxContactReportDetailsLen caps the reporter's details and the reviewer's note.
+const MaxContactReportDetailsLen = 1000
+
+// ValidContactReportReason reports whether reason is one of the ContactReport* reasons.
+func ValidContactReportReason(reason string) bool {
+	switch reason {
+	case ContactReportTelegramInvalid, ContactReportPhoneInvalid, ContactReportEmailInvalid, ContactReportOther:
+		return true
+	}
+	return false
+}
+
+// ContactReport is a company's report that an unlocked contact does not work.
+type ContactReport struct {
+	ID            int64      `json:"id"`
+	CandidateSlug string     `json:"candidate_slug"`
+	Reason        string     `json:"reason"`
+	Details       string     `json:"details,omitempty"`
+	Status        string     `json:"status"`
+	RefundAmount  int32      `json:"refund_amount"`
+	ReviewNote    string     `json:"review_note,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// AdminContactReport is a report in the platform review queue, with the contact as it is now.
+type AdminContactReport struct {
+	ContactReport
+	CompanyID     int64            `json:"company_id"`
+	CompanyName   string           `json:"company_name"`
+	CandidateName string           `json:"candidate_name"`
+	ReporterID    int64            `json:"reporter_id,omitempty"`
+	ReporterName  string           `json:"reporter_name,omitempty"`
+	UnlockCost    int32            `json:"unlock_cost"`
+	UnlockedAt    time.Time        `json:"unlocked_at"`
+	Contact       CandidateContact `json:"contact"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Candidate contact modes (candidates.contact_mode)
+const (
+	// ContactModeOpen: an unlock reveals the contact immediately
+	ContactModeOpen = "open"
+	// ContactModeRequest: an unlock asks the candidate first; quota is charged on accept
+	ContactModeRequest = "request"
+)
+
+// Contact request statuses. Expired is derived from expires_at, never stored.
+const (
+	ContactRequestPending  = "pending"
+	ContactRequestAccepted = "accepted"
+	ContactRequestDeclined = "declined"
+	ContactRequestExpired  = "expired"
+)
+
+// ContactRequestTTL is how long a candidate has to answer a contact request.
+const ContactRequestTTL = 7 * 24 * time.Hour
+
+// ContactRequest is a company's request to see a request-mode candidate's contact.
+type ContactRequest struct {
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+// ContactRequestStatus derives the effective status of a stored request.
+func ContactRequestStatus(stored string, expiresAt, now time.Time) string {
+	if stored == ContactRequestPending && !now.Before(expiresAt) {
+		return ContactRequestExpired
+	}
+	return stored
+}
+
+// UnlockResult is the outcome of an unlock: the contact, or the pending request if the
+// candidate must approve the company first.
+type UnlockResult struct {
+	Contact        *CandidateContact
+	ContactRequest *ContactRequest
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Credit ledger entry kinds (credit_ledger.kind)
+const (
+	// CreditGrant is a period's plan allowance
+	CreditGrant = "grant"
+	// CreditTopUp is a purchase or manual top-up; it does not expire
+	CreditTopUp  = "topup"
+	CreditUnlock = "unlock"
+	CreditRefund = "refund"
+	// CreditExpire removes what is left of a period's grant when the period ends
+	CreditExpire = "expire"
+	// CreditAdjustment is a correction by a platform admin (quota or plan change)
+	CreditAdjustment = "adjustment"
+)
+
+// CreditEntry is one row of a company's append-only credit ledger. Amount is signed.
+type CreditEntry struct {
+	ID            int64     `json:"id"`
+	Kind          string    `json:"kind"`
+	Amount        int32     `json:"amount"`
+	BalanceAfter  int32     `json:"balance_after"`
+	CandidateSlug string    `json:"candidate_slug,omitempty"`
+	HRUserID      int64     `json:"hr_user_id,omitempty"`
+	Ref           string    `json:"ref,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CreditBalance is a company's spendable credits. Balance is the running balance kept with
+// the quota; LedgerBalance re-adds the ledger and Reconciled reports whether both agree.
+type CreditBalance struct {
+	Balance       int32 `json:"balance"`
+	LedgerBalance int64 `json:"ledger_balance"`
+	Reconciled    bool  `json:"reconciled"`
+	// PeriodGrant and PeriodSpent describe the current quota period; unspent grant credits
+	// expire at PeriodEnd
+	PeriodGrant int32  `json:"period_grant"`
+	PeriodSpent int32  `json:"period_spent"`
+	PeriodStart string `json:"period_start"`
+	PeriodEnd   string `json:"period_end"`
+}
+
+// MaxCandidateUnlockCost caps what a single unlock can cost.
+const MaxCandidateUnlockCost = 100
+
+// Limits of a manual top-up
+const (
+	MaxCreditTopUp   = 100000
+	MaxCreditRefLen  = 128
+	MaxCreditNoteLen = 200
+)
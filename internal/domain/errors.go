@@ -6,4 +6,43 @@ var (
     ErrNotFound           = errors.New("not_found")
     ErrQuotaExceeded      = errors.New("quota_exceeded")
     ErrQuotaNotConfigured = errors.New("quota_not_configured")
+    ErrInviteInvalid      = errors.New("invite_invalid")
+    // ErrInviteAlreadyMember: the user belongs to a company with teammates or history and
+    // cannot move to the inviting one
+    ErrInviteAlreadyMember = errors.New("invite_already_member")
+    ErrForbidden          = errors.New("forbidden")
+    ErrCannotModifySelf   = errors.New("cannot_modify_self")
+    ErrLastOwner          = errors.New("last_owner")
+    ErrCandidateExists    = errors.New("candidate_exists")
+    // ErrContactRequestPending: the candidate must accept before the contact is revealed
+    ErrContactRequestPending  = errors.New("contact_request_pending")
+    ErrContactRequestDeclined = errors.New("contact_request_declined")
+    // ErrContactRequestClosed: the request was already answered or has expired
+    ErrContactRequestClosed = errors.New("contact_request_closed")
+    // ErrSavedSearchLimit: the HR user already has service.MaxSavedSearches searches
+    ErrSavedSearchLimit = errors.New("saved_search_limit")
+    // ErrPipelineStageNotEmpty: a stage cannot be removed while candidates are in it
+    ErrPipelineStageNotEmpty = errors.New("pipeline_stage_not_empty")
+    // ErrPipelineStageInvalid: the stage does not belong to the pipeline
+    ErrPipelineStageInvalid = errors.New("pipeline_stage_invalid")
+    // ErrCandidateTagLimit: the candidate already has MaxCandidateTags tags
+    ErrCandidateTagLimit = errors.New("candidate_tag_limit")
+    // ErrSeatLimit: the company's plan allows no more active HR users
+    ErrSeatLimit = errors.New("seat_limit")
+    // ErrPlanExists: a plan with this code already exists
+    ErrPlanExists = errors.New("plan_exists")
+    // ErrContactNotUnlocked: only unlocked contacts can be reported
+    ErrContactNotUnlocked = errors.New("contact_not_unlocked")
+    // ErrContactReportExists: the company already reported this candidate's contact
+    ErrContactReportExists = errors.New("contact_report_exists")
+    // ErrContactReportWindow: the unlock is older than ContactReportWindow
+    ErrContactReportWindow = errors.New("contact_report_window_closed")
+    // ErrContactReportClosed: the report was already reviewed
+    ErrContactReportClosed = errors.New("contact_report_closed")
+    // ErrPaymentProviderUnavailable: the provider is not enabled or does not sell the pack
+    ErrPaymentProviderUnavailable = errors.New("payment_provider_unavailable")
+    // ErrPaymentProvider: the provider failed to start the checkout
+    ErrPaymentProvider = errors.New("payment_provider_error")
+    // ErrPaymentAmountMismatch: the provider reported a different amount than was charged
+    ErrPaymentAmountMismatch = errors.New("payment_amount_mismatch")
 )
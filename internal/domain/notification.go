@@ -0,0 +1,40 @@
+package domain
+
+// Kinds of bot_outbox messages
+const (
+	BotOutboxCandidateUnlocked = "candidate_unlocked"
+	BotOutboxContactRequest    = "contact_request"
+	BotOutboxSavedSearchDigest = "saved_search_digest"
+)
+
+// UnlockNotification is the bot_outbox payload of BotOutboxCandidateUnlocked.
+type UnlockNotification struct {
+	CandidateID int64  `json:"candidate_id"`
+	CompanyName string `json:"company_name"`
+	DesiredRole string `json:"desired_role"`
+	Timezone    string `json:"timezone"`
+}
+
+// ContactRequestNotification is the bot_outbox payload of BotOutboxContactRequest.
+type ContactRequestNotification struct {
+	RequestID   int64  `json:"request_id"`
+	CompanyName string `json:"company_name"`
+	DesiredRole string `json:"desired_role"`
+}
+
+// SavedSearchDigest is the bot_outbox payload of BotOutboxSavedSearchDigest.
+type SavedSearchDigest struct {
+	SearchID   int64              `json:"search_id"`
+	Name       string             `json:"name"`
+	Candidates []SavedSearchMatch `json:"candidates"`
+	// More is set when there were more matches than listed
+	More bool `json:"more,omitempty"`
+	// URL opens the web app; only set when it can be used in an inline button (https)
+	URL string `json:"url,omitempty"`
+}
+
+type SavedSearchMatch struct {
+	Slug        string `json:"slug"`
+	DisplayName string `json:"display_name"`
+	DesiredRole string `json:"desired_role"`
+}
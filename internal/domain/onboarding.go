@@ -0,0 +1,72 @@
+package domain
+
+// Candidate onboarding steps, in the order the bot asks them.
+const (
+	OnboardingStepDesiredRole  = "desired_role"
+	OnboardingStepEnglishLevel = "english_level"
+	OnboardingStepSalary       = "salary"
+	OnboardingStepAvailability = "availability"
+	OnboardingStepTimezone     = "timezone"
+	OnboardingStepSkills       = "skills"
+	OnboardingStepConsent      = "consent"
+)
+
+// English levels accepted for candidates.english_level.
+var EnglishLevels = []string{"none", "basic", "working", "fluent"}
+
+func IsValidEnglishLevel(level string) bool {
+	for _, l := range EnglishLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// OnboardingDraft holds the answers collected so far (stored as JSONB between messages).
+// CandidateID is set when an existing profile is being edited (/edit).
+type OnboardingDraft struct {
+	CandidateID      int64    `json:"candidate_id,omitempty"`
+	DesiredRole      string   `json:"desired_role,omitempty"`
+	EnglishLevel     string   `json:"english_level,omitempty"`
+	SalaryMin        int32    `json:"salary_min,omitempty"`
+	SalaryMax        int32    `json:"salary_max,omitempty"`
+	AvailabilityDays int32    `json:"availability_days,omitempty"`
+	Timezone         string   `json:"timezone,omitempty"`
+	Skills           []string `json:"skills,omitempty"`
+}
+
+// Editing reports whether the draft updates an existing candidate.
+func (d OnboardingDraft) Editing() bool {
+	return d.CandidateID != 0
+}
+
+// Candidate statuses (candidates.status).
+const (
+	CandidateStatusActive  = "active"
+	CandidateStatusHidden  = "hidden"
+	CandidateStatusDeleted = "deleted"
+)
+
+// CandidateProfile is a candidate's own view of their record in the bot.
+type CandidateProfile struct {
+	ID          int64
+	Slug        string
+	DisplayName string
+	Status      string
+	TgUsername  string
+	// NotifyOnUnlock: the candidate opted in to a bot message when a company unlocks their contact
+	NotifyOnUnlock bool
+	// ContactMode is domain.ContactModeOpen or domain.ContactModeRequest
+	ContactMode string
+	OnboardingDraft
+}
+
+// NewCandidate is a completed onboarding, ready to be inserted.
+type NewCandidate struct {
+	TgUserID    int64
+	TgUsername  string
+	Slug        string
+	DisplayName string
+	OnboardingDraft
+}
@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// Payment providers (payments.provider)
+const (
+	// PaymentProviderTelegram is an invoice paid inside Telegram: Stars, or a Telegram Payments
+	// provider when one is configured
+	PaymentProviderTelegram = "telegram"
+	// PaymentProviderCard is a card processor's hosted checkout confirmed by signed webhooks
+	PaymentProviderCard = "card"
+	// PaymentProviderFake speaks the card protocol without a network, for development and tests
+	PaymentProviderFake = "fake"
+)
+
+// Payment statuses; also the types of PaymentEvent
+const (
+	PaymentPending   = "pending"
+	PaymentSucceeded = "succeeded"
+	PaymentFailed    = "failed"
+)
+
+// CurrencyStars is the currency code of Telegram Stars.
+const CurrencyStars = "XTR"
+
+// CreditPack is a bundle of non-expiring credits a company can buy. Prices lists what it
+// costs with each enabled provider.
+type CreditPack struct {
+	ID      int64       `json:"id"`
+	Code    string      `json:"code"`
+	Name    string      `json:"name"`
+	Credits int32       `json:"credits"`
+	Prices  []PackPrice `json:"prices"`
+}
+
+// PackPrice is a pack's price with one provider. Amount is in the currency's minor units
+// (fen for CNY, whole Stars for XTR).
+type PackPrice struct {
+	Provider string `json:"provider"`
+	Amount   int32  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Payment is a company's purchase of a credit pack.
+type Payment struct {
+	ID            int64      `json:"id"`
+	PackCode      string     `json:"pack_code"`
+	Provider      string     `json:"provider"`
+	Credits       int32      `json:"credits"`
+	Amount        int32      `json:"amount"`
+	Currency      string     `json:"currency"`
+	Status        string     `json:"status"`
+	CheckoutURL   string     `json:"checkout_url,omitempty"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+}
+
+// PaymentEvent is a provider's notification that a payment succeeded or failed. ID is unique
+// per provider and repeated when the provider redelivers the notification. The payment is
+// identified by PaymentID or, if that is 0, by the provider's ProviderRef.
+type PaymentEvent struct {
+	Provider      string
+	ID            string
+	Type          string // PaymentSucceeded or PaymentFailed
+	PaymentID     int64
+	ProviderRef   string
+	Amount        int32
+	Currency      string
+	FailureReason string
+}
+
+// PaymentOutcome is what applying a PaymentEvent does to its payment.
+type PaymentOutcome int
+
+const (
+	// PaymentUnchanged: the payment is already settled, e.g. a late failure after a success
+	PaymentUnchanged PaymentOutcome = iota
+	// PaymentCredit marks the payment succeeded and adds its credits
+	PaymentCredit
+	// PaymentFail marks a pending payment failed
+	PaymentFail
+	// PaymentMismatch fails the payment without credits: a different amount or currency was
+	// paid than was charged
+	PaymentMismatch
+)
+
+// Outcome decides what ev does to a payment in the given status that charged amount in
+// currency. A failed payment can still succeed, e.g. when the buyer retries on the same
+// checkout; a succeeded one is never changed again.
+func (ev PaymentEvent) Outcome(status string, amount int32, currency string) PaymentOutcome {
+	switch {
+	case status == PaymentSucceeded:
+		return PaymentUnchanged
+	case ev.Type != PaymentSucceeded:
+		if status == PaymentPending {
+			return PaymentFail
+		}
+		return PaymentUnchanged
+	case ev.Amount != amount || ev.Currency != currency:
+		return PaymentMismatch
+	}
+	return PaymentCredit
+}
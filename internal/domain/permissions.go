@@ -0,0 +1,45 @@
+package domain
+
+// Permission is an action an HR user may perform inside their company.
+type Permission string
+
+const (
+    PermCandidatesRead   Permission = "candidates.read"
+    PermCandidatesUnlock Permission = "candidates.unlock"
+    PermAuditRead        Permission = "audit.read"
+    PermMembersManage    Permission = "members.manage" // members + invites
+    PermQuotaManage      Permission = "quota.manage"
+    // PermPipelinesManage covers creating, configuring and deleting pipelines; moving
+    // candidates between stages only needs PermCandidatesRead
+    PermPipelinesManage  Permission = "pipelines.manage"
+)
+
+// rolePermissions is the permission matrix:
+//   owner     - everything
+//   admin     - browse/unlock + audit logs + pipeline setup
+//   recruiter - browse/unlock
+var rolePermissions = map[string]map[Permission]bool{
+    RoleOwner: {
+        PermCandidatesRead:   true,
+        PermCandidatesUnlock: true,
+        PermAuditRead:        true,
+        PermMembersManage:    true,
+        PermQuotaManage:      true,
+        PermPipelinesManage:  true,
+    },
+    RoleAdmin: {
+        PermCandidatesRead:   true,
+        PermCandidatesUnlock: true,
+        PermAuditRead:        true,
+        PermPipelinesManage:  true,
+    },
+    RoleRecruiter: {
+        PermCandidatesRead:   true,
+        PermCandidatesUnlock: true,
+    },
+}
+
+// RoleHasPermission reports whether role is granted perm; unknown roles get nothing.
+func RoleHasPermission(role string, perm Permission) bool {
+    return rolePermissions[role][perm]
+}
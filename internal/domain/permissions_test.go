@@ -0,0 +1,41 @@
+package domain
+
+import "testing"
+
+func TestRoleHasPermission(t *testing.T) {
+	all := []Permission{
+		PermCandidatesRead, PermCandidatesUnlock, PermAuditRead,
+		PermMembersManage, PermQuotaManage, PermPipelinesManage,
+	}
+	tests := []struct {
+		role string
+		want []Permission
+	}{
+		{RoleOwner, all},
+		{RoleAdmin, []Permission{PermCandidatesRead, PermCandidatesUnlock, PermAuditRead, PermPipelinesManage}},
+		{RoleRecruiter, []Permission{PermCandidatesRead, PermCandidatesUnlock}},
+		{"", nil},
+		{"superuser", nil},
+	}
+	for _, tt := range tests {
+		granted := make(map[Permission]bool, len(tt.want))
+		for _, p := range tt.want {
+			granted[p] = true
+		}
+		for _, p := range all {
+			if got := RoleHasPermission(tt.role, p); got != granted[p] {
+				t.Errorf("RoleHasPermission(%q, %s) = %v, want %v", tt.role, p, got, granted[p])
+			}
+		}
+	}
+}
+
+// Recruiters must not reach the team settings (members and invites are both
+// PermMembersManage), pipeline setup, billing or the audit log.
+func TestRecruiterCannotManageTeamOrPipelines(t *testing.T) {
+	for _, p := range []Permission{PermMembersManage, PermPipelinesManage, PermQuotaManage, PermAuditRead} {
+		if RoleHasPermission(RoleRecruiter, p) {
+			t.Errorf("recruiters have %s", p)
+		}
+	}
+}
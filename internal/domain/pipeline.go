@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Pipeline is a company-wide shortlist whose candidates move through ordered stages.
+type Pipeline struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Stages    []PipelineStage `json:"stages"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type PipelineStage struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	Position       int32  `json:"position"`
+	CandidateCount int32  `json:"candidate_count"`
+}
+
+// PipelineStageInput configures one stage; ID is 0 for a new stage.
+type PipelineStageInput struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// PipelineEntry is a candidate in a pipeline.
+type PipelineEntry struct {
+	Slug            string    `json:"slug"`
+	DisplayName     string    `json:"display_name"`
+	DesiredRole     string    `json:"desired_role"`
+	UnlockedContact bool      `json:"unlocked_contact"`
+	StageID         int64     `json:"stage_id"`
+	AddedAt         time.Time `json:"added_at"`
+	MovedAt         time.Time `json:"moved_at"`
+}
+
+// PipelineDetail is a pipeline with its candidates, ordered by stage.
+type PipelineDetail struct {
+	Pipeline
+	Candidates []PipelineEntry `json:"candidates"`
+}
+
+// CandidatePipelineStage is where a candidate stands in one of the company's pipelines,
+// as shown on candidate cards.
+type CandidatePipelineStage struct {
+	PipelineID   int64  `json:"pipeline_id"`
+	PipelineName string `json:"pipeline_name"`
+	StageID      int64  `json:"stage_id"`
+	Stage        string `json:"stage"`
+}
+
+// DefaultPipelineStages are used when a pipeline is created without stages.
+var DefaultPipelineStages = []string{"contacted", "interviewing", "offer", "hired", "rejected"}
+
+const (
+	MaxPipelineStages       = 20
+	MaxPipelineNameLen      = 100
+	MaxPipelineStageNameLen = 50
+)
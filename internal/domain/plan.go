@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Plan is a subscription tier. Its unlock allowance becomes a company's quota at the
+// start of each period; Features are informational flags for the frontend and sales.
+type Plan struct {
+	ID                 int64           `json:"id"`
+	Code               string          `json:"code"`
+	Name               string          `json:"name"`
+	UnlockQuotaMonthly int32           `json:"unlock_quota_monthly"`
+	SeatLimit          int32           `json:"seat_limit"` // active HR users, 0 = unlimited
+	Features           map[string]bool `json:"features"`
+	IsDefault          bool            `json:"is_default"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// CompanyPlanAssignment puts a company on a plan from EffectiveFrom (YYYY-MM-DD) until its
+// next assignment.
+type CompanyPlanAssignment struct {
+	PlanID        int64     `json:"plan_id"`
+	PlanCode      string    `json:"plan_code"`
+	EffectiveFrom string    `json:"effective_from"`
+	AssignedBy    int64     `json:"assigned_by,omitempty"` // platform admin tg_user_id
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+const (
+	MaxPlanCodeLen = 32
+	MaxPlanNameLen = 100
+)
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// SavedSearch is a named candidate filter whose new matches are sent to its owner by the
+// bot. Each run only lists candidates updated (or newly active) since the previous run.
+type SavedSearch struct {
+	ID        int64               `json:"id"`
+	Name      string              `json:"name"`
+	Filters   CandidateListFilter `json:"filters"`
+	Frequency string              `json:"frequency"`
+	Muted     bool                `json:"muted"`
+	LastRunAt time.Time           `json:"last_run_at"`
+	NextRunAt time.Time           `json:"next_run_at"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// Saved search digest frequencies (saved_searches.frequency)
+const (
+	SavedSearchHourly = "hourly"
+	SavedSearchDaily  = "daily"
+	SavedSearchWeekly = "weekly"
+)
+
+// SavedSearchInterval is the time between two digests of a frequency.
+func SavedSearchInterval(frequency string) (time.Duration, bool) {
+	switch frequency {
+	case SavedSearchHourly:
+		return time.Hour, true
+	case SavedSearchDaily:
+		return 24 * time.Hour, true
+	case SavedSearchWeekly:
+		return 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
@@ -11,11 +11,14 @@ import (
 	"tg-hr-platform/internal/db"
 	"tg-hr-platform/internal/domain"
 	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
 	"tg-hr-platform/internal/util"
 )
 
 type AccountHandler struct {
     Q *db.Queries
+    // Plans, when set, adds the company's current plan to /api/me
+    Plans *service.PlanService
 }
 
 func NewAccountHandler(q *db.Queries) *AccountHandler {
@@ -53,6 +56,18 @@ func (h *AccountHandler) GetMe(c *gin.Context) {
         }
     }
 
+    var plan *domain.Plan
+    if h.Plans != nil {
+        p, err := h.Plans.CompanyPlan(ctx, hrClaims.CompanyID)
+        if err != nil && !errors.Is(err, domain.ErrNotFound) {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+            return
+        }
+        if err == nil {
+            plan = &p
+        }
+    }
+
     periodStart := formatDate(quota.PeriodStart)
     periodEnd := formatDate(quota.PeriodEnd)
 
@@ -85,7 +100,9 @@ func (h *AccountHandler) GetMe(c *gin.Context) {
             "unlock_quota_remaining": remaining,
             "period_start":         periodStart,
             "period_end":           periodEnd,
+            "credit_balance":       quota.CreditBalance,
         },
+        "plan": plan,
     })
 }
 
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"tg-hr-platform/internal/auth"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+type AdminJWTSigner interface {
+	SignAdminClaims(claims *domain.AdminClaims) (string, error)
+}
+
+type AdminAuditSvc interface {
+	LogAdmin(adminTgUserID, companyID int64, action, targetType, targetID string, meta map[string]any)
+}
+
+// AdminAuthHandler logs platform operators in with the same Telegram login widget,
+// restricted to an allowlist of Telegram user IDs.
+type AdminAuthHandler struct {
+	telegramVerifier *auth.TelegramVerifier
+	jwtSigner        AdminJWTSigner
+	allowed          map[int64]bool
+	cookieSecure     bool
+}
+
+func NewAdminAuthHandler(telegramVerifier *auth.TelegramVerifier, jwtSigner AdminJWTSigner, allowed map[int64]bool, cookieSecure bool) *AdminAuthHandler {
+	return &AdminAuthHandler{
+		telegramVerifier: telegramVerifier,
+		jwtSigner:        jwtSigner,
+		allowed:          allowed,
+		cookieSecure:     cookieSecure,
+	}
+}
+
+// TelegramLogin handles platform admin login
+// POST /admin/auth/telegram/login
+// Body: same as /auth/telegram/login
+func (h *AdminAuthHandler) TelegramLogin(c *gin.Context) {
+	var data auth.TelegramAuthData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if err := h.telegramVerifier.VerifyAuthData(&data); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_auth_data"})
+		return
+	}
+	if !h.allowed[data.ID] {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	tokenStr, err := h.jwtSigner.SignAdminClaims(&domain.AdminClaims{TgUserID: data.ID, Username: data.Username})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "token_generation_failed"})
+		return
+	}
+
+	c.SetCookie(
+		middleware.AdminCookieName,
+		tokenStr,
+		int(AdminTokenTTL.Seconds()),
+		"/admin",
+		"",
+		h.cookieSecure,
+		true,
+	)
+	c.JSON(http.StatusOK, gin.H{"success": true, "tg_user_id": data.ID})
+}
+
+type AdminHandler struct {
+	Svc            *service.AdminService
+	Plans          *service.PlanService
+	Credits        *service.CreditService
+	ContactReports *service.ContactReportService
+	Audit          AdminAuditSvc
+}
+
+type adminStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type adminQuotaRequest struct {
+	UnlockQuotaTotal *int32 `json:"unlock_quota_total"`
+	UnlockQuotaUsed  *int32 `json:"unlock_quota_used"`
+	PeriodEnd        string `json:"period_end"` // YYYY-MM-DD
+}
+
+func adminClaims(c *gin.Context) *domain.AdminClaims {
+	return c.MustGet(middleware.CtxAdminClaimsKey).(*domain.AdminClaims)
+}
+
+// ListCompanies lists all companies with member counts and quota
+// GET /admin/companies?status=&q=&page=&page_size=
+func (h *AdminHandler) ListCompanies(c *gin.Context) {
+	page, pageSize, limit, offset := parsePagination(c)
+	items, err := h.Svc.ListCompanies(c.Request.Context(), strPtr(c.Query("status")), strPtr(c.Query("q")), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "page": page, "page_size": pageSize})
+}
+
+// UpdateCompanyStatus sets companies.status
+// PATCH /admin/companies/:id/status
+// Body: { "status": "active|pending|blocked" }
+func (h *AdminHandler) UpdateCompanyStatus(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	var req adminStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !domain.IsValidCompanyStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_status"})
+		return
+	}
+
+	prev, err := h.Svc.SetCompanyStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, id, "admin.company.status", "company", c.Param("id"),
+			map[string]any{"from": prev, "to": req.Status})
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "status": req.Status})
+}
+
+// ListHRUsers lists HR users across companies (e.g. ?status=pending)
+// GET /admin/hr-users?status=&company_id=&page=&page_size=
+func (h *AdminHandler) ListHRUsers(c *gin.Context) {
+	page, pageSize, limit, offset := parsePagination(c)
+	var companyID *int64
+	if v := c.Query("company_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_company_id"})
+			return
+		}
+		companyID = &id
+	}
+
+	items, err := h.Svc.ListHRUsers(c.Request.Context(), strPtr(c.Query("status")), companyID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "page": page, "page_size": pageSize})
+}
+
+// UpdateHRUserStatus approves or blocks an HR user in any company
+// PATCH /admin/hr-users/:id/status
+// Body: { "status": "active|blocked" }
+func (h *AdminHandler) UpdateHRUserStatus(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	var req adminStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil ||
+		(req.Status != domain.HRStatusActive && req.Status != domain.HRStatusBlocked) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_status"})
+		return
+	}
+
+	companyID, prev, err := h.Svc.SetHRUserStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, companyID, "admin.hr_user.status", "hr_user", c.Param("id"),
+			map[string]any{"from": prev, "to": req.Status})
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "status": req.Status})
+}
+
+// RevokeHRUserSessions signs an HR user out on every device (e.g. a compromised account)
+// POST /admin/hr-users/:id/revoke-sessions
+func (h *AdminHandler) RevokeHRUserSessions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	companyID, err := h.Svc.RevokeHRUserSessions(c.Request.Context(), id)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, companyID, "admin.hr_user.revoke_sessions", "hr_user", c.Param("id"), nil)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// UpdateCompanyQuota creates or adjusts a company's quota
+// PUT /admin/companies/:id/quota
+// Body: { "unlock_quota_total": 50, "unlock_quota_used": 0, "period_end": "2026-03-01" } (all optional)
+func (h *AdminHandler) UpdateCompanyQuota(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	var req adminQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if (req.UnlockQuotaTotal != nil && *req.UnlockQuotaTotal < 0) || (req.UnlockQuotaUsed != nil && *req.UnlockQuotaUsed < 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_quota"})
+		return
+	}
+	var periodEnd pgtype.Date
+	if req.PeriodEnd != "" {
+		t, err := time.Parse("2006-01-02", req.PeriodEnd)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_period_end"})
+			return
+		}
+		periodEnd = pgtype.Date{Time: t, Valid: true}
+	}
+
+	quota, err := h.Svc.AdjustQuota(c.Request.Context(), id, req.UnlockQuotaTotal, req.UnlockQuotaUsed, periodEnd)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, id, "admin.quota.adjust", "company", c.Param("id"),
+			map[string]any{"unlock_quota_total": quota.UnlockQuotaTotal, "unlock_quota_used": quota.UnlockQuotaUsed, "period_end": formatDate(quota.PeriodEnd)})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"company_id":         quota.CompanyID,
+		"unlock_quota_total": quota.UnlockQuotaTotal,
+		"unlock_quota_used":  quota.UnlockQuotaUsed,
+		"period_start":       formatDate(quota.PeriodStart),
+		"period_end":         formatDate(quota.PeriodEnd),
+	})
+}
+
+// EnterImpersonation is the middleware.Impersonate hook: rejects unknown companies and
+// records every support read in the target company's audit log.
+func (h *AdminHandler) EnterImpersonation(c *gin.Context, admin *domain.AdminClaims, companyID int64) error {
+	if err := h.Svc.CompanyExists(c.Request.Context(), companyID); err != nil {
+		return err
+	}
+	if h.Audit != nil {
+		h.Audit.LogAdmin(admin.TgUserID, companyID, "admin.impersonate", "company", strconv.FormatInt(companyID, 10),
+			map[string]any{"path": c.Request.URL.Path})
+	}
+	return nil
+}
+
+func writeAdminError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		return
+	}
+	if errors.Is(err, domain.ErrPlanExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": "plan_exists"})
+		return
+	}
+	if errors.Is(err, domain.ErrSeatLimit) {
+		c.JSON(http.StatusConflict, gin.H{"error": "seat_limit"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+)
+
+type adminContactReportRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// ListContactReports is the review queue of reported contacts, oldest first
+// GET /admin/contact-reports?status=pending&page=&page_size=
+func (h *AdminHandler) ListContactReports(c *gin.Context) {
+	page, pageSize, limit, offset := parsePagination(c)
+
+	items, err := h.ContactReports.List(c.Request.Context(), strPtr(c.Query("status")), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "page": page, "page_size": pageSize})
+}
+
+// ResolveContactReport refunds or rejects a pending report
+// PATCH /admin/contact-reports/:id
+// Body: { "status": "refunded|rejected", "note": "username confirmed deleted" } (note optional)
+func (h *AdminHandler) ResolveContactReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	var req adminContactReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil ||
+		(req.Status != domain.ContactReportRefunded && req.Status != domain.ContactReportRejected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_status"})
+		return
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > domain.MaxContactReportDetailsLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_note"})
+		return
+	}
+
+	refund := req.Status == domain.ContactReportRefunded
+	companyID, report, err := h.ContactReports.Resolve(c.Request.Context(), id, adminClaims(c).TgUserID, refund, note)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrContactReportClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": "contact_report_closed"})
+		case errors.Is(err, domain.ErrQuotaNotConfigured):
+			c.JSON(http.StatusConflict, gin.H{"error": "quota_not_configured"})
+		default:
+			writeAdminError(c, err)
+		}
+		return
+	}
+
+	if h.Audit != nil {
+		action := "admin.contact_report.reject"
+		if refund {
+			action = "admin.contact_report.refund"
+		}
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, companyID, action, "candidate", report.CandidateSlug,
+			map[string]any{"report_id": report.ID, "refund_amount": report.RefundAmount})
+	}
+	c.JSON(http.StatusOK, report)
+}
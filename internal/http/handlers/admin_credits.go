@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+)
+
+type adminCreditTopUpRequest struct {
+	Amount int32  `json:"amount"`
+	Note   string `json:"note"`
+	// Ref is an optional external reference (e.g. an invoice number); repeating it is a no-op
+	Ref string `json:"ref"`
+}
+
+type adminUnlockCostRequest struct {
+	Cost int32 `json:"cost"`
+}
+
+// TopUpCompanyCredits adds non-expiring credits to a company
+// POST /admin/companies/:id/credits
+// Body: { "amount": 50, "note": "invoice 2026-031", "ref": "inv-2026-031" } (note and ref optional)
+func (h *AdminHandler) TopUpCompanyCredits(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	var req adminCreditTopUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if req.Amount <= 0 || req.Amount > domain.MaxCreditTopUp {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_amount"})
+		return
+	}
+	ref := strings.TrimSpace(req.Ref)
+	if len(ref) > domain.MaxCreditRefLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_ref"})
+		return
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > domain.MaxCreditNoteLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_note"})
+		return
+	}
+
+	entry, added, err := h.Credits.TopUp(c.Request.Context(), id, req.Amount, ref, note)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if added && h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, id, "admin.credits.topup", "company", c.Param("id"),
+			map[string]any{"amount": entry.Amount, "balance_after": entry.BalanceAfter, "ref": entry.Ref})
+	}
+	c.JSON(http.StatusOK, gin.H{"entry": entry, "added": added})
+}
+
+// UpdateCandidateUnlockCost sets how many credits unlocking a candidate's contact costs
+// PUT /admin/candidates/:slug/unlock-cost
+// Body: { "cost": 3 }
+func (h *AdminHandler) UpdateCandidateUnlockCost(c *gin.Context) {
+	var req adminUnlockCostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if req.Cost <= 0 || req.Cost > domain.MaxCandidateUnlockCost {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_cost"})
+		return
+	}
+
+	slug := c.Param("slug")
+	prev, err := h.Credits.SetUnlockCost(c.Request.Context(), slug, req.Cost)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, 0, "admin.candidate.unlock_cost", "candidate", slug,
+			map[string]any{"from": prev, "to": req.Cost})
+	}
+	c.JSON(http.StatusOK, gin.H{"slug": slug, "unlock_cost": req.Cost})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/service"
+)
+
+var planCodeRe = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type adminPlanRequest struct {
+	Code               string          `json:"code"`
+	Name               *string         `json:"name"`
+	UnlockQuotaMonthly *int32          `json:"unlock_quota_monthly"`
+	SeatLimit          *int32          `json:"seat_limit"`
+	Features           map[string]bool `json:"features"`
+	IsDefault          bool            `json:"is_default"`
+}
+
+type adminCompanyPlanRequest struct {
+	PlanID        int64  `json:"plan_id"`
+	EffectiveFrom string `json:"effective_from"` // YYYY-MM-DD, default today
+}
+
+// ListPlans lists all subscription plans
+// GET /admin/plans
+func (h *AdminHandler) ListPlans(c *gin.Context) {
+	items, err := h.Plans.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// CreatePlan adds a subscription plan
+// POST /admin/plans
+// Body: { "code": "pro", "name": "Pro", "unlock_quota_monthly": 100, "seat_limit": 10, "features": {"priority_support": true} }
+func (h *AdminHandler) CreatePlan(c *gin.Context) {
+	var req adminPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Name == nil || req.UnlockQuotaMonthly == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if len(req.Code) > domain.MaxPlanCodeLen || !planCodeRe.MatchString(req.Code) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_code"})
+		return
+	}
+	name, ok := normalizeName(*req.Name, domain.MaxPlanNameLen)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_name"})
+		return
+	}
+	if !validPlanLimits(req) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_quota"})
+		return
+	}
+	var seatLimit int32
+	if req.SeatLimit != nil {
+		seatLimit = *req.SeatLimit
+	}
+
+	plan, err := h.Plans.Create(c.Request.Context(), domain.Plan{
+		Code:               req.Code,
+		Name:               name,
+		UnlockQuotaMonthly: *req.UnlockQuotaMonthly,
+		SeatLimit:          seatLimit,
+		Features:           req.Features,
+	})
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, 0, "admin.plan.create", "plan", strconv.FormatInt(plan.ID, 10),
+			map[string]any{"code": plan.Code, "unlock_quota_monthly": plan.UnlockQuotaMonthly, "seat_limit": plan.SeatLimit})
+	}
+	c.JSON(http.StatusCreated, plan)
+}
+
+// UpdatePlan changes a plan; companies get a new allowance from their next period on
+// PATCH /admin/plans/:id
+// Body: { "name", "unlock_quota_monthly", "seat_limit", "features", "is_default": true } (all optional; code is fixed)
+func (h *AdminHandler) UpdatePlan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	var req adminPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	upd := service.PlanUpdate{
+		UnlockQuotaMonthly: req.UnlockQuotaMonthly,
+		SeatLimit:          req.SeatLimit,
+		Features:           req.Features,
+		Default:            req.IsDefault,
+	}
+	if req.Name != nil {
+		name, ok := normalizeName(*req.Name, domain.MaxPlanNameLen)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_name"})
+			return
+		}
+		upd.Name = &name
+	}
+	if !validPlanLimits(req) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_quota"})
+		return
+	}
+
+	plan, err := h.Plans.Update(c.Request.Context(), id, upd)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogAdmin(adminClaims(c).TgUserID, 0, "admin.plan.update", "plan", c.Param("id"),
+			map[string]any{"unlock_quota_monthly": plan.UnlockQuotaMonthly, "seat_limit": plan.SeatLimit, "is_default": plan.IsDefault})
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// ListCompanyPlans lists a company's plan assignments, scheduled ones included
+// GET /admin/companies/:id/plans
+func (h *AdminHandler) ListCompanyPlans(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	items, err := h.Plans.Assignments(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// AssignCompanyPlan puts a company on a plan from a date on
+// PUT /admin/companies/:id/plan
+// Body: { "plan_id": 2, "effective_from": "2026-03-01" }
+func (h *AdminHandler) AssignCompanyPlan(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+	var req adminCompanyPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.PlanID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	now := time.Now().UTC()
+	effectiveFrom := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.EffectiveFrom != "" {
+		if effectiveFrom, err = time.Parse("2006-01-02", req.EffectiveFrom); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_effective_from"})
+			return
+		}
+	}
+
+	admin := adminClaims(c)
+	plan, quota, err := h.Plans.Assign(c.Request.Context(), id, req.PlanID, effectiveFrom, admin.TgUserID)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	resp := gin.H{
+		"company_id":     id,
+		"plan":           plan,
+		"effective_from": effectiveFrom.Format("2006-01-02"),
+	}
+	meta := map[string]any{"plan_id": plan.ID, "plan_code": plan.Code, "effective_from": resp["effective_from"]}
+	if quota != nil {
+		resp["quota"] = gin.H{
+			"unlock_quota_total": quota.UnlockQuotaTotal,
+			"unlock_quota_used":  quota.UnlockQuotaUsed,
+			"period_start":       formatDate(quota.PeriodStart),
+			"period_end":         formatDate(quota.PeriodEnd),
+		}
+		meta["unlock_quota_total"] = quota.UnlockQuotaTotal
+	}
+	if h.Audit != nil {
+		h.Audit.LogAdmin(admin.TgUserID, id, "admin.company.plan", "company", c.Param("id"), meta)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func validPlanLimits(req adminPlanRequest) bool {
+	return (req.UnlockQuotaMonthly == nil || *req.UnlockQuotaMonthly >= 0) &&
+		(req.SeatLimit == nil || *req.SeatLimit >= 0)
+}
@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 
 	"tg-hr-platform/internal/auth"
 	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
 )
 
 type AuthHandler struct {
@@ -15,15 +23,70 @@ type AuthHandler struct {
 	jwtSigner        JWTSigner
 	userRepo         HRUserRepository
 	cookieSecure     bool
+	// companies, when set, rejects logins into blocked companies
+	companies middleware.CompanyStatusLookup
+	// sessions/revoker back /auth/refresh and /auth/logout
+	sessions SessionTokenVerifier
+	revoker  TokenRevoker
+	// userSessions backs /auth/logout-all
+	userSessions UserSessionRevoker
 }
 
+// SetCompanyStatusLookup enables the company status check at login
+func (h *AuthHandler) SetCompanyStatusLookup(l middleware.CompanyStatusLookup) {
+	h.companies = l
+}
+
+// SetSessionStore enables refresh-token rotation and logout revocation
+func (h *AuthHandler) SetSessionStore(sessions SessionTokenVerifier, revoker TokenRevoker) {
+	h.sessions = sessions
+	h.revoker = revoker
+}
+
+// SetUserSessionRevoker enables /auth/logout-all (bumps hr_users.session_version)
+func (h *AuthHandler) SetUserSessionRevoker(r UserSessionRevoker) {
+	h.userSessions = r
+}
+
+const (
+	accessCookieName  = "hr_auth"
+	refreshCookieName = "hr_refresh"
+	// refresh cookie is only sent to /auth/refresh and /auth/logout
+	refreshCookiePath = "/auth"
+)
+
 type JWTSigner interface {
+	// SignClaims issues a short-lived access token
 	SignClaims(claims *domain.HRClaims) (string, error)
+	// SignRefresh issues a long-lived refresh token, usable only at /auth/refresh
+	SignRefresh(claims *domain.HRClaims) (string, error)
+	AccessTTL() time.Duration
+	RefreshTTL() time.Duration
+}
+
+type SessionTokenVerifier interface {
+	ParseRefresh(token string) (*domain.HRClaims, auth.TokenMeta, error)
+	Meta(token string) (auth.TokenMeta, error)
+}
+
+// TokenRevoker denylists token IDs, e.g. cache.TokenDenylist
+type TokenRevoker interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeOnce is Revoke that reports false if jti was already revoked (atomically)
+	RevokeOnce(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+}
+
+// UserSessionRevoker invalidates every token of an HR user, e.g. service.HRUserStateService
+type UserSessionRevoker interface {
+	RevokeSessions(ctx context.Context, hrUserID int64) error
 }
 
 type HRUserRepository interface {
-	// GetOrCreateHRUserByTelegramID returns hr_user_id, company_id, status, error
-	GetOrCreateHRUserByTelegramID(userID int64, username, displayName string) (hrUserID, companyID int64, status string, err error)
+	// GetOrCreateHRUserByTelegramID returns the user's company, status, role and session_version.
+	// inviteCode is optional; new users with a valid code join the inviting company.
+	GetOrCreateHRUserByTelegramID(userID int64, username, displayName, inviteCode string) (domain.HRUserState, error)
+	// GetHRUserState returns the current hr_users state (domain.ErrNotFound if gone)
+	GetHRUserState(ctx context.Context, hrUserID int64) (domain.HRUserState, error)
 }
 
 func NewAuthHandler(telegramVerifier *auth.TelegramVerifier, jwtSigner JWTSigner, userRepo HRUserRepository, cookieSecure bool) *AuthHandler {
@@ -35,15 +98,22 @@ func NewAuthHandler(telegramVerifier *auth.TelegramVerifier, jwtSigner JWTSigner
 	}
 }
 
+// telegramLoginRequest is the login widget payload plus an optional company invite code
+type telegramLoginRequest struct {
+	auth.TelegramAuthData
+	InviteCode string `json:"invite_code"`
+}
+
 // TelegramLogin handles Telegram login widget callback
 // POST /auth/telegram/login
-// Body: { "id", "first_name", "username", "auth_date", "hash", ... }
+// Body: { "id", "first_name", "username", "auth_date", "hash", ..., "invite_code" (optional) }
 func (h *AuthHandler) TelegramLogin(c *gin.Context) {
-	var data auth.TelegramAuthData
-	if err := c.ShouldBindJSON(&data); err != nil {
+	var req telegramLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
 		return
 	}
+	data := req.TelegramAuthData
 
 	// 1. Verify Telegram auth data authenticity
 	if err := h.telegramVerifier.VerifyAuthData(&data); err != nil {
@@ -51,65 +121,320 @@ func (h *AuthHandler) TelegramLogin(c *gin.Context) {
 		return
 	}
 
+	h.signIn(c, &data, strings.TrimSpace(req.InviteCode))
+}
+
+// telegramWebAppLoginRequest carries the raw Mini App initData string
+type telegramWebAppLoginRequest struct {
+	InitData   string `json:"init_data"`
+	InviteCode string `json:"invite_code"`
+}
+
+// TelegramWebAppLogin signs in users launched from the bot's web_app button
+// POST /auth/telegram/webapp
+// Body: { "init_data": "<window.Telegram.WebApp.initData>", "invite_code" (optional) }
+// The invite code falls back to the Mini App start_param if that is an invite code.
+func (h *AuthHandler) TelegramWebAppLogin(c *gin.Context) {
+	var req telegramWebAppLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.InitData == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	webApp, err := h.telegramVerifier.VerifyWebAppInitData(req.InitData)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_auth_data"})
+		return
+	}
+
+	inviteCode := strings.TrimSpace(req.InviteCode)
+	if inviteCode == "" && service.IsInviteCode(webApp.StartParam) {
+		inviteCode = webApp.StartParam
+	}
+	h.signIn(c, webApp.AuthData(), inviteCode)
+}
+
+// signIn finds or creates the HR user for a verified Telegram account and issues a session
+func (h *AuthHandler) signIn(c *gin.Context, data *auth.TelegramAuthData, inviteCode string) {
 	// 2. Get or create HR user
-	hrUserID, companyID, status, err := h.userRepo.GetOrCreateHRUserByTelegramID(
+	user, err := h.userRepo.GetOrCreateHRUserByTelegramID(
 		data.ID,
 		data.GetUsername(),
 		data.GetDisplayName(),
+		inviteCode,
 	)
 	if err != nil {
+		if errors.Is(err, domain.ErrInviteInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invite_invalid"})
+			return
+		}
+		if errors.Is(err, domain.ErrInviteAlreadyMember) {
+			c.JSON(http.StatusConflict, gin.H{"error": "invite_already_member"})
+			return
+		}
+		if errors.Is(err, domain.ErrSeatLimit) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "seat_limit"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
 		return
 	}
 
-	// 3. Generate JWT claims
-	claims := &domain.HRClaims{
-		HRUserID:  hrUserID,
-		CompanyID: companyID,
-		Status:    status,
-		Role:      "recruiter",
+	// 2.1 Blocked companies cannot sign in (pending ones can, and see company_pending on /api)
+	if h.companies != nil {
+		companyStatus, err := h.companies.CompanyStatus(c.Request.Context(), user.CompanyID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
+			return
+		}
+		if companyStatus == domain.CompanyStatusBlocked {
+			c.JSON(http.StatusForbidden, gin.H{"error": "company_blocked"})
+			return
+		}
 	}
 
-	// 4. Sign JWT token
-	tokenStr, err := h.jwtSigner.SignClaims(claims)
-	if err != nil {
+	// 3. Generate JWT claims
+	claims := claimsFromState(user)
+
+	// 4. Sign tokens and set secure cookies
+	if err := h.issueSession(c, claims); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "token_generation_failed"})
 		return
 	}
 
-	// 5. Set secure cookie
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"user_id": user.HRUserID,
+		"status":  user.Status,
+		"role":    user.Role,
+	})
+}
+
+func claimsFromState(st domain.HRUserState) *domain.HRClaims {
+	return &domain.HRClaims{
+		HRUserID:       st.HRUserID,
+		CompanyID:      st.CompanyID,
+		Status:         st.Status,
+		Role:           st.Role,
+		SessionVersion: st.SessionVersion,
+	}
+}
+
+// issueSession signs an access + refresh token pair and sets both cookies
+func (h *AuthHandler) issueSession(c *gin.Context, claims *domain.HRClaims) error {
+	accessToken, err := h.jwtSigner.SignClaims(claims)
+	if err != nil {
+		return err
+	}
+	refreshToken, err := h.jwtSigner.SignRefresh(claims)
+	if err != nil {
+		return err
+	}
+
 	c.SetCookie(
-		"hr_auth",
-		tokenStr,
-		24*3600, // 24 hours
+		accessCookieName,
+		accessToken,
+		int(h.jwtSigner.AccessTTL().Seconds()),
 		"/",
 		"",             // domain: empty for current domain
 		h.cookieSecure, // secure (HTTPS only in production)
 		true,           // httponly
 	)
+	c.SetCookie(refreshCookieName, refreshToken, int(h.jwtSigner.RefreshTTL().Seconds()), refreshCookiePath, "", h.cookieSecure, true)
+	return nil
+}
+
+// Refresh rotates the refresh token and issues a new access token.
+// Status/role/company are reloaded from hr_users, so changes apply on refresh;
+// tokens from before a session_version bump are rejected.
+// POST /auth/refresh (uses the hr_refresh cookie)
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	if h.sessions == nil || h.revoker == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		return
+	}
+	cookie, err := c.Request.Cookie(refreshCookieName)
+	if err != nil || cookie.Value == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	old, meta, err := h.sessions.ParseRefresh(cookie.Value)
+	if errors.Is(err, auth.ErrDenylistUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "service_unavailable"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.userRepo.GetHRUserState(ctx, old.HRUserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
+		return
+	}
+	if user.SessionVersion != old.SessionVersion {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "session_revoked"})
+		return
+	}
+	if h.companies != nil {
+		companyStatus, err := h.companies.CompanyStatus(ctx, user.CompanyID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
+			return
+		}
+		if companyStatus == domain.CompanyStatusBlocked {
+			c.JSON(http.StatusForbidden, gin.H{"error": "company_blocked"})
+			return
+		}
+	}
 
+	// Rotate: the presented refresh token can't be used again. Only the request that
+	// revokes it gets a new session; a concurrent replay loses the SET NX.
+	first, err := h.revoker.RevokeOnce(ctx, meta.ID, meta.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "service_unavailable"})
+		return
+	}
+	if !first {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		return
+	}
+
+	claims := claimsFromState(user)
+	if err := h.issueSession(c, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "token_generation_failed"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"user_id": hrUserID,
-		"status":  status,
+		"user_id": claims.HRUserID,
+		"status":  claims.Status,
+		"role":    claims.Role,
 	})
 }
 
-// JWTClaims implements JWTSigner for signing HR claims
+// Logout revokes the current access and refresh tokens (by jti) and clears the cookies
+// POST /auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if h.sessions != nil && h.revoker != nil {
+		for _, name := range []string{accessCookieName, refreshCookieName} {
+			cookie, err := c.Request.Cookie(name)
+			if err != nil || cookie.Value == "" {
+				continue
+			}
+			meta, err := h.sessions.Meta(cookie.Value)
+			if err != nil {
+				continue // expired or forged: nothing to revoke
+			}
+			if err := h.revoker.Revoke(c.Request.Context(), meta.ID, meta.ExpiresAt); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
+				return
+			}
+		}
+	}
+
+	h.clearSession(c)
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// LogoutAll signs the user out on every device by bumping hr_users.session_version
+// POST /auth/logout-all (requires a valid hr_auth cookie)
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if h.userSessions == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		return
+	}
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	if err := h.userSessions.RevokeSessions(c.Request.Context(), claims.HRUserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
+		return
+	}
+	h.clearSession(c)
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *AuthHandler) clearSession(c *gin.Context) {
+	c.SetCookie(accessCookieName, "", -1, "/", "", h.cookieSecure, true)
+	c.SetCookie(refreshCookieName, "", -1, refreshCookiePath, "", h.cookieSecure, true)
+}
+
+// JWTClaimsSigner implements JWTSigner for signing HR claims.
+// Tokens carry iss/iat/exp/jti and a "kid" header naming the signing key.
 type JWTClaimsSigner struct {
-	secret []byte
+	keys       *auth.KeySet
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
-func NewJWTClaimsSigner(secret string) *JWTClaimsSigner {
-	return &JWTClaimsSigner{secret: []byte(secret)}
+func NewJWTClaimsSigner(keys *auth.KeySet, issuer string, accessTTL, refreshTTL time.Duration) *JWTClaimsSigner {
+	return &JWTClaimsSigner{keys: keys, issuer: issuer, accessTTL: accessTTL, refreshTTL: refreshTTL}
 }
 
+func (s *JWTClaimsSigner) AccessTTL() time.Duration  { return s.accessTTL }
+func (s *JWTClaimsSigner) RefreshTTL() time.Duration { return s.refreshTTL }
+
 func (s *JWTClaimsSigner) SignClaims(claims *domain.HRClaims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return s.signHR(claims, auth.TokenTypeAccess, s.accessTTL)
+}
+
+func (s *JWTClaimsSigner) SignRefresh(claims *domain.HRClaims) (string, error) {
+	return s.signHR(claims, auth.TokenTypeRefresh, s.refreshTTL)
+}
+
+func (s *JWTClaimsSigner) signHR(claims *domain.HRClaims, typ string, ttl time.Duration) (string, error) {
+	return s.sign(jwt.MapClaims{
+		"typ":        typ,
 		"hr_user_id": claims.HRUserID,
 		"company_id": claims.CompanyID,
 		"status":     claims.Status,
 		"role":       claims.Role,
-	})
-	return token.SignedString(s.secret)
+		"sv":         claims.SessionVersion,
+	}, ttl)
+}
+
+// sign adds registered claims (iss, iat, exp, jti) and the kid header
+func (s *JWTClaimsSigner) sign(mc jwt.MapClaims, ttl time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	mc["jti"] = jti
+	mc["iat"] = now.Unix()
+	mc["exp"] = now.Add(ttl).Unix()
+	if s.issuer != "" {
+		mc["iss"] = s.issuer
+	}
+
+	kid, secret := s.keys.Active()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mc)
+	token.Header["kid"] = kid
+	return token.SignedString(secret)
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// AdminTokenTTL bounds platform admin sessions; operators simply log in again.
+const AdminTokenTTL = 12 * time.Hour
+
+// SignAdminClaims issues a short-lived platform admin token (scope=platform_admin)
+func (s *JWTClaimsSigner) SignAdminClaims(claims *domain.AdminClaims) (string, error) {
+	return s.sign(jwt.MapClaims{
+		"scope":      auth.AdminScope,
+		"tg_user_id": claims.TgUserID,
+		"username":   claims.Username,
+	}, AdminTokenTTL)
 }
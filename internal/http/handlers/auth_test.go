@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/auth"
+	"tg-hr-platform/internal/domain"
+)
+
+// memDenylist is an in-memory cache.TokenDenylist
+type memDenylist struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+	// err, when set, fails every call like an unreachable Redis
+	err error
+}
+
+func (d *memDenylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := d.RevokeOnce(ctx, jti, expiresAt)
+	return err
+}
+
+func (d *memDenylist) RevokeOnce(_ context.Context, jti string, expiresAt time.Time) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return false, d.err
+	}
+	if _, ok := d.revoked[jti]; ok {
+		return false, nil
+	}
+	d.revoked[jti] = expiresAt
+	return true, nil
+}
+
+func (d *memDenylist) IsRevoked(_ context.Context, jti string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.revoked[jti]
+	return ok, d.err
+}
+
+type fakeHRUsers struct {
+	mu    sync.Mutex
+	state domain.HRUserState
+}
+
+func (r *fakeHRUsers) GetOrCreateHRUserByTelegramID(int64, string, string, string) (domain.HRUserState, error) {
+	return r.GetHRUserState(context.Background(), 0)
+}
+
+func (r *fakeHRUsers) GetHRUserState(context.Context, int64) (domain.HRUserState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state, nil
+}
+
+type authFixture struct {
+	h         *AuthHandler
+	signer    *JWTClaimsSigner
+	verifier  *auth.JWTVerifier
+	denylist  *memDenylist
+	users     *fakeHRUsers
+	userState domain.HRUserState
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	keys, err := auth.ParseKeySet("", "", "test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &authFixture{
+		signer:   NewJWTClaimsSigner(keys, "test", 15*time.Minute, 24*time.Hour),
+		verifier: auth.NewJWTVerifier(keys, "test"),
+		denylist: &memDenylist{revoked: make(map[string]time.Time)},
+		userState: domain.HRUserState{
+			HRUserID: 7, CompanyID: 3, Status: domain.HRStatusActive, Role: domain.RoleOwner, SessionVersion: 1,
+		},
+	}
+	f.users = &fakeHRUsers{state: f.userState}
+	f.verifier.SetRevocations(f.denylist)
+	f.h = NewAuthHandler(nil, f.signer, f.users, false)
+	f.h.SetSessionStore(f.verifier, f.denylist)
+	return f
+}
+
+// sign issues a token pair for the fixture's user
+func (f *authFixture) sign(t *testing.T) (access, refresh string) {
+	t.Helper()
+	claims := claimsFromState(f.userState)
+	access, err := f.signer.SignClaims(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh, err = f.signer.SignRefresh(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return access, refresh
+}
+
+// call runs handler with the given cookies and returns the recorder
+func call(handler gin.HandlerFunc, path string, cookies map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, path, nil)
+	for name, value := range cookies {
+		c.Request.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	handler(c)
+	return w
+}
+
+func responseCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestAuthRefreshRotates(t *testing.T) {
+	f := newAuthFixture(t)
+	_, refresh := f.sign(t)
+
+	w := call(f.h.Refresh, "/auth/refresh", map[string]string{refreshCookieName: refresh})
+	if w.Code != http.StatusOK {
+		t.Fatalf("refresh: %d %s", w.Code, w.Body)
+	}
+	access, next := responseCookie(w, accessCookieName), responseCookie(w, refreshCookieName)
+	if access == nil || next == nil || next.Value == refresh || next.Path != refreshCookiePath {
+		t.Fatalf("cookies after refresh: %v, %v", access, next)
+	}
+	if _, err := f.verifier.Parse(access.Value); err != nil {
+		t.Errorf("new access token: %v", err)
+	}
+
+	// the old refresh token is spent, the new one works once
+	if w := call(f.h.Refresh, "/auth/refresh", map[string]string{refreshCookieName: refresh}); w.Code != http.StatusUnauthorized {
+		t.Errorf("replayed refresh token: %d, want 401", w.Code)
+	}
+	if w := call(f.h.Refresh, "/auth/refresh", map[string]string{refreshCookieName: next.Value}); w.Code != http.StatusOK {
+		t.Errorf("rotated refresh token: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestAuthConcurrentRefreshSucceedsOnce(t *testing.T) {
+	f := newAuthFixture(t)
+	_, refresh := f.sign(t)
+
+	const n = 8
+	codes := make(chan int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- call(f.h.Refresh, "/auth/refresh", map[string]string{refreshCookieName: refresh}).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	ok := 0
+	for code := range codes {
+		switch code {
+		case http.StatusOK:
+			ok++
+		case http.StatusUnauthorized:
+		default:
+			t.Errorf("unexpected status %d", code)
+		}
+	}
+	if ok != 1 {
+		t.Errorf("%d of %d concurrent refreshes succeeded, want 1", ok, n)
+	}
+}
+
+func TestAuthRefreshRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(f *authFixture)
+		token      func(f *authFixture, access, refresh string) string
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "access token",
+			token:      func(_ *authFixture, access, _ string) string { return access },
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "invalid_token",
+		},
+		{
+			name:       "session_version bumped",
+			setup:      func(f *authFixture) { f.users.state.SessionVersion++ },
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "session_revoked",
+		},
+		{
+			name:       "denylist unavailable",
+			setup:      func(f *authFixture) { f.denylist.err = errors.New("redis: connection refused") },
+			wantStatus: http.StatusServiceUnavailable,
+			wantError:  "service_unavailable",
+		},
+		{
+			name:       "no revoker",
+			setup:      func(f *authFixture) { f.h.revoker = nil },
+			wantStatus: http.StatusNotFound,
+			wantError:  "not_found",
+		},
+	}
+	for _, tt := range tests {
+		f := newAuthFixture(t)
+		access, refresh := f.sign(t)
+		if tt.setup != nil {
+			tt.setup(f)
+		}
+		token := refresh
+		if tt.token != nil {
+			token = tt.token(f, access, refresh)
+		}
+
+		w := call(f.h.Refresh, "/auth/refresh", map[string]string{refreshCookieName: token})
+		if w.Code != tt.wantStatus || !hasError(w, tt.wantError) {
+			t.Errorf("%s: %d %s, want %d %s", tt.name, w.Code, w.Body, tt.wantStatus, tt.wantError)
+		}
+		if c := responseCookie(w, accessCookieName); c != nil {
+			t.Errorf("%s: issued an access token", tt.name)
+		}
+	}
+}
+
+func hasError(w *httptest.ResponseRecorder, code string) bool {
+	return code == "" || w.Body.String() == `{"error":"`+code+`"}`
+}
+
+func TestAuthLogout(t *testing.T) {
+	f := newAuthFixture(t)
+	access, refresh := f.sign(t)
+
+	w := call(f.h.Logout, "/auth/logout", map[string]string{accessCookieName: access, refreshCookieName: refresh})
+	if w.Code != http.StatusOK {
+		t.Fatalf("logout: %d %s", w.Code, w.Body)
+	}
+	for _, name := range []string{accessCookieName, refreshCookieName} {
+		if c := responseCookie(w, name); c == nil || c.Value != "" || c.MaxAge >= 0 {
+			t.Errorf("%s not cleared: %v", name, c)
+		}
+	}
+	if _, err := f.verifier.Parse(access); err == nil {
+		t.Error("access token still accepted after logout")
+	}
+	if w := call(f.h.Refresh, "/auth/refresh", map[string]string{refreshCookieName: refresh}); w.Code != http.StatusUnauthorized {
+		t.Errorf("refresh after logout: %d, want 401", w.Code)
+	}
+
+	// logging out twice, or without valid cookies, still clears the session
+	for _, cookies := range []map[string]string{
+		{accessCookieName: access, refreshCookieName: refresh},
+		{accessCookieName: "garbage"},
+		nil,
+	} {
+		if w := call(f.h.Logout, "/auth/logout", cookies); w.Code != http.StatusOK {
+			t.Errorf("logout with %v: %d %s", cookies, w.Code, w.Body)
+		}
+	}
+
+	f.denylist.err = errors.New("redis: connection refused")
+	if w := call(f.h.Logout, "/auth/logout", map[string]string{refreshCookieName: refresh}); w.Code != http.StatusInternalServerError {
+		t.Errorf("logout with the denylist down: %d, want 500", w.Code)
+	}
+}
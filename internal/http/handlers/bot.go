@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/service"
+	"tg-hr-platform/internal/telegram"
 )
 
 // BotWebhookRequest represents incoming webhook from Telegram
@@ -16,14 +23,20 @@ type BotWebhookRequest struct {
 		From      struct {
 			ID        int64  `json:"id"`
 			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
 			Username  string `json:"username"`
 		} `json:"from"`
 		Chat struct {
 			ID int64 `json:"id"`
 		} `json:"chat"`
-		Text string `json:"text"`
-		Date int64  `json:"date"`
+		Text              string                      `json:"text"`
+		Date              int64                       `json:"date"`
+		SuccessfulPayment *telegram.SuccessfulPayment `json:"successful_payment"`
 	} `json:"message"`
+	CallbackQuery    *telegram.CallbackQuery     `json:"callback_query"`
+	InlineQuery      *telegram.InlineQuery       `json:"inline_query"`
+	MyChatMember     *telegram.ChatMemberUpdated `json:"my_chat_member"`
+	PreCheckoutQuery *telegram.PreCheckoutQuery  `json:"pre_checkout_query"`
 }
 
 // BotWebhookResponse represents response to send to Telegram
@@ -55,21 +68,101 @@ type BotHandler struct {
 	botToken       string
 	webAppURL      string
 	webhookSecret  string
+	// onboarding, when set, enables candidate self-onboarding (/apply, /edit)
+	onboarding CandidateOnboarding
+	// profiles, when set, enables candidate self-service (/profile, /hide, /show, /delete)
+	profiles CandidateProfiles
+	// client sends what doesn't fit in the webhook response (polling replies, callback follow-ups)
+	client *telegram.Client
+	// callbacks routes inline button presses by the "<route>" part of callback_data
+	callbacks map[string]CallbackHandler
+	// payments, when set, confirms and completes invoices paid in Telegram
+	payments Payments
+}
+
+// CandidateOnboarding runs the candidate onboarding conversation, e.g. service.OnboardingService
+type CandidateOnboarding interface {
+	Start(ctx context.Context, u service.BotUser) (service.BotReply, error)
+	StartEdit(ctx context.Context, u service.BotUser) (service.BotReply, error)
+	Cancel(ctx context.Context, u service.BotUser) (reply service.BotReply, active bool, err error)
+	HandleText(ctx context.Context, u service.BotUser, text string) (reply service.BotReply, handled bool, err error)
+}
+
+// CandidateProfiles is candidate self-service, e.g. service.CandidateProfileService
+type CandidateProfiles interface {
+	Profile(ctx context.Context, u service.BotUser) (service.BotReply, error)
+	SetHidden(ctx context.Context, u service.BotUser, hidden bool) (service.BotReply, error)
+	ConfirmDelete(ctx context.Context, u service.BotUser) (service.BotReply, error)
+	Delete(ctx context.Context, u service.BotUser) (service.BotReply, error)
+	SetNotifyOnUnlock(ctx context.Context, u service.BotUser, notify bool) (service.BotReply, error)
+	SetContactMode(ctx context.Context, u service.BotUser, mode string) (service.BotReply, error)
+}
+
+// ContactRequests lets candidates answer contact requests, e.g. service.ContactRequestService
+type ContactRequests interface {
+	Answer(ctx context.Context, u service.BotUser, requestID int64, accept bool) (service.BotReply, error)
 }
 
+// SavedSearches lets HR users mute saved search digests, e.g. service.SavedSearchService
+type SavedSearches interface {
+	SetMuted(ctx context.Context, u service.BotUser, id int64, muted bool) (service.BotReply, error)
+}
+
+// Payments completes credit purchases paid with bot invoices, e.g. service.PaymentService
+type Payments interface {
+	PreCheckout(ctx context.Context, q *telegram.PreCheckoutQuery) (ok bool, errorMessage string, err error)
+	CompleteTelegram(ctx context.Context, u service.BotUser, p *telegram.SuccessfulPayment) (service.BotReply, error)
+}
+
+// applyPayload is the deep-link payload (t.me/<bot>?start=apply) that starts candidate onboarding
+const applyPayload = "apply"
+
 func NewBotHandler(botToken, webAppURL, webhookSecret string) *BotHandler {
 	return &BotHandler{
 		botToken:      botToken,
 		webAppURL:     webAppURL,
 		webhookSecret: webhookSecret,
+		callbacks:     map[string]CallbackHandler{},
 	}
 }
 
+// SetClient sets the Bot API client used for calls beyond the webhook reply.
+// Required for polling mode and for callback handlers that edit or send messages.
+func (h *BotHandler) SetClient(client *telegram.Client) {
+	h.client = client
+}
+
+// SetOnboarding enables candidate self-onboarding through the bot
+func (h *BotHandler) SetOnboarding(o CandidateOnboarding) {
+	h.onboarding = o
+}
+
+// SetCandidateProfiles enables candidate profile self-service through the bot
+func (h *BotHandler) SetCandidateProfiles(p CandidateProfiles) {
+	h.profiles = p
+	h.HandleCallback(service.ProfileCallbackRoute, h.profileCallback)
+}
+
+// SetContactRequests enables the accept/decline buttons of contact requests
+func (h *BotHandler) SetContactRequests(r ContactRequests) {
+	h.HandleCallback(service.ContactRequestCallbackRoute, contactRequestCallback(r))
+}
+
+// SetSavedSearches enables the mute/unmute buttons of saved search digests
+func (h *BotHandler) SetSavedSearches(s SavedSearches) {
+	h.HandleCallback(service.SavedSearchCallbackRoute, savedSearchCallback(s))
+}
+
+// SetPayments enables paying bot invoices (pre-checkout confirmation and successful payments)
+func (h *BotHandler) SetPayments(p Payments) {
+	h.payments = p
+}
+
 // VerifyWebhookSignature verifies Telegram webhook X-Telegram-Bot-API-Secret-Token header
 func (h *BotHandler) VerifyWebhookSignature(c *gin.Context) (bool, error) {
 	if h.webhookSecret == "" {
-		log.Println("⚠️  WARNING: Webhook secret is empty, skipping verification")
-		return true, nil
+		log.Println("⚠️  WARNING: Webhook secret is empty, refusing webhook updates")
+		return false, nil
 	}
 
 	token := c.GetHeader("X-Telegram-Bot-API-Secret-Token")
@@ -77,16 +170,14 @@ func (h *BotHandler) VerifyWebhookSignature(c *gin.Context) (bool, error) {
 		return false, nil
 	}
 
-	// In production, you should validate the token against your configured webhook secret
-	// For development, just check if the header exists
-	return token == h.webhookSecret || h.webhookSecret == "", nil
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.webhookSecret)) == 1, nil
 }
 
 // HandleWebhook processes incoming Telegram webhook
 func (h *BotHandler) HandleWebhook(c *gin.Context) {
 	var req BotWebhookRequest
 
-	// Verify webhook (optional: can skip for development)
+	// Verify the secret token Telegram sends with every webhook update
 	verified, err := h.VerifyWebhookSignature(c)
 	if err != nil {
 		log.Printf("❌ Webhook verification error: %v", err)
@@ -94,7 +185,7 @@ func (h *BotHandler) HandleWebhook(c *gin.Context) {
 		return
 	}
 
-	if !verified && h.webhookSecret != "" {
+	if !verified {
 		log.Println("❌ Webhook signature verification failed")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
@@ -108,47 +199,268 @@ func (h *BotHandler) HandleWebhook(c *gin.Context) {
 
 	log.Printf("📨 Webhook received: update_id=%d", req.UpdateID)
 
-	// Handle message
-	if req.Message != nil {
-		text := strings.TrimSpace(req.Message.Text)
-		chatID := req.Message.Chat.ID
-		userName := req.Message.From.Username
-		firstName := req.Message.From.FirstName
+	// reply in the webhook response body, saving a round trip to the Bot API
+	if resp := h.HandleUpdate(c.Request.Context(), &req); resp != nil {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
 
-		log.Printf("📬 Message from @%s (%s): %q", userName, firstName, text)
+// HandlePolledUpdate is the telegram.UpdateHandler for BOT_MODE=polling: it runs the same
+// logic as HandleWebhook and sends the reply through the client.
+func (h *BotHandler) HandlePolledUpdate(ctx context.Context, raw json.RawMessage) {
+	var req BotWebhookRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		log.Printf("❌ Failed to parse update: %v", err)
+		return
+	}
+	log.Printf("📨 Update received: update_id=%d", req.UpdateID)
 
-		// Handle /start command
-		if strings.HasPrefix(text, "/start") {
-			h.respondWithWebApp(c, chatID, userName)
-			return
+	if resp := h.HandleUpdate(ctx, &req); resp != nil {
+		h.send(ctx, resp)
+	}
+}
+
+// HandleUpdate routes one update by type and returns the Bot API call to answer it with
+// ({"method": ..., params...}), or nil if there is nothing to send.
+func (h *BotHandler) HandleUpdate(ctx context.Context, req *BotWebhookRequest) gin.H {
+	switch {
+	case req.Message != nil:
+		return h.handleMessage(ctx, req)
+	case req.CallbackQuery != nil:
+		return h.handleCallbackQuery(ctx, req.CallbackQuery)
+	case req.InlineQuery != nil:
+		// inline mode is not offered; answer anyway so the client stops waiting
+		return gin.H{
+			"method":          "answerInlineQuery",
+			"inline_query_id": req.InlineQuery.ID,
+			"results":         []any{},
+			"cache_time":      300,
 		}
+	case req.PreCheckoutQuery != nil:
+		return h.handlePreCheckoutQuery(ctx, req.PreCheckoutQuery)
+	case req.MyChatMember != nil:
+		m := req.MyChatMember
+		log.Printf("👥 Bot membership in chat %d changed by %d: %s -> %s",
+			m.Chat.ID, m.From.ID, m.OldChatMember.Status, m.NewChatMember.Status)
+		return nil
+	}
+	return nil
+}
+
+func (h *BotHandler) handleMessage(ctx context.Context, req *BotWebhookRequest) gin.H {
+	text := strings.TrimSpace(req.Message.Text)
+	chatID := req.Message.Chat.ID
+	userName := req.Message.From.Username
+	firstName := req.Message.From.FirstName
+
+	log.Printf("📬 Message from @%s (%s): %q", userName, firstName, text)
+
+	user := service.BotUser{
+		ID:        req.Message.From.ID,
+		ChatID:    chatID,
+		Username:  userName,
+		FirstName: firstName,
+		LastName:  req.Message.From.LastName,
+	}
+	if req.Message.SuccessfulPayment != nil {
+		return h.handleSuccessfulPayment(ctx, user, req.Message.SuccessfulPayment)
+	}
+	if h.profiles != nil {
+		if resp := h.handleProfileCommand(ctx, user, text); resp != nil {
+			return resp
+		}
+	}
+	if h.onboarding != nil {
+		if resp := h.handleOnboarding(ctx, user, text); resp != nil {
+			return resp
+		}
+	}
 
-		// Default response
-		h.respondWithHelp(c, chatID)
+	// Handle /start command (optionally with a deep-link payload: /start <invite_code>)
+	if strings.HasPrefix(text, "/start") {
+		inviteCode := startPayload(text)
+		if !service.IsInviteCode(inviteCode) {
+			inviteCode = ""
+		}
+		return h.respondWithWebApp(chatID, userName, inviteCode)
+	}
+
+	// Default response
+	return h.respondWithHelp(chatID)
+}
+
+// send performs a {"method": ..., params...} call through the client
+func (h *BotHandler) send(ctx context.Context, resp gin.H) {
+	method, _ := resp["method"].(string)
+	chatID, _ := resp["chat_id"].(int64)
+	if h.client == nil {
+		log.Printf("⚠️  Bot client not configured, dropping %s to chat %d", method, chatID)
 		return
 	}
+	params := make(gin.H, len(resp))
+	for k, v := range resp {
+		if k != "method" {
+			params[k] = v
+		}
+	}
+	if err := h.client.Call(ctx, method, chatID, params, nil); err != nil {
+		log.Printf("❌ Failed to send %s to chat %d: %v", method, chatID, err)
+	}
+}
 
-	c.JSON(http.StatusOK, gin.H{"ok": true})
+// handleOnboarding routes /apply, /cancel and answers to an ongoing onboarding.
+// Returns nil if the message is not part of onboarding.
+func (h *BotHandler) handleOnboarding(ctx context.Context, u service.BotUser, text string) gin.H {
+	var (
+		reply service.BotReply
+		err   error
+	)
+	switch command := commandName(text); {
+	case command == "/apply" || (command == "/start" && startPayload(text) == applyPayload):
+		reply, err = h.onboarding.Start(ctx, u)
+	case command == "/edit":
+		reply, err = h.onboarding.StartEdit(ctx, u)
+	case command == "/cancel":
+		var active bool
+		reply, active, err = h.onboarding.Cancel(ctx, u)
+		if err == nil && !active {
+			return nil
+		}
+	case command != "":
+		return nil
+	default:
+		var handled bool
+		reply, handled, err = h.onboarding.HandleText(ctx, u, text)
+		if err == nil && !handled {
+			return nil
+		}
+	}
+
+	return h.respondWithServiceReply(u.ChatID, reply, err)
+}
+
+// handleProfileCommand routes candidate self-service commands. Returns nil for anything else.
+func (h *BotHandler) handleProfileCommand(ctx context.Context, u service.BotUser, text string) gin.H {
+	var (
+		reply service.BotReply
+		err   error
+	)
+	switch commandName(text) {
+	case "/profile":
+		reply, err = h.profiles.Profile(ctx, u)
+	case "/hide":
+		reply, err = h.profiles.SetHidden(ctx, u, true)
+	case "/show":
+		reply, err = h.profiles.SetHidden(ctx, u, false)
+	case "/delete":
+		if strings.Join(strings.Fields(text), " ") == service.DeleteConfirmCommand {
+			reply, err = h.profiles.Delete(ctx, u)
+		} else {
+			reply, err = h.profiles.ConfirmDelete(ctx, u)
+		}
+	default:
+		return nil
+	}
+	return h.respondWithServiceReply(u.ChatID, reply, err)
+}
+
+// respondWithServiceReply sends reply, or a generic apology if the service failed
+func (h *BotHandler) respondWithServiceReply(chatID int64, reply service.BotReply, err error) gin.H {
+	if err != nil {
+		log.Printf("❌ Bot command error for chat %d: %v", chatID, err)
+		reply = service.BotReply{Text: "出错了，请稍后再试。"}
+	}
+	return h.respondWithReply(chatID, reply)
+}
+
+// commandName returns the bot command of a message ("/apply@MyBot x" -> "/apply"), or ""
+func commandName(text string) string {
+	if !strings.HasPrefix(text, "/") {
+		return ""
+	}
+	command := strings.Fields(text)[0]
+	if i := strings.Index(command, "@"); i >= 0 {
+		command = command[:i]
+	}
+	return command
+}
+
+// respondWithReply sends a service.BotReply, rendering Buttons as an inline keyboard and
+// Keyboard as a one-time reply keyboard
+func (h *BotHandler) respondWithReply(chatID int64, reply service.BotReply) gin.H {
+	resp := gin.H{
+		"method":  "sendMessage",
+		"chat_id": chatID,
+		"text":    reply.Text,
+	}
+	switch {
+	case len(reply.Buttons) > 0:
+		resp["reply_markup"] = service.InlineKeyboard(reply.Buttons)
+	case len(reply.Keyboard) > 0:
+		rows := make([][]gin.H, 0, len(reply.Keyboard))
+		for _, row := range reply.Keyboard {
+			buttons := make([]gin.H, 0, len(row))
+			for _, label := range row {
+				buttons = append(buttons, gin.H{"text": label})
+			}
+			rows = append(rows, buttons)
+		}
+		resp["reply_markup"] = gin.H{"keyboard": rows, "one_time_keyboard": true, "resize_keyboard": true}
+	case reply.RemoveKeyboard:
+		resp["reply_markup"] = gin.H{"remove_keyboard": true}
+	}
+	return resp
+}
+
+// startPayload extracts the deep-link parameter from "/start <payload>"
+func startPayload(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// webAppURLWithInvite appends ?invite=<code> so the frontend can pass it on to /auth/telegram/login
+func (h *BotHandler) webAppURLWithInvite(inviteCode string) string {
+	if inviteCode == "" {
+		return h.webAppURL
+	}
+	u, err := url.Parse(h.webAppURL)
+	if err != nil {
+		return h.webAppURL
+	}
+	q := u.Query()
+	q.Set("invite", inviteCode)
+	u.RawQuery = q.Encode()
+	return u.String()
 }
 
 // respondWithWebApp sends WebApp launch button
-func (h *BotHandler) respondWithWebApp(c *gin.Context, chatID int64, userName string) {
+func (h *BotHandler) respondWithWebApp(chatID int64, userName, inviteCode string) gin.H {
 	greeting := "欢迎使用 TG HR Platform！"
 	if userName != "" {
 		greeting = "@" + userName + " 欢迎！"
 	}
+	text := greeting + "\n\n请点击下方按钮打开 HR 招聘平台"
+	if inviteCode != "" {
+		text = greeting + "\n\n你收到了团队邀请，请点击下方按钮加入团队"
+	}
+	webAppURL := h.webAppURLWithInvite(inviteCode)
 
 	resp := gin.H{
 		"method":   "sendMessage",
 		"chat_id":  chatID,
-		"text":     greeting + "\n\n请点击下方按钮打开 HR 招聘平台",
+		"text":     text,
 		"reply_markup": gin.H{
 			"inline_keyboard": [][]gin.H{
 				{
 					{
 						"text": "📱 打开招聘平台",
 						"web_app": gin.H{
-							"url": h.webAppURL,
+							"url": webAppURL,
 						},
 					},
 				},
@@ -156,36 +468,40 @@ func (h *BotHandler) respondWithWebApp(c *gin.Context, chatID int64, userName st
 		},
 	}
 
-	c.JSON(http.StatusOK, resp)
-	log.Printf("✅ Sent WebApp button to chat %d (webapp_url=%s)", chatID, h.webAppURL)
+	log.Printf("✅ Sent WebApp button to chat %d (webapp_url=%s)", chatID, webAppURL)
+	return resp
 }
 
 // respondWithHelp sends help message
-func (h *BotHandler) respondWithHelp(c *gin.Context, chatID int64) {
+func (h *BotHandler) respondWithHelp(chatID int64) gin.H {
 	resp := gin.H{
 		"method":  "sendMessage",
 		"chat_id": chatID,
-		"text":    "🤖 TG HR Platform Bot\n\n使用 /start 命令开始",
+		"text":    "🤖 TG HR Platform Bot\n\n使用 /start 命令开始\n求职者发送 /apply 创建候选人档案，/profile 查看档案",
 	}
 
-	c.JSON(http.StatusOK, resp)
 	log.Printf("✅ Sent help message to chat %d", chatID)
+	return resp
 }
 
-// SetBotCommands sets bot commands via Telegram Bot API
-// Call this once during startup to register /start command in BotFather menu
-func SetBotCommands(botToken string) error {
-	if botToken == "" {
-		log.Println("⚠️  No bot token provided, skipping SetBotCommands")
-		return nil
-	}
-
-	// Call Telegram API: POST /bot<token>/setMyCommands
-	url := "https://api.telegram.org/bot" + botToken + "/setMyCommands"
-
-	// Would need to use http.Client to POST here
-	// For now, just log instruction
-	log.Printf("💡 To register /start command, visit BotFather or call: %s", url)
+// BotCommands is the command menu registered with Telegram (setMyCommands)
+var BotCommands = []telegram.BotCommand{
+	{Command: "start", Description: "打开招聘平台"},
+	{Command: "apply", Description: "求职者创建候选人档案"},
+	{Command: "profile", Description: "查看我的档案"},
+	{Command: "edit", Description: "修改我的档案"},
+	{Command: "hide", Description: "隐藏档案"},
+	{Command: "show", Description: "公开档案"},
+	{Command: "delete", Description: "删除档案"},
+	{Command: "cancel", Description: "取消当前操作"},
+}
 
+// SetBotCommands registers BotCommands in the bot's menu.
+// Call this once during startup.
+func SetBotCommands(ctx context.Context, client *telegram.Client) error {
+	if err := client.SetMyCommands(ctx, BotCommands); err != nil {
+		return err
+	}
+	log.Printf("✅ Registered %d bot commands", len(BotCommands))
 	return nil
 }
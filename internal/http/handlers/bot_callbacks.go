@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/service"
+	"tg-hr-platform/internal/telegram"
+)
+
+// CallbackResult is what to do after an inline button press.
+type CallbackResult struct {
+	// Notice is shown as a toast (a modal alert if Alert is set)
+	Notice string
+	Alert  bool
+	// Edit replaces the message carrying the button; only inline Buttons survive an edit,
+	// a reply with a reply Keyboard is sent as a new message instead
+	Edit *service.BotReply
+	// Send posts a new message to the chat
+	Send *service.BotReply
+}
+
+// CallbackHandler handles presses of buttons whose callback_data is "<route>:<arg>".
+// callback_data comes from the client and is not trusted: handlers must authorize by u.ID.
+type CallbackHandler func(ctx context.Context, u service.BotUser, arg string) (CallbackResult, error)
+
+// HandleCallback registers fn for callback_data starting with "<route>:".
+func (h *BotHandler) HandleCallback(route string, fn CallbackHandler) {
+	h.callbacks[route] = fn
+}
+
+// handleCallbackQuery runs the route's handler, performs edits/new messages through the
+// client and answers the query (which stops the button's loading spinner).
+func (h *BotHandler) handleCallbackQuery(ctx context.Context, q *telegram.CallbackQuery) gin.H {
+	answer := gin.H{
+		"method":            "answerCallbackQuery",
+		"callback_query_id": q.ID,
+	}
+
+	route, arg, _ := strings.Cut(q.Data, ":")
+	fn, ok := h.callbacks[route]
+	if !ok || q.Message == nil {
+		answer["text"] = "按钮已失效"
+		return answer
+	}
+
+	log.Printf("🔘 Callback from @%s (%d): %q", q.From.Username, q.From.ID, q.Data)
+	u := service.BotUser{
+		ID:        q.From.ID,
+		ChatID:    q.Message.Chat.ID,
+		Username:  q.From.Username,
+		FirstName: q.From.FirstName,
+		LastName:  q.From.LastName,
+	}
+	res, err := fn(ctx, u, arg)
+	if err != nil {
+		log.Printf("❌ Bot callback error for chat %d: %v", u.ChatID, err)
+		answer["text"] = "出错了，请稍后再试。"
+		answer["show_alert"] = true
+		return answer
+	}
+
+	if res.Edit != nil {
+		if len(res.Edit.Keyboard) > 0 {
+			res.Send = res.Edit
+		} else {
+			h.editMessage(ctx, q.Message, *res.Edit)
+		}
+	}
+	if res.Send != nil {
+		h.send(ctx, h.respondWithReply(u.ChatID, *res.Send))
+	}
+	if res.Notice != "" {
+		answer["text"] = res.Notice
+		answer["show_alert"] = res.Alert
+	}
+	return answer
+}
+
+// editMessage replaces the text and inline keyboard of a bot message
+func (h *BotHandler) editMessage(ctx context.Context, m *telegram.Message, reply service.BotReply) {
+	if h.client == nil {
+		log.Printf("⚠️  Bot client not configured, cannot edit message in chat %d", m.Chat.ID)
+		return
+	}
+	p := telegram.EditMessageTextParams{ChatID: m.Chat.ID, MessageID: m.MessageID, Text: reply.Text}
+	if len(reply.Buttons) > 0 {
+		markup := service.InlineKeyboard(reply.Buttons)
+		p.ReplyMarkup = &markup
+	}
+	_, err := h.client.EditMessageText(ctx, p)
+	var apiErr *telegram.APIError
+	if errors.As(err, &apiErr) && strings.Contains(apiErr.Description, "message is not modified") {
+		// pressing the same button twice
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to edit message %d in chat %d: %v", m.MessageID, m.Chat.ID, err)
+	}
+}
+
+// profileCallback handles the buttons of /profile and /delete ("profile:<action>")
+func (h *BotHandler) profileCallback(ctx context.Context, u service.BotUser, action string) (CallbackResult, error) {
+	var (
+		reply service.BotReply
+		err   error
+	)
+	switch action {
+	case service.ProfileActionView:
+		reply, err = h.profiles.Profile(ctx, u)
+	case service.ProfileActionHide:
+		reply, err = h.profiles.SetHidden(ctx, u, true)
+	case service.ProfileActionShow:
+		reply, err = h.profiles.SetHidden(ctx, u, false)
+	case service.ProfileActionDelete:
+		reply, err = h.profiles.ConfirmDelete(ctx, u)
+	case service.ProfileActionDeleteConfirm:
+		reply, err = h.profiles.Delete(ctx, u)
+	case service.ProfileActionNotifyOn:
+		reply, err = h.profiles.SetNotifyOnUnlock(ctx, u, true)
+	case service.ProfileActionNotifyOff:
+		reply, err = h.profiles.SetNotifyOnUnlock(ctx, u, false)
+	case service.ProfileActionModeOpen:
+		reply, err = h.profiles.SetContactMode(ctx, u, domain.ContactModeOpen)
+	case service.ProfileActionModeRequest:
+		reply, err = h.profiles.SetContactMode(ctx, u, domain.ContactModeRequest)
+	case service.ProfileActionEdit:
+		if h.onboarding == nil {
+			return CallbackResult{Notice: "按钮已失效"}, nil
+		}
+		// the questionnaire uses reply keyboards, so it starts in a new message
+		reply, err = h.onboarding.StartEdit(ctx, u)
+		return CallbackResult{Send: &reply}, err
+	default:
+		return CallbackResult{Notice: "按钮已失效"}, nil
+	}
+	return CallbackResult{Edit: &reply}, err
+}
+
+// contactRequestCallback handles "creq:accept:<id>" and "creq:decline:<id>"
+func contactRequestCallback(r ContactRequests) CallbackHandler {
+	return func(ctx context.Context, u service.BotUser, arg string) (CallbackResult, error) {
+		action, rawID, _ := strings.Cut(arg, ":")
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil || (action != service.ContactRequestActionAccept && action != service.ContactRequestActionDecline) {
+			return CallbackResult{Notice: "按钮已失效"}, nil
+		}
+		reply, err := r.Answer(ctx, u, id, action == service.ContactRequestActionAccept)
+		return CallbackResult{Edit: &reply}, err
+	}
+}
+
+// savedSearchCallback handles "ss:mute:<id>" and "ss:unmute:<id>"
+func savedSearchCallback(s SavedSearches) CallbackHandler {
+	return func(ctx context.Context, u service.BotUser, arg string) (CallbackResult, error) {
+		action, rawID, _ := strings.Cut(arg, ":")
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil || (action != service.SavedSearchActionMute && action != service.SavedSearchActionUnmute) {
+			return CallbackResult{Notice: "按钮已失效"}, nil
+		}
+		mute := action == service.SavedSearchActionMute
+		reply, err := s.SetMuted(ctx, u, id, mute)
+		if mute {
+			// keep the digest readable; the confirmation carries the undo button
+			return CallbackResult{Send: &reply}, err
+		}
+		return CallbackResult{Edit: &reply}, err
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/service"
+	"tg-hr-platform/internal/telegram"
+)
+
+// handlePreCheckoutQuery confirms or declines an invoice before the user is charged.
+// Telegram cancels the payment if this is not answered within 10 seconds, so it is
+// answered even when payments are not enabled.
+func (h *BotHandler) handlePreCheckoutQuery(ctx context.Context, q *telegram.PreCheckoutQuery) gin.H {
+	answer := gin.H{
+		"method":                "answerPreCheckoutQuery",
+		"pre_checkout_query_id": q.ID,
+		"ok":                    false,
+	}
+	if h.payments == nil {
+		answer["error_message"] = "暂不支持支付。"
+		return answer
+	}
+
+	log.Printf("💳 Pre-checkout from @%s (%d): %d %s for %q", q.From.Username, q.From.ID, q.TotalAmount, q.Currency, q.InvoicePayload)
+	ok, message, err := h.payments.PreCheckout(ctx, q)
+	if err != nil {
+		log.Printf("❌ Pre-checkout %s failed: %v", q.ID, err)
+		message = "暂时无法处理支付，请稍后再试。"
+	}
+	answer["ok"] = ok && err == nil
+	if !ok || err != nil {
+		answer["error_message"] = message
+	}
+	return answer
+}
+
+// handleSuccessfulPayment credits a paid invoice and thanks the user.
+func (h *BotHandler) handleSuccessfulPayment(ctx context.Context, u service.BotUser, p *telegram.SuccessfulPayment) gin.H {
+	log.Printf("💰 Payment %s from @%s (%d): %d %s for %q", p.TelegramPaymentChargeID, u.Username, u.ID, p.TotalAmount, p.Currency, p.InvoicePayload)
+	if h.payments == nil {
+		log.Printf("⚠️  Payments not configured, cannot credit Telegram payment %s", p.TelegramPaymentChargeID)
+		return nil
+	}
+	reply, err := h.payments.CompleteTelegram(ctx, u, p)
+	return h.respondWithServiceReply(u.ChatID, reply, err)
+}
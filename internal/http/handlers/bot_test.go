@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/service"
+	"tg-hr-platform/internal/telegram"
+)
+
+const (
+	testToken  = "123:abc"
+	testSecret = "webhook-secret"
+)
+
+type botCall struct {
+	method string
+	body   map[string]any
+}
+
+// botAPI stands in for api.telegram.org and records the calls the handler makes beyond
+// the webhook response.
+type botAPI struct {
+	mu    sync.Mutex
+	calls []botCall
+	// fail, when set, is returned for every call instead of success
+	fail string
+}
+
+func (a *botAPI) recorded() []botCall {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]botCall(nil), a.calls...)
+}
+
+func newTestBotHandler(t *testing.T) (*BotHandler, *botAPI) {
+	t.Helper()
+	api := &botAPI{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		api.mu.Lock()
+		api.calls = append(api.calls, botCall{method: strings.TrimPrefix(r.URL.Path, "/bot"+testToken+"/"), body: body})
+		fail := api.fail
+		api.mu.Unlock()
+		if fail != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"ok":false,"error_code":400,"description":%q}`, fail)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":9,"chat":{"id":1001,"type":"private"},"date":0}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := telegram.NewClient(testToken)
+	client.SetBaseURL(srv.URL)
+	client.SetRateLimits(0, 0)
+
+	h := NewBotHandler(testToken, "https://example.com/app", testSecret)
+	h.SetClient(client)
+	return h, api
+}
+
+// webhook posts update to HandleWebhook and returns the status and decoded response body.
+func webhook(t *testing.T, h *BotHandler, secret, update string) (int, map[string]any) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/telegram/webhook", strings.NewReader(update))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		c.Request.Header.Set("X-Telegram-Bot-API-Secret-Token", secret)
+	}
+	h.HandleWebhook(c)
+
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response %q: %v", w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+func callbackUpdate(data string) string {
+	return fmt.Sprintf(`{"update_id":1,"callback_query":{"id":"cq1","from":{"id":1001,"first_name":"Ann","username":"ann"},`+
+		`"message":{"message_id":9,"chat":{"id":1001,"type":"private"},"date":0},"data":%q}}`, data)
+}
+
+// markupRows returns the rows of a reply_markup's inline_keyboard (or keyboard) as decoded JSON
+func markupRows(t *testing.T, body map[string]any, key string) []any {
+	t.Helper()
+	markup, ok := body["reply_markup"].(map[string]any)
+	if !ok {
+		t.Fatalf("no reply_markup in %v", body)
+	}
+	rows, ok := markup[key].([]any)
+	if !ok {
+		t.Fatalf("reply_markup has no %s: %v", key, markup)
+	}
+	return rows
+}
+
+func TestBotWebhookRequiresSecret(t *testing.T) {
+	h, _ := newTestBotHandler(t)
+	update := `{"update_id":1,"message":{"message_id":1,"from":{"id":1001},"chat":{"id":1001},"text":"/start"}}`
+
+	for _, secret := range []string{"", "wrong-secret"} {
+		if status, _ := webhook(t, h, secret, update); status != http.StatusUnauthorized {
+			t.Errorf("secret %q: status %d, want 401", secret, status)
+		}
+	}
+	unset := NewBotHandler(testToken, "https://example.com/app", "")
+	if status, _ := webhook(t, unset, "", update); status != http.StatusUnauthorized {
+		t.Errorf("no secret configured: status %d, want 401", status)
+	}
+	if status, body := webhook(t, h, testSecret, update); status != http.StatusOK || body["method"] != "sendMessage" {
+		t.Errorf("valid secret: status %d, body %v", status, body)
+	}
+}
+
+func TestBotCallbackQuery(t *testing.T) {
+	reply := service.BotReply{Text: "已更新", Buttons: [][]service.BotButton{{{Text: "撤销", Data: "t:undo"}}}}
+	questionnaire := service.BotReply{Text: "请选择", Keyboard: [][]string{{"是", "否"}}}
+
+	tests := []struct {
+		name        string
+		data        string
+		result      CallbackResult
+		err         error
+		notModified bool
+		// wantAnswer is the text of the answerCallbackQuery; wantAlert its show_alert
+		wantAnswer string
+		wantAlert  bool
+		// wantCall is the Bot API method called through the client, if any
+		wantCall string
+	}{
+		{name: "unknown route", data: "gone:x", wantAnswer: "按钮已失效"},
+		{name: "no colon", data: "garbage", wantAnswer: "按钮已失效"},
+		{name: "handler error", data: "t:x", err: errors.New("db down"), wantAnswer: "出错了，请稍后再试。", wantAlert: true},
+		{name: "notice", data: "t:x", result: CallbackResult{Notice: "已关闭", Alert: true}, wantAnswer: "已关闭", wantAlert: true},
+		{name: "edit with buttons", data: "t:x", result: CallbackResult{Edit: &reply, Notice: "好的"}, wantAnswer: "好的", wantCall: "editMessageText"},
+		{name: "edit unchanged", data: "t:x", result: CallbackResult{Edit: &reply}, notModified: true, wantCall: "editMessageText"},
+		{name: "edit with reply keyboard is sent", data: "t:x", result: CallbackResult{Edit: &questionnaire}, wantCall: "sendMessage"},
+		{name: "send", data: "t:x", result: CallbackResult{Send: &reply}, wantCall: "sendMessage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, api := newTestBotHandler(t)
+			if tt.notModified {
+				api.fail = "Bad Request: message is not modified"
+			}
+			var got service.BotUser
+			var gotArg string
+			h.HandleCallback("t", func(_ context.Context, u service.BotUser, arg string) (CallbackResult, error) {
+				got, gotArg = u, arg
+				return tt.result, tt.err
+			})
+
+			status, answer := webhook(t, h, testSecret, callbackUpdate(tt.data))
+			if status != http.StatusOK || answer["method"] != "answerCallbackQuery" || answer["callback_query_id"] != "cq1" {
+				t.Fatalf("status %d, answer %v", status, answer)
+			}
+			text, _ := answer["text"].(string)
+			alert, _ := answer["show_alert"].(bool)
+			if text != tt.wantAnswer || alert != tt.wantAlert {
+				t.Errorf("answer text %q alert %v, want %q %v", text, alert, tt.wantAnswer, tt.wantAlert)
+			}
+			if strings.HasPrefix(tt.data, "t:") && (got.ID != 1001 || got.ChatID != 1001 || gotArg != "x") {
+				t.Errorf("handler called with %+v, %q", got, gotArg)
+			}
+
+			calls := api.recorded()
+			if tt.wantCall == "" {
+				if len(calls) != 0 {
+					t.Errorf("unexpected Bot API calls %v", calls)
+				}
+				return
+			}
+			if len(calls) != 1 || calls[0].method != tt.wantCall {
+				t.Fatalf("Bot API calls %v, want one %s", calls, tt.wantCall)
+			}
+			call := calls[0].body
+			if call["chat_id"] != float64(1001) {
+				t.Errorf("%s to chat %v, want 1001", tt.wantCall, call["chat_id"])
+			}
+			switch {
+			case tt.wantCall == "editMessageText":
+				if call["message_id"] != float64(9) || call["text"] != reply.Text {
+					t.Errorf("edit %v, want message 9 with the reply text", call)
+				}
+				if rows := markupRows(t, call, "inline_keyboard"); len(rows) != 1 {
+					t.Errorf("edit keyboard %v, want the reply's buttons", rows)
+				}
+			case tt.result.Edit == &questionnaire:
+				if rows := markupRows(t, call, "keyboard"); len(rows) != 1 {
+					t.Errorf("sent keyboard %v, want the reply keyboard", rows)
+				}
+			default:
+				if rows := markupRows(t, call, "inline_keyboard"); len(rows) != 1 {
+					t.Errorf("sent keyboard %v, want the reply's buttons", rows)
+				}
+			}
+		})
+	}
+}
+
+func TestBotCallbackQueryWithoutMessage(t *testing.T) {
+	h, api := newTestBotHandler(t)
+	called := false
+	h.HandleCallback("t", func(context.Context, service.BotUser, string) (CallbackResult, error) {
+		called = true
+		return CallbackResult{}, nil
+	})
+
+	// buttons on inline-mode messages carry no message
+	_, answer := webhook(t, h, testSecret, `{"update_id":1,"callback_query":{"id":"cq1","from":{"id":1001},"data":"t:x"}}`)
+	if answer["text"] != "按钮已失效" || called || len(api.recorded()) != 0 {
+		t.Errorf("answer %v, handler called %v, calls %v", answer, called, api.recorded())
+	}
+}
+
+func TestBotInlineQuery(t *testing.T) {
+	h, _ := newTestBotHandler(t)
+	_, answer := webhook(t, h, testSecret, `{"update_id":1,"inline_query":{"id":"iq1","from":{"id":1001},"query":"go","offset":""}}`)
+	results, ok := answer["results"].([]any)
+	if answer["method"] != "answerInlineQuery" || answer["inline_query_id"] != "iq1" || !ok || len(results) != 0 {
+		t.Errorf("answer %v, want an empty answerInlineQuery", answer)
+	}
+}
+
+type fakePayments struct {
+	ok      bool
+	message string
+	err     error
+}
+
+func (p *fakePayments) PreCheckout(context.Context, *telegram.PreCheckoutQuery) (bool, string, error) {
+	return p.ok, p.message, p.err
+}
+
+func (p *fakePayments) CompleteTelegram(context.Context, service.BotUser, *telegram.SuccessfulPayment) (service.BotReply, error) {
+	return service.BotReply{Text: "支付成功"}, p.err
+}
+
+func TestBotPreCheckoutQuery(t *testing.T) {
+	update := `{"update_id":1,"pre_checkout_query":{"id":"pcq1","from":{"id":1001},"currency":"XTR","total_amount":250,"invoice_payload":"pay_4"}}`
+	tests := []struct {
+		name        string
+		payments    *fakePayments
+		wantOK      bool
+		wantMessage string
+	}{
+		{"payments disabled", nil, false, "暂不支持支付。"},
+		{"accepted", &fakePayments{ok: true}, true, ""},
+		{"declined", &fakePayments{message: "订单已过期"}, false, "订单已过期"},
+		{"error", &fakePayments{ok: true, err: errors.New("db down")}, false, "暂时无法处理支付，请稍后再试。"},
+	}
+	for _, tt := range tests {
+		h, _ := newTestBotHandler(t)
+		if tt.payments != nil {
+			h.SetPayments(tt.payments)
+		}
+		_, answer := webhook(t, h, testSecret, update)
+		message, _ := answer["error_message"].(string)
+		if answer["method"] != "answerPreCheckoutQuery" || answer["pre_checkout_query_id"] != "pcq1" ||
+			answer["ok"] != tt.wantOK || message != tt.wantMessage {
+			t.Errorf("%s: answer %v, want ok=%v error_message %q", tt.name, answer, tt.wantOK, tt.wantMessage)
+		}
+	}
+}
+
+func TestBotStartInvite(t *testing.T) {
+	h, _ := newTestBotHandler(t)
+	tests := []struct {
+		payload    string
+		wantInvite string
+	}{
+		{"", ""},
+		{"ABCDEFGHJKMN", "ABCDEFGHJKMN"},
+		// other deep links are not offered as invites
+		{"promo2024", ""},
+		{"abcdefghjkmn", ""},
+	}
+	for _, tt := range tests {
+		text := strings.TrimSpace("/start " + tt.payload)
+		_, resp := webhook(t, h, testSecret, fmt.Sprintf(`{"update_id":1,"message":{"message_id":1,"from":{"id":1001},"chat":{"id":1001},"text":%q}}`, text))
+		rows := markupRows(t, resp, "inline_keyboard")
+		button, _ := rows[0].([]any)[0].(map[string]any)
+		webApp, _ := button["web_app"].(map[string]any)
+		want := "https://example.com/app"
+		if tt.wantInvite != "" {
+			want += "?invite=" + tt.wantInvite
+		}
+		if webApp["url"] != want {
+			t.Errorf("%q: web app URL %v, want %s", text, webApp["url"], want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+// CandidateNoteHandler serves a company's private notes and tags on candidates.
+type CandidateNoteHandler struct {
+	Svc   *service.CandidateNoteService
+	Audit AuditSvc
+}
+
+type candidateNoteRequest struct {
+	Body string `json:"body"`
+}
+
+type candidateTagRequest struct {
+	Tag string `json:"tag"`
+}
+
+// ListNotes returns the company's notes on a candidate, newest first
+// GET /api/candidates/:slug/notes
+func (h *CandidateNoteHandler) ListNotes(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	items, err := h.Svc.ListNotes(c.Request.Context(), claims.CompanyID, c.Param("slug"))
+	if err != nil {
+		candidateNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// AddNote leaves a note on a candidate
+// POST /api/candidates/:slug/notes
+// Body: { "body": "strong Go, weak English" }
+func (h *CandidateNoteHandler) AddNote(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	slug := c.Param("slug")
+
+	body, ok := bindNoteBody(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.Svc.AddNote(c.Request.Context(), claims.CompanyID, claims.HRUserID, slug, body)
+	if err != nil {
+		candidateNoteError(c, err)
+		return
+	}
+
+	// the note text stays out of the audit log
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "candidate.note_create", "candidate", slug,
+			map[string]any{"note_id": n.ID})
+	}
+
+	c.JSON(http.StatusCreated, n)
+}
+
+// UpdateNote edits one of the caller's own notes
+// PATCH /api/candidates/:slug/notes/:id
+// Body: { "body": "..." }
+func (h *CandidateNoteHandler) UpdateNote(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	slug := c.Param("slug")
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	body, ok := bindNoteBody(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.Svc.UpdateNote(c.Request.Context(), claims.CompanyID, claims.HRUserID, slug, id, body)
+	if err != nil {
+		candidateNoteError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "candidate.note_update", "candidate", slug,
+			map[string]any{"note_id": n.ID})
+	}
+
+	c.JSON(http.StatusOK, n)
+}
+
+// DeleteNote deletes one of the caller's own notes
+// DELETE /api/candidates/:slug/notes/:id
+func (h *CandidateNoteHandler) DeleteNote(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	slug := c.Param("slug")
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	if err := h.Svc.DeleteNote(c.Request.Context(), claims.CompanyID, claims.HRUserID, slug, id); err != nil {
+		candidateNoteError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "candidate.note_delete", "candidate", slug,
+			map[string]any{"note_id": id})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// AddTag tags a candidate for the caller's company
+// POST /api/candidates/:slug/tags
+// Body: { "tag": "shortlist-q3" }
+func (h *CandidateNoteHandler) AddTag(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	slug := c.Param("slug")
+
+	var req candidateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	tag, ok := normalizeTag(req.Tag)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_tag"})
+		return
+	}
+
+	tags, err := h.Svc.AddTag(c.Request.Context(), claims.CompanyID, claims.HRUserID, slug, tag)
+	if err != nil {
+		candidateNoteError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "candidate.tag_add", "candidate", slug, map[string]any{"tag": tag})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// RemoveTag removes a tag (case-insensitive) from a candidate
+// DELETE /api/candidates/:slug/tags/:tag
+func (h *CandidateNoteHandler) RemoveTag(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	slug := c.Param("slug")
+	tag, ok := normalizeTag(c.Param("tag"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_tag"})
+		return
+	}
+
+	tags, err := h.Svc.RemoveTag(c.Request.Context(), claims.CompanyID, slug, tag)
+	if err != nil {
+		candidateNoteError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "candidate.tag_remove", "candidate", slug, map[string]any{"tag": tag})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// ListTags returns the tags the company uses, most used first
+// GET /api/tags
+func (h *CandidateNoteHandler) ListTags(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	items, err := h.Svc.CompanyTags(c.Request.Context(), claims.CompanyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func bindNoteBody(c *gin.Context) (string, bool) {
+	var req candidateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return "", false
+	}
+	body, ok := normalizeName(req.Body, domain.MaxCandidateNoteLen)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_body"})
+		return "", false
+	}
+	return body, true
+}
+
+// normalizeTag trims a tag and collapses inner whitespace
+func normalizeTag(tag string) (string, bool) {
+	tag = strings.Join(strings.Fields(tag), " ")
+	return tag, tag != "" && utf8.RuneCountInString(tag) <= domain.MaxCandidateTagLen
+}
+
+func candidateNoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(err, domain.ErrCandidateTagLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": "candidate_tag_limit", "max": domain.MaxCandidateTags})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+	}
+}
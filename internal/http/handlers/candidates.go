@@ -35,6 +35,7 @@ func (h *CandidateHandler) List(c *gin.Context) {
         AvailMax:  int32PtrFromQuery(c, "availability_days_max"),
         SalaryMin: int32PtrFromQuery(c, "salary_min"),
         SalaryMax: int32PtrFromQuery(c, "salary_max"),
+        Tag:       strPtr(c.Query("tag")),
         Limit:     limit,
         Offset:    offset,
     }
@@ -78,8 +79,12 @@ func (h *CandidateHandler) Unlock(c *gin.Context) {
     claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
     slug := c.Param("slug")
 
-    contact, err := h.Svc.UnlockContact(c.Request.Context(), claims.CompanyID, claims.HRUserID, slug)
+    res, err := h.Svc.UnlockContact(c.Request.Context(), claims.CompanyID, claims.HRUserID, slug)
     if err != nil {
+        if errors.Is(err, domain.ErrContactRequestDeclined) {
+            c.JSON(http.StatusForbidden, gin.H{"error": "contact_request_declined"})
+            return
+        }
         if errors.Is(err, domain.ErrQuotaExceeded) {
             c.JSON(http.StatusPaymentRequired, gin.H{"error": "quota_exceeded"})
             return
@@ -96,9 +101,19 @@ func (h *CandidateHandler) Unlock(c *gin.Context) {
         return
     }
 
+    // Request-mode candidate: nothing is revealed (or charged) until they accept
+    if res.ContactRequest != nil {
+        if h.Audit != nil {
+            h.Audit.LogHR(c, claims.HRUserID, "candidate.contact_request", "candidate", slug,
+                map[string]any{"status": res.ContactRequest.Status})
+        }
+        c.JSON(http.StatusAccepted, gin.H{"status": res.ContactRequest.Status, "contact_request": res.ContactRequest})
+        return
+    }
+
     if h.Audit != nil {
         h.Audit.LogHR(c, claims.HRUserID, "candidate.unlock", "candidate", slug, nil)
     }
 
-    c.JSON(http.StatusOK, contact)
+    c.JSON(http.StatusOK, res.Contact)
 }
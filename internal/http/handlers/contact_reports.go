@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+// ContactReportHandler lets companies report unlocked contacts that turned out to be invalid.
+type ContactReportHandler struct {
+	Svc   *service.ContactReportService
+	Audit AuditSvc
+}
+
+type contactReportRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+// Report flags the candidate's unlocked contact as invalid for a platform admin to review
+// POST /api/candidates/:slug/report
+// Body: { "reason": "telegram_invalid", "details": "username does not exist" } (details optional)
+func (h *ContactReportHandler) Report(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	slug := c.Param("slug")
+
+	var req contactReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if !domain.ValidContactReportReason(req.Reason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_reason"})
+		return
+	}
+	details := strings.TrimSpace(req.Details)
+	if utf8.RuneCountInString(details) > domain.MaxContactReportDetailsLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_details"})
+		return
+	}
+
+	report, err := h.Svc.Report(c.Request.Context(), claims.CompanyID, claims.HRUserID, slug, req.Reason, details)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		case errors.Is(err, domain.ErrContactNotUnlocked):
+			c.JSON(http.StatusConflict, gin.H{"error": "contact_not_unlocked"})
+		case errors.Is(err, domain.ErrContactReportExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "contact_report_exists"})
+		case errors.Is(err, domain.ErrContactReportWindow):
+			c.JSON(http.StatusConflict, gin.H{"error": "contact_report_window_closed"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		}
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "candidate.contact_report", "candidate", slug,
+			map[string]any{"report_id": report.ID, "reason": report.Reason})
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
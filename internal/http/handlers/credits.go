@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+type CreditHandler struct {
+	Svc *service.CreditService
+}
+
+// Balance returns the company's spendable credits and the current period's grant
+// GET /api/credits
+func (h *CreditHandler) Balance(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	balance, err := h.Svc.Balance(c.Request.Context(), claims.CompanyID)
+	if err != nil {
+		if errors.Is(err, domain.ErrQuotaNotConfigured) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "quota_not_configured"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+// Ledger lists the company's credit ledger, newest first
+// GET /api/credits/ledger?page=1&page_size=20
+func (h *CreditHandler) Ledger(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	page, pageSize, limit, offset := parsePagination(c)
+
+	items, err := h.Svc.Ledger(c.Request.Context(), claims.CompanyID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+type InviteHandler struct {
+	Svc   *service.InviteService
+	Audit AuditSvc
+}
+
+type createInviteRequest struct {
+	Role           string `json:"role"`
+	MaxUses        int32  `json:"max_uses"`
+	ExpiresInHours int    `json:"expires_in_hours"`
+}
+
+type updateInviteRequest struct {
+	// ExpiresInHours of 0 expires the invite now.
+	ExpiresInHours *int `json:"expires_in_hours"`
+}
+
+// Create generates a new join code for the caller's company
+// POST /api/company/invites
+// Body: { "role": "recruiter", "max_uses": 1, "expires_in_hours": 72 }
+func (h *InviteHandler) Create(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	var req createInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if req.Role == "" {
+		req.Role = domain.RoleRecruiter
+	}
+	if !domain.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_role"})
+		return
+	}
+	if req.MaxUses <= 0 {
+		req.MaxUses = 1
+	}
+	if req.MaxUses > service.MaxInviteUses {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_max_uses"})
+		return
+	}
+	ttl := service.DefaultInviteTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if ttl > service.MaxInviteTTL {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_expiry"})
+		return
+	}
+
+	inv, err := h.Svc.CreateInvite(c.Request.Context(), claims.CompanyID, claims.HRUserID, req.Role, req.MaxUses, ttl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "invite.create", "invite", strconv.FormatInt(inv.ID, 10),
+			map[string]any{"role": inv.Role, "max_uses": inv.MaxUses, "expires_at": inv.ExpiresAt})
+	}
+
+	c.JSON(http.StatusCreated, inv)
+}
+
+// List returns the company's invites, newest first
+// GET /api/company/invites
+func (h *InviteHandler) List(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	page, pageSize, limit, offset := parsePagination(c)
+	items, err := h.Svc.ListInvites(c.Request.Context(), claims.CompanyID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items, "page": page, "page_size": pageSize})
+}
+
+// Update changes an invite's expiry (expires_in_hours=0 expires it now)
+// PATCH /api/company/invites/:id
+func (h *InviteHandler) Update(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	var req updateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.ExpiresInHours == nil || *req.ExpiresInHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	ttl := time.Duration(*req.ExpiresInHours) * time.Hour
+	if ttl > service.MaxInviteTTL {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_expiry"})
+		return
+	}
+
+	if err := h.Svc.SetInviteExpiry(c.Request.Context(), claims.CompanyID, id, ttl); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "invite.update", "invite", c.Param("id"),
+			map[string]any{"expires_in_hours": *req.ExpiresInHours})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Revoke disables an invite permanently
+// DELETE /api/company/invites/:id
+func (h *InviteHandler) Revoke(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	if err := h.Svc.RevokeInvite(c.Request.Context(), claims.CompanyID, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "invite.revoke", "invite", c.Param("id"), nil)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+type MemberHandler struct {
+	Svc   *service.MemberService
+	Audit AuditSvc
+}
+
+type updateMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// List returns all members of the caller's company
+// GET /api/company/members
+func (h *MemberHandler) List(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	items, err := h.Svc.ListMembers(c.Request.Context(), claims.CompanyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Approve activates a pending (or previously blocked) member
+// POST /api/company/members/:id/approve
+func (h *MemberHandler) Approve(c *gin.Context) {
+	h.setStatus(c, domain.HRStatusActive, "member.approve")
+}
+
+// Block blocks a member
+// POST /api/company/members/:id/block
+func (h *MemberHandler) Block(c *gin.Context) {
+	h.setStatus(c, domain.HRStatusBlocked, "member.block")
+}
+
+func (h *MemberHandler) setStatus(c *gin.Context, status, action string) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := memberIDParam(c)
+	if !ok {
+		return
+	}
+
+	upd, err := h.Svc.SetStatus(c.Request.Context(), claims.CompanyID, claims.HRUserID, id, status)
+	if err != nil {
+		writeMemberError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, action, "hr_user", c.Param("id"),
+			map[string]any{"from": upd.From, "to": upd.To})
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "status": upd.To})
+}
+
+// UpdateRole changes a member's role
+// PATCH /api/company/members/:id/role
+// Body: { "role": "admin" }
+func (h *MemberHandler) UpdateRole(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := memberIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req updateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if !domain.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_role"})
+		return
+	}
+
+	upd, err := h.Svc.SetRole(c.Request.Context(), claims.CompanyID, claims.HRUserID, id, req.Role)
+	if err != nil {
+		writeMemberError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "member.role_change", "hr_user", c.Param("id"),
+			map[string]any{"from": upd.From, "to": upd.To})
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "role": upd.To})
+}
+
+// Remove removes a member from the company
+// DELETE /api/company/members/:id
+func (h *MemberHandler) Remove(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := memberIDParam(c)
+	if !ok {
+		return
+	}
+
+	upd, err := h.Svc.Remove(c.Request.Context(), claims.CompanyID, claims.HRUserID, id)
+	if err != nil {
+		writeMemberError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "member.remove", "hr_user", c.Param("id"),
+			map[string]any{"from": upd.From, "to": upd.To})
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RevokeSessions signs a member out on every device
+// POST /api/company/members/:id/revoke-sessions
+func (h *MemberHandler) RevokeSessions(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := memberIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Svc.RevokeSessions(c.Request.Context(), claims.CompanyID, claims.HRUserID, id); err != nil {
+		writeMemberError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "member.revoke_sessions", "hr_user", c.Param("id"), nil)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func memberIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeMemberError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+	case errors.Is(err, domain.ErrCannotModifySelf):
+		c.JSON(http.StatusConflict, gin.H{"error": "cannot_modify_self"})
+	case errors.Is(err, domain.ErrLastOwner):
+		c.JSON(http.StatusConflict, gin.H{"error": "last_owner"})
+	case errors.Is(err, domain.ErrSeatLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": "seat_limit"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/payments"
+	"tg-hr-platform/internal/service"
+)
+
+// maxWebhookBody caps the payment webhook body that is read and verified.
+const maxWebhookBody = 1 << 20
+
+// PaymentHandler lets companies buy credit packs and receives provider webhooks.
+type PaymentHandler struct {
+	Svc   *service.PaymentService
+	Audit AuditSvc
+	// Fake, when set, enables the fake provider's checkout page (development only)
+	Fake *payments.FakeProvider
+}
+
+type checkoutRequest struct {
+	PackID   int64  `json:"pack_id"`
+	Provider string `json:"provider"`
+}
+
+// Packs lists the credit packs on sale and their prices per provider
+// GET /api/payments/packs
+func (h *PaymentHandler) Packs(c *gin.Context) {
+	packs, err := h.Svc.Packs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": packs, "providers": h.Svc.Providers()})
+}
+
+// Checkout starts buying a credit pack; the client sends the buyer to checkout_url
+// POST /api/payments
+// Body: { "pack_id": 1, "provider": "telegram" }
+func (h *PaymentHandler) Checkout(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	var req checkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.PackID <= 0 || req.Provider == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	payment, err := h.Svc.Checkout(c.Request.Context(), claims.CompanyID, claims.HRUserID, req.PackID, req.Provider)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "pack_not_found"})
+		case errors.Is(err, domain.ErrPaymentProviderUnavailable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "payment_provider_unavailable"})
+		case errors.Is(err, domain.ErrPaymentProvider):
+			c.JSON(http.StatusBadGateway, gin.H{"error": "payment_provider_error"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		}
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "payment.checkout", "payment", strconv.FormatInt(payment.ID, 10),
+			map[string]any{"provider": payment.Provider, "pack": payment.PackCode, "amount": payment.Amount, "currency": payment.Currency})
+	}
+	c.JSON(http.StatusCreated, payment)
+}
+
+// List lists the company's payments, newest first
+// GET /api/payments?page=1&page_size=20
+func (h *PaymentHandler) List(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	page, pageSize, limit, offset := parsePagination(c)
+
+	items, err := h.Svc.List(c.Request.Context(), claims.CompanyID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// Get returns one payment, for polling its status after checkout
+// GET /api/payments/:id
+func (h *PaymentHandler) Get(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	payment, err := h.Svc.Get(c.Request.Context(), claims.CompanyID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// Webhook receives a provider's payment notifications. Anything but a 2xx makes the
+// provider retry, so only bad signatures and internal errors are refused.
+// POST /payments/webhook/:provider (public, authenticated by payments.SignatureHeader)
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	err = h.Svc.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+	case errors.Is(err, payments.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_signature"})
+	default:
+		log.Printf("❌ %s payment webhook failed: %v", c.Param("provider"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+	}
+}
+
+// FakePay completes a fake checkout as the buyer would, by delivering the provider's signed
+// webhook to the regular webhook path
+// POST /payments/fake/:checkout_id?result=succeeded|failed (result defaults to succeeded)
+func (h *PaymentHandler) FakePay(c *gin.Context) {
+	result := c.DefaultQuery("result", domain.PaymentSucceeded)
+	if result != domain.PaymentSucceeded && result != domain.PaymentFailed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_result"})
+		return
+	}
+	header, body, err := h.Fake.Pay(c.Param("checkout_id"), result == domain.PaymentSucceeded)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		return
+	}
+	if err := h.Svc.HandleWebhook(c.Request.Context(), h.Fake.Name(), header, body); err != nil {
+		log.Printf("❌ fake payment webhook failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "result": result})
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+type PipelineHandler struct {
+	Svc   *service.PipelineService
+	Audit AuditSvc
+}
+
+type createPipelineRequest struct {
+	Name   string   `json:"name"`
+	Stages []string `json:"stages"`
+}
+
+type renamePipelineRequest struct {
+	Name string `json:"name"`
+}
+
+type replacePipelineStagesRequest struct {
+	Stages []domain.PipelineStageInput `json:"stages"`
+}
+
+type movePipelineCandidateRequest struct {
+	StageID int64 `json:"stage_id"`
+}
+
+// Create adds a pipeline to the caller's company
+// POST /api/pipelines
+// Body: { "name": "Backend Q3", "stages": ["contacted", "interviewing", "offer", "hired", "rejected"] }
+func (h *PipelineHandler) Create(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	var req createPipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	name, ok := normalizeName(req.Name, domain.MaxPipelineNameLen)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_name"})
+		return
+	}
+	if len(req.Stages) > domain.MaxPipelineStages {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_stages"})
+		return
+	}
+	for i, s := range req.Stages {
+		if req.Stages[i], ok = normalizeName(s, domain.MaxPipelineStageNameLen); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_stages"})
+			return
+		}
+	}
+
+	p, err := h.Svc.Create(c.Request.Context(), claims.CompanyID, claims.HRUserID, name, req.Stages)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "pipeline.create", "pipeline", strconv.FormatInt(p.ID, 10),
+			map[string]any{"name": p.Name})
+	}
+
+	c.JSON(http.StatusCreated, p)
+}
+
+// List returns the company's pipelines with their stages and candidate counts
+// GET /api/pipelines
+func (h *PipelineHandler) List(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	items, err := h.Svc.List(c.Request.Context(), claims.CompanyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Get returns a pipeline with its candidates
+// GET /api/pipelines/:id
+func (h *PipelineHandler) Get(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := pipelineID(c)
+	if !ok {
+		return
+	}
+
+	d, err := h.Svc.Get(c.Request.Context(), claims.CompanyID, id)
+	if err != nil {
+		pipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
+
+// Rename changes a pipeline's name
+// PATCH /api/pipelines/:id
+// Body: { "name": "Backend Q4" }
+func (h *PipelineHandler) Rename(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := pipelineID(c)
+	if !ok {
+		return
+	}
+
+	var req renamePipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	name, ok := normalizeName(req.Name, domain.MaxPipelineNameLen)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_name"})
+		return
+	}
+
+	p, err := h.Svc.Rename(c.Request.Context(), claims.CompanyID, id, name)
+	if err != nil {
+		pipelineError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "pipeline.rename", "pipeline", c.Param("id"),
+			map[string]any{"name": p.Name})
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// ReplaceStages sets the pipeline's stages in order: entries with an id keep that stage
+// (renamed/moved), entries without are created, and unlisted stages are deleted
+// PUT /api/pipelines/:id/stages
+// Body: { "stages": [{ "id": 1, "name": "contacted" }, { "name": "tech interview" }] }
+func (h *PipelineHandler) ReplaceStages(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := pipelineID(c)
+	if !ok {
+		return
+	}
+
+	var req replacePipelineStagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if len(req.Stages) == 0 || len(req.Stages) > domain.MaxPipelineStages {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_stages"})
+		return
+	}
+	for i, s := range req.Stages {
+		if req.Stages[i].Name, ok = normalizeName(s.Name, domain.MaxPipelineStageNameLen); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_stages"})
+			return
+		}
+	}
+
+	p, err := h.Svc.ReplaceStages(c.Request.Context(), claims.CompanyID, id, req.Stages)
+	if err != nil {
+		pipelineError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		names := make([]string, 0, len(p.Stages))
+		for _, s := range p.Stages {
+			names = append(names, s.Name)
+		}
+		h.Audit.LogHR(c, claims.HRUserID, "pipeline.stages_update", "pipeline", c.Param("id"),
+			map[string]any{"stages": names})
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// Delete removes a pipeline and everyone's place in it
+// DELETE /api/pipelines/:id
+func (h *PipelineHandler) Delete(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := pipelineID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Svc.Delete(c.Request.Context(), claims.CompanyID, id); err != nil {
+		pipelineError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "pipeline.delete", "pipeline", c.Param("id"), nil)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// MoveCandidate adds a candidate to the pipeline or moves them to another stage
+// PUT /api/pipelines/:id/candidates/:slug
+// Body: { "stage_id": 3 }
+func (h *PipelineHandler) MoveCandidate(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := pipelineID(c)
+	if !ok {
+		return
+	}
+	slug := c.Param("slug")
+
+	var req movePipelineCandidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.StageID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	t, err := h.Svc.MoveCandidate(c.Request.Context(), claims.CompanyID, claims.HRUserID, id, slug, req.StageID)
+	if err != nil {
+		pipelineError(c, err)
+		return
+	}
+
+	// moving to the current stage is a no-op and not audited
+	if t != nil && h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "pipeline.candidate_move", "candidate", slug, t.AuditMeta())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RemoveCandidate takes a candidate out of the pipeline
+// DELETE /api/pipelines/:id/candidates/:slug
+func (h *PipelineHandler) RemoveCandidate(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, ok := pipelineID(c)
+	if !ok {
+		return
+	}
+	slug := c.Param("slug")
+
+	t, err := h.Svc.RemoveCandidate(c.Request.Context(), claims.CompanyID, id, slug)
+	if err != nil {
+		pipelineError(c, err)
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "pipeline.candidate_remove", "candidate", slug, t.AuditMeta())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func pipelineID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func pipelineError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+	case errors.Is(err, domain.ErrPipelineStageInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_stage"})
+	case errors.Is(err, domain.ErrPipelineStageNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": "stage_not_empty"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+	}
+}
+
+// normalizeName trims a user-given name and checks it is non-empty and at most maxLen characters
+func normalizeName(name string, maxLen int) (string, bool) {
+	name = strings.TrimSpace(name)
+	return name, name != "" && utf8.RuneCountInString(name) <= maxLen
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+type QuotaHandler struct {
+	Svc *service.QuotaService
+}
+
+// History lists the company's finished quota periods, newest first; the current period is in /api/me
+// GET /api/quota/history?page=1&page_size=20
+func (h *QuotaHandler) History(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	page, pageSize, limit, offset := parsePagination(c)
+
+	items, err := h.Svc.History(c.Request.Context(), claims.CompanyID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
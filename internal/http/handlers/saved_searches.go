@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/http/middleware"
+	"tg-hr-platform/internal/service"
+)
+
+const maxSavedSearchNameLen = 100
+
+type SavedSearchHandler struct {
+	Svc   *service.SavedSearchService
+	Audit AuditSvc
+}
+
+type createSavedSearchRequest struct {
+	Name      string                     `json:"name"`
+	Filters   domain.CandidateListFilter `json:"filters"`
+	Frequency string                     `json:"frequency"`
+}
+
+type updateSavedSearchRequest struct {
+	Name      *string                     `json:"name"`
+	Filters   *domain.CandidateListFilter `json:"filters"`
+	Frequency *string                     `json:"frequency"`
+	Muted     *bool                       `json:"muted"`
+}
+
+// Create saves a candidate filter; new matches are sent to the caller's Telegram chat
+// POST /api/saved-searches
+// Body: { "name": "Senior Go", "filters": { "english": "B2", "salary_min": 20000 }, "frequency": "daily" }
+func (h *SavedSearchHandler) Create(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	var req createSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	name, ok := normalizeName(req.Name, maxSavedSearchNameLen)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_name"})
+		return
+	}
+	if req.Frequency == "" {
+		req.Frequency = domain.SavedSearchDaily
+	}
+	if _, ok := domain.SavedSearchInterval(req.Frequency); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_frequency"})
+		return
+	}
+
+	ss, err := h.Svc.Create(c.Request.Context(), claims.CompanyID, claims.HRUserID, name, req.Filters, req.Frequency)
+	if err != nil {
+		if errors.Is(err, domain.ErrSavedSearchLimit) {
+			c.JSON(http.StatusConflict, gin.H{"error": "saved_search_limit", "max": service.MaxSavedSearches})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "saved_search.create", "saved_search", strconv.FormatInt(ss.ID, 10),
+			map[string]any{"name": ss.Name, "frequency": ss.Frequency})
+	}
+
+	c.JSON(http.StatusCreated, ss)
+}
+
+// List returns the caller's saved searches, newest first
+// GET /api/saved-searches
+func (h *SavedSearchHandler) List(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+
+	items, err := h.Svc.List(c.Request.Context(), claims.HRUserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Update renames a search or changes its filters, frequency or mute state
+// PATCH /api/saved-searches/:id
+// Body: any of { "name", "filters", "frequency", "muted" }; filters are replaced as a whole
+func (h *SavedSearchHandler) Update(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	var req updateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+	if req.Name != nil {
+		name, ok := normalizeName(*req.Name, maxSavedSearchNameLen)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_name"})
+			return
+		}
+		req.Name = &name
+	}
+	if req.Frequency != nil {
+		if _, ok := domain.SavedSearchInterval(*req.Frequency); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_frequency"})
+			return
+		}
+	}
+
+	ss, err := h.Svc.Update(c.Request.Context(), claims.HRUserID, id, service.SavedSearchUpdate{
+		Name:      req.Name,
+		Filters:   req.Filters,
+		Frequency: req.Frequency,
+		Muted:     req.Muted,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "saved_search.update", "saved_search", c.Param("id"),
+			map[string]any{"frequency": ss.Frequency, "muted": ss.Muted})
+	}
+
+	c.JSON(http.StatusOK, ss)
+}
+
+// Delete removes a saved search
+// DELETE /api/saved-searches/:id
+func (h *SavedSearchHandler) Delete(c *gin.Context) {
+	claims := c.MustGet(middleware.CtxHRClaimsKey).(*domain.HRClaims)
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+		return
+	}
+
+	if err := h.Svc.Delete(c.Request.Context(), claims.HRUserID, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+
+	if h.Audit != nil {
+		h.Audit.LogHR(c, claims.HRUserID, "saved_search.delete", "saved_search", c.Param("id"), nil)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+)
+
+const (
+	CtxAdminClaimsKey = "admin_claims"
+	// CtxImpersonatingKey is set (true) on read-only support sessions started by a platform admin.
+	CtxImpersonatingKey = "impersonating"
+
+	AdminCookieName = "admin_auth"
+)
+
+type AdminJWTVerifier interface {
+	ParseAdmin(token string) (*domain.AdminClaims, error)
+}
+
+// AdminAuthMiddleware guards the /admin console. The allowlist is re-checked on every
+// request, so removing an ID from PLATFORM_ADMIN_TG_IDS revokes access on restart.
+type AdminAuthMiddleware struct {
+	JWT     AdminJWTVerifier
+	Allowed map[int64]bool
+}
+
+func (m *AdminAuthMiddleware) Auth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cookie, err := c.Request.Cookie(AdminCookieName)
+		if err != nil || cookie.Value == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		claims, err := m.JWT.ParseAdmin(cookie.Value)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+			return
+		}
+		if !m.Allowed[claims.TgUserID] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Set(CtxAdminClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// Impersonate turns an admin request for /:company_id/... into a read-only view of that
+// company by injecting synthetic HR claims, so the regular /api handlers can be reused.
+// Only mount GET routes behind it. onEnter may reject unknown companies or record the access.
+func Impersonate(onEnter func(c *gin.Context, admin *domain.AdminClaims, companyID int64) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.AbortWithStatusJSON(http.StatusMethodNotAllowed, gin.H{"error": "read_only"})
+			return
+		}
+		admin, ok := c.MustGet(CtxAdminClaimsKey).(*domain.AdminClaims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		companyID, err := strconv.ParseInt(c.Param("company_id"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid_id"})
+			return
+		}
+		if err := onEnter(c, admin, companyID); err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		}
+
+		c.Set(CtxHRClaimsKey, &domain.HRClaims{
+			CompanyID: companyID,
+			Status:    domain.HRStatusActive,
+			Role:      domain.RoleAdmin,
+		})
+		c.Set(CtxCompanyIDKey, companyID)
+		c.Set(CtxImpersonatingKey, true)
+		c.Next()
+	}
+}
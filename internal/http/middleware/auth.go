@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"tg-hr-platform/internal/domain"
@@ -10,12 +12,30 @@ import (
 
 const CtxHRClaimsKey = "hr_claims"
 
+// CtxCompanyIDKey is read by service.AuditLogService.LogHR to scope audit rows.
+const CtxCompanyIDKey = "company_id"
+
 type JWTVerifier interface {
     Parse(token string) (*domain.HRClaims, error)
 }
 
+// CompanyStatusLookup returns companies.status (active/pending/blocked) for a company.
+type CompanyStatusLookup interface {
+    CompanyStatus(ctx context.Context, companyID int64) (string, error)
+}
+
+// HRUserStateLookup returns the current hr_users state (domain.ErrNotFound if removed).
+type HRUserStateLookup interface {
+    HRUserState(ctx context.Context, hrUserID int64) (domain.HRUserState, error)
+}
+
 type AuthMiddleware struct {
     JWT JWTVerifier
+    // Companies, when set, makes AuthActiveHR also require an active company.
+    Companies CompanyStatusLookup
+    // Users, when set, makes AuthActiveHR use the user's current status/role instead of
+    // the token's, and reject tokens issued before a session_version bump.
+    Users HRUserStateLookup
 }
 
 func (m *AuthMiddleware) Auth() gin.HandlerFunc {
@@ -31,6 +51,7 @@ func (m *AuthMiddleware) Auth() gin.HandlerFunc {
             return
         }
         c.Set(CtxHRClaimsKey, claims)
+        c.Set(CtxCompanyIDKey, claims.CompanyID)
         c.Next()
     }
 }
@@ -43,13 +64,61 @@ func (m *AuthMiddleware) AuthActiveHR() gin.HandlerFunc {
             return
         }
         claims := v.(*domain.HRClaims)
+        if m.Users != nil {
+            st, err := m.Users.HRUserState(c.Request.Context(), claims.HRUserID)
+            if err != nil {
+                if errors.Is(err, domain.ErrNotFound) {
+                    c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "session_revoked"})
+                    return
+                }
+                c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+                return
+            }
+            if st.SessionVersion != claims.SessionVersion || st.CompanyID != claims.CompanyID {
+                c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "session_revoked"})
+                return
+            }
+            // claims is per-request, so later handlers and RequirePermission see the fresh values
+            claims.Status = st.Status
+            claims.Role = st.Role
+        }
         switch claims.Status {
         case "active":
-            c.Next()
         case "pending":
             c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "pending_approval"})
+            return
         default:
             c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "blocked"})
+            return
+        }
+
+        if m.Companies != nil {
+            status, err := m.Companies.CompanyStatus(c.Request.Context(), claims.CompanyID)
+            if err != nil {
+                if errors.Is(err, domain.ErrNotFound) {
+                    c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "company_blocked"})
+                    return
+                }
+                c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+                return
+            }
+            if errCode := CompanyStatusError(status); errCode != "" {
+                c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": errCode})
+                return
+            }
         }
+        c.Next()
+    }
+}
+
+// CompanyStatusError maps a non-active company status to its API error code ("" if active).
+func CompanyStatusError(status string) string {
+    switch status {
+    case domain.CompanyStatusActive:
+        return ""
+    case domain.CompanyStatusPending:
+        return "company_pending"
+    default:
+        return "company_blocked"
     }
 }
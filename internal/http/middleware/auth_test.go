@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/service"
+)
+
+type fakeUsers struct {
+	state domain.HRUserState
+	err   error
+}
+
+func (f fakeUsers) HRUserState(context.Context, int64) (domain.HRUserState, error) {
+	return f.state, f.err
+}
+
+// serveActiveHR runs AuthActiveHR for a request authenticated with claims and returns the
+// status, the error code (if any) and the claims the handler saw.
+func serveActiveHR(t *testing.T, m *AuthMiddleware, claims domain.HRClaims) (int, string, *domain.HRClaims) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	var seen *domain.HRClaims
+	r := gin.New()
+	r.GET("/api/me",
+		func(c *gin.Context) { c.Set(CtxHRClaimsKey, &claims) },
+		m.AuthActiveHR(),
+		func(c *gin.Context) {
+			seen = c.MustGet(CtxHRClaimsKey).(*domain.HRClaims)
+			c.JSON(http.StatusOK, gin.H{"success": true})
+		})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/me", nil))
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body.Error, seen
+}
+
+func TestAuthActiveHRChecksCurrentState(t *testing.T) {
+	token := domain.HRClaims{HRUserID: 7, CompanyID: 3, Status: domain.HRStatusActive, Role: domain.RoleOwner, SessionVersion: 2}
+	current := domain.HRUserState{HRUserID: 7, CompanyID: 3, Status: domain.HRStatusActive, Role: domain.RoleOwner, SessionVersion: 2}
+	with := func(change func(*domain.HRUserState)) domain.HRUserState {
+		st := current
+		change(&st)
+		return st
+	}
+
+	tests := []struct {
+		name       string
+		users      fakeUsers
+		wantStatus int
+		wantError  string
+	}{
+		{"unchanged", fakeUsers{state: current}, http.StatusOK, ""},
+		{"session_version bumped", fakeUsers{state: with(func(st *domain.HRUserState) { st.SessionVersion++ })}, http.StatusUnauthorized, "session_revoked"},
+		{"moved to another company", fakeUsers{state: with(func(st *domain.HRUserState) { st.CompanyID = 4 })}, http.StatusUnauthorized, "session_revoked"},
+		{"removed", fakeUsers{err: domain.ErrNotFound}, http.StatusUnauthorized, "session_revoked"},
+		{"blocked since login", fakeUsers{state: with(func(st *domain.HRUserState) { st.Status = domain.HRStatusBlocked })}, http.StatusForbidden, "blocked"},
+		{"lookup failed", fakeUsers{err: errors.New("db down")}, http.StatusInternalServerError, "internal"},
+	}
+	for _, tt := range tests {
+		status, code, _ := serveActiveHR(t, &AuthMiddleware{Users: tt.users}, token)
+		if status != tt.wantStatus || code != tt.wantError {
+			t.Errorf("%s: %d %q, want %d %q", tt.name, status, code, tt.wantStatus, tt.wantError)
+		}
+	}
+
+	// role changes apply to the request without a new token
+	demoted := with(func(st *domain.HRUserState) { st.Role = domain.RoleRecruiter })
+	if status, _, seen := serveActiveHR(t, &AuthMiddleware{Users: fakeUsers{state: demoted}}, token); status != http.StatusOK || seen.Role != domain.RoleRecruiter {
+		t.Errorf("demoted user: %d with role %v", status, seen)
+	}
+}
+
+func TestAuthActiveHRRejectsRevokedSessions(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, hrUserID := dbtest.SeedCompany(t, pool, 0)
+	users := &service.HRUserStateService{Q: db.New(pool)}
+	m := &AuthMiddleware{Users: users}
+
+	st, err := users.HRUserState(ctx, hrUserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := domain.HRClaims{HRUserID: hrUserID, CompanyID: companyID, Status: st.Status, Role: st.Role, SessionVersion: st.SessionVersion}
+	if status, code, _ := serveActiveHR(t, m, token); status != http.StatusOK {
+		t.Fatalf("fresh token: %d %q", status, code)
+	}
+
+	// logout-all / revoke-sessions
+	if err := users.RevokeSessions(ctx, hrUserID); err != nil {
+		t.Fatal(err)
+	}
+	if status, code, _ := serveActiveHR(t, m, token); status != http.StatusUnauthorized || code != "session_revoked" {
+		t.Errorf("token from before the bump: %d %q, want 401 session_revoked", status, code)
+	}
+	token.SessionVersion++
+	if status, code, _ := serveActiveHR(t, m, token); status != http.StatusOK {
+		t.Errorf("token issued after the bump: %d %q", status, code)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tg-hr-platform/internal/domain"
+)
+
+// RequirePermission checks the caller's role against the domain permission matrix.
+// Must run after Auth().
+func RequirePermission(perm domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := claimsFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if !domain.RoleHasPermission(claims.Role, perm) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func claimsFromContext(c *gin.Context) (*domain.HRClaims, bool) {
+	v, ok := c.Get(CtxHRClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*domain.HRClaims)
+	return claims, ok
+}
@@ -0,0 +1,164 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"tg-hr-platform/internal/domain"
+)
+
+// CardProvider talks to a card processor's hosted checkout API:
+//
+//	POST <base>/v1/checkouts  (Authorization: Bearer <key>, Idempotency-Key: payment:<id>)
+//	  {"amount": 9900, "currency": "CNY", "description": "...", "reference": "payment:<id>", "return_url": "..."}
+//	-> {"id": "chk_...", "url": "https://..."}
+//
+// and confirms payments with webhooks signed in SignatureHeader:
+//
+//	{"id": "evt_...", "type": "checkout.succeeded" | "checkout.failed",
+//	 "data": {"checkout_id": "chk_...", "reference": "payment:<id>", "amount": 9900, "currency": "CNY", "failure_reason": ""}}
+type CardProvider struct {
+	baseURL       string
+	apiKey        string
+	webhookSecret string
+	returnURL     string
+	http          *http.Client
+	now           func() time.Time
+}
+
+// NewCardProvider: returnURL is where the checkout sends the buyer back to when done.
+func NewCardProvider(baseURL, apiKey, webhookSecret, returnURL string) *CardProvider {
+	return &CardProvider{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		apiKey:        apiKey,
+		webhookSecret: webhookSecret,
+		returnURL:     returnURL,
+		http:          &http.Client{Timeout: 15 * time.Second},
+		now:           time.Now,
+	}
+}
+
+// SetHTTPClient replaces the HTTP client (e.g. in tests).
+func (p *CardProvider) SetHTTPClient(hc *http.Client) {
+	p.http = hc
+}
+
+func (p *CardProvider) Name() string { return domain.PaymentProviderCard }
+
+func (p *CardProvider) Price(pack Pack) (int32, string, bool) { return minorUnitPrice(pack) }
+
+type cardCheckoutRequest struct {
+	Amount      int32  `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	Reference   string `json:"reference"`
+	ReturnURL   string `json:"return_url,omitempty"`
+}
+
+type cardCheckoutResponse struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+func (p *CardProvider) CreateCheckout(ctx context.Context, c Checkout) (Session, error) {
+	ref := Reference(c.PaymentID)
+	body, err := json.Marshal(cardCheckoutRequest{
+		Amount:      c.Amount,
+		Currency:    c.Currency,
+		Description: c.Title,
+		Reference:   ref,
+		ReturnURL:   p.returnURL,
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/checkouts", bytes.NewReader(body))
+	if err != nil {
+		return Session{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	// a retried request for the same payment returns the same checkout
+	req.Header.Set("Idempotency-Key", ref)
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return Session{}, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Session{}, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return Session{}, fmt.Errorf("card checkout: %s: %.200s", resp.Status, raw)
+	}
+	var out cardCheckoutResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return Session{}, fmt.Errorf("card checkout: decode response: %w", err)
+	}
+	if out.ID == "" || out.URL == "" {
+		return Session{}, fmt.Errorf("card checkout: response without id or url")
+	}
+	return Session{ProviderRef: out.ID, URL: out.URL}, nil
+}
+
+func (p *CardProvider) ParseWebhook(header http.Header, body []byte) (domain.PaymentEvent, error) {
+	return parseCardWebhook(p.Name(), p.webhookSecret, header, body, p.now())
+}
+
+type cardWebhook struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		CheckoutID    string `json:"checkout_id"`
+		Reference     string `json:"reference"`
+		Amount        int32  `json:"amount"`
+		Currency      string `json:"currency"`
+		FailureReason string `json:"failure_reason"`
+	} `json:"data"`
+}
+
+// Card webhook event types
+const (
+	cardEventSucceeded = "checkout.succeeded"
+	cardEventFailed    = "checkout.failed"
+)
+
+// parseCardWebhook verifies and decodes a webhook in the card protocol (also used by FakeProvider).
+func parseCardWebhook(provider, secret string, header http.Header, body []byte, now time.Time) (domain.PaymentEvent, error) {
+	if err := VerifySignature(secret, header.Get(SignatureHeader), body, now); err != nil {
+		return domain.PaymentEvent{}, err
+	}
+	var w cardWebhook
+	if err := json.Unmarshal(body, &w); err != nil {
+		return domain.PaymentEvent{}, fmt.Errorf("%s webhook: %w", provider, err)
+	}
+	ev := domain.PaymentEvent{
+		Provider:      provider,
+		ID:            w.ID,
+		ProviderRef:   w.Data.CheckoutID,
+		Amount:        w.Data.Amount,
+		Currency:      w.Data.Currency,
+		FailureReason: w.Data.FailureReason,
+	}
+	switch w.Type {
+	case cardEventSucceeded:
+		ev.Type = domain.PaymentSucceeded
+	case cardEventFailed:
+		ev.Type = domain.PaymentFailed
+	default:
+		return domain.PaymentEvent{}, ErrIgnoredEvent
+	}
+	if ev.ID == "" || (ev.ProviderRef == "" && w.Data.Reference == "") {
+		return domain.PaymentEvent{}, fmt.Errorf("%s webhook: event without id or checkout", provider)
+	}
+	ev.PaymentID, _ = ParseReference(w.Data.Reference)
+	return ev, nil
+}
@@ -0,0 +1,100 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/domain"
+)
+
+func TestCardCreateCheckout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/checkouts" || r.Header.Get("Authorization") != "Bearer sk_test" || r.Header.Get("Idempotency-Key") != "payment:7" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		var body cardCheckoutRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body.Amount != 9900 || body.Currency != "CNY" || body.Reference != "payment:7" {
+			t.Errorf("unexpected body %+v", body)
+		}
+		_, _ = w.Write([]byte(`{"id":"chk_1","url":"https://pay.example/chk_1"}`))
+	}))
+	defer srv.Close()
+
+	p := NewCardProvider(srv.URL+"/", "sk_test", "whsec", "")
+	s, err := p.CreateCheckout(context.Background(), Checkout{PaymentID: 7, Title: "10 积分", Amount: 9900, Currency: "CNY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ProviderRef != "chk_1" || s.URL != "https://pay.example/chk_1" {
+		t.Fatalf("unexpected session %+v", s)
+	}
+}
+
+func TestCardCreateCheckoutError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"error":"account_suspended"}`))
+	}))
+	defer srv.Close()
+
+	p := NewCardProvider(srv.URL, "sk_test", "whsec", "")
+	if _, err := p.CreateCheckout(context.Background(), Checkout{PaymentID: 1, Amount: 100, Currency: "CNY"}); err == nil {
+		t.Fatal("expected an error for a 402 response")
+	}
+}
+
+func TestCardParseWebhook(t *testing.T) {
+	p := NewCardProvider("https://pay.example", "sk_test", "whsec", "")
+	now := time.Unix(1_800_000_000, 0)
+	p.now = func() time.Time { return now }
+
+	body := []byte(`{"id":"evt_1","type":"checkout.succeeded","data":{"checkout_id":"chk_1","reference":"payment:7","amount":9900,"currency":"CNY"}}`)
+	signed := func(body []byte, at time.Time) http.Header {
+		h := http.Header{}
+		h.Set(SignatureHeader, Sign("whsec", body, at))
+		return h
+	}
+
+	ev, err := p.ParseWebhook(signed(body, now), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.PaymentEvent{Provider: "card", ID: "evt_1", Type: domain.PaymentSucceeded, PaymentID: 7,
+		ProviderRef: "chk_1", Amount: 9900, Currency: "CNY"}
+	if ev != want {
+		t.Fatalf("got %+v, want %+v", ev, want)
+	}
+
+	tampered := []byte(`{"id":"evt_1","type":"checkout.succeeded","data":{"checkout_id":"chk_1","reference":"payment:7","amount":1,"currency":"CNY"}}`)
+	if _, err := p.ParseWebhook(signed(body, now), tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered body: got %v, want ErrInvalidSignature", err)
+	}
+	if _, err := p.ParseWebhook(signed(body, now.Add(-SignatureTolerance-time.Second)), body); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("stale signature: got %v, want ErrInvalidSignature", err)
+	}
+	if _, err := p.ParseWebhook(http.Header{}, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("unsigned: got %v, want ErrInvalidSignature", err)
+	}
+
+	other := []byte(`{"id":"evt_2","type":"checkout.created","data":{"checkout_id":"chk_1"}}`)
+	if _, err := p.ParseWebhook(signed(other, now), other); !errors.Is(err, ErrIgnoredEvent) {
+		t.Fatalf("other event type: got %v, want ErrIgnoredEvent", err)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	if id, ok := ParseReference(Reference(42)); !ok || id != 42 {
+		t.Fatalf("round trip: got %d %v", id, ok)
+	}
+	for _, ref := range []string{"", "payment:", "payment:x", "payment:-1", "contact_report:1"} {
+		if _, ok := ParseReference(ref); ok {
+			t.Errorf("ParseReference(%q) accepted", ref)
+		}
+	}
+}
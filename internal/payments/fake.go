@@ -0,0 +1,88 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-hr-platform/internal/domain"
+)
+
+// ErrUnknownCheckout: FakeProvider.Pay was given a checkout it did not create.
+var ErrUnknownCheckout = errors.New("unknown_checkout")
+
+// FakeProvider is a card provider that needs no network: checkouts are kept in memory and
+// Pay produces the signed webhook the processor would send. It is meant for local
+// development and tests; checkouts are lost on restart.
+type FakeProvider struct {
+	secret      string
+	checkoutURL string
+
+	mu        sync.Mutex
+	seq       int
+	checkouts map[string]Checkout
+}
+
+// NewFakeProvider: checkoutURL is the base of the checkout links handed out
+// (e.g. http://localhost:8080/payments/fake); the checkout ID is appended.
+func NewFakeProvider(secret, checkoutURL string) *FakeProvider {
+	return &FakeProvider{
+		secret:      secret,
+		checkoutURL: strings.TrimRight(checkoutURL, "/"),
+		checkouts:   map[string]Checkout{},
+	}
+}
+
+func (p *FakeProvider) Name() string { return domain.PaymentProviderFake }
+
+func (p *FakeProvider) Price(pack Pack) (int32, string, bool) { return minorUnitPrice(pack) }
+
+func (p *FakeProvider) CreateCheckout(_ context.Context, c Checkout) (Session, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	id := fmt.Sprintf("fake_chk_%d_%d", c.PaymentID, p.seq)
+	p.checkouts[id] = c
+	return Session{ProviderRef: id, URL: p.checkoutURL + "/" + id}, nil
+}
+
+// Pay simulates the buyer completing (or failing) the checkout and returns the webhook
+// request the processor would send for it. Calling it again returns the same event, as a
+// redelivery would.
+func (p *FakeProvider) Pay(checkoutID string, succeeded bool) (http.Header, []byte, error) {
+	p.mu.Lock()
+	c, ok := p.checkouts[checkoutID]
+	p.mu.Unlock()
+	if !ok {
+		return nil, nil, ErrUnknownCheckout
+	}
+
+	var w cardWebhook
+	w.Type = cardEventSucceeded
+	if !succeeded {
+		w.Type = cardEventFailed
+		w.Data.FailureReason = "card_declined"
+	}
+	w.ID = "evt_" + checkoutID + "_" + w.Type
+	w.Data.CheckoutID = checkoutID
+	w.Data.Reference = Reference(c.PaymentID)
+	w.Data.Amount = c.Amount
+	w.Data.Currency = c.Currency
+	body, err := json.Marshal(w)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(SignatureHeader, Sign(p.secret, body, time.Now()))
+	return header, body, nil
+}
+
+func (p *FakeProvider) ParseWebhook(header http.Header, body []byte) (domain.PaymentEvent, error) {
+	return parseCardWebhook(p.Name(), p.secret, header, body, time.Now())
+}
@@ -0,0 +1,50 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tg-hr-platform/internal/domain"
+)
+
+func TestFakeProviderRoundTrip(t *testing.T) {
+	p := NewFakeProvider("dev-secret", "http://localhost:8080/payments/fake/")
+	s, err := p.CreateCheckout(context.Background(), Checkout{PaymentID: 3, Amount: 44900, Currency: "CNY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.URL != "http://localhost:8080/payments/fake/"+s.ProviderRef {
+		t.Fatalf("unexpected checkout URL %q", s.URL)
+	}
+
+	header, body, err := p.Pay(s.ProviderRef, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := p.ParseWebhook(header, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != domain.PaymentSucceeded || ev.PaymentID != 3 || ev.ProviderRef != s.ProviderRef || ev.Amount != 44900 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	// a redelivery carries the same event ID
+	header, body, _ = p.Pay(s.ProviderRef, true)
+	if again, _ := p.ParseWebhook(header, body); again.ID != ev.ID {
+		t.Fatalf("redelivered event ID %q, want %q", again.ID, ev.ID)
+	}
+
+	header, body, _ = p.Pay(s.ProviderRef, false)
+	if failed, _ := p.ParseWebhook(header, body); failed.Type != domain.PaymentFailed || failed.ID == ev.ID {
+		t.Fatalf("unexpected failure event %+v", failed)
+	}
+
+	if _, _, err := p.Pay("fake_chk_unknown", true); !errors.Is(err, ErrUnknownCheckout) {
+		t.Fatalf("unknown checkout: got %v", err)
+	}
+	if _, err := NewFakeProvider("other", "").ParseWebhook(header, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("wrong secret: got %v, want ErrInvalidSignature", err)
+	}
+}
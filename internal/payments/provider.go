@@ -0,0 +1,87 @@
+// Package payments connects credit purchases to payment providers. A Provider starts a
+// checkout for a payment; the provider later confirms it, either with a signed webhook
+// (WebhookProvider) or, for Telegram, with bot updates handled by the bot handler.
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tg-hr-platform/internal/domain"
+)
+
+var (
+	// ErrInvalidSignature: the webhook is not signed with the provider's secret, or too old
+	ErrInvalidSignature = errors.New("invalid_signature")
+	// ErrIgnoredEvent: the webhook is genuine but not about a payment outcome
+	ErrIgnoredEvent = errors.New("ignored_event")
+)
+
+// Pack is what a provider needs to know to price a credit pack.
+type Pack struct {
+	Credits    int32
+	PriceMinor int32
+	Currency   string
+	PriceStars int32
+}
+
+// Checkout is one payment to collect. Amount is in the currency's minor units.
+type Checkout struct {
+	PaymentID   int64
+	Title       string
+	Description string
+	Amount      int32
+	Currency    string
+}
+
+// Session is a started checkout: the buyer pays at URL. ProviderRef is the provider's ID for
+// the checkout, empty if the provider only names the charge once it succeeded.
+type Session struct {
+	ProviderRef string
+	URL         string
+}
+
+type Provider interface {
+	// Name is the provider's domain.PaymentProvider* constant
+	Name() string
+	// Price returns what the pack costs with this provider; ok is false if it is not sold here
+	Price(pack Pack) (amount int32, currency string, ok bool)
+	CreateCheckout(ctx context.Context, c Checkout) (Session, error)
+}
+
+// WebhookProvider is a Provider that confirms payments by calling POST /payments/webhook/<name>.
+type WebhookProvider interface {
+	Provider
+	// ParseWebhook verifies the request and returns the payment outcome it reports.
+	// Fails with ErrInvalidSignature or ErrIgnoredEvent.
+	ParseWebhook(header http.Header, body []byte) (domain.PaymentEvent, error)
+}
+
+const referencePrefix = "payment:"
+
+// Reference is how a payment is named to providers (invoice payloads, checkout references)
+// and in the credit ledger: "payment:<id>".
+func Reference(paymentID int64) string {
+	return referencePrefix + strconv.FormatInt(paymentID, 10)
+}
+
+// ParseReference is the inverse of Reference.
+func ParseReference(ref string) (int64, bool) {
+	raw, ok := strings.CutPrefix(ref, referencePrefix)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// minorUnitPrice is the card price; packs priced at 0 are not sold by card.
+func minorUnitPrice(pack Pack) (int32, string, bool) {
+	return pack.PriceMinor, pack.Currency, pack.PriceMinor > 0
+}
@@ -0,0 +1,60 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries a webhook's signature: "t=<unix seconds>,v1=<hex HMAC-SHA256 of
+// "<t>.<body>" keyed with the webhook secret>".
+const SignatureHeader = "X-Payment-Signature"
+
+// SignatureTolerance is how old a signed webhook may be; older ones are rejected as replays.
+const SignatureTolerance = 5 * time.Minute
+
+// Sign returns the SignatureHeader value for body signed at t.
+func Sign(secret string, body []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(signatureMAC(secret, ts, body))
+}
+
+// VerifySignature checks a SignatureHeader value; any failure is ErrInvalidSignature.
+func VerifySignature(secret, header string, body []byte, now time.Time) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, signatureMAC(secret, ts, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signatureMAC(secret, ts string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,45 @@
+package payments
+
+import (
+	"context"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/telegram"
+)
+
+// TelegramProvider sells packs with bot invoices (createInvoiceLink). Without a provider
+// token invoices are in Telegram Stars; with one (from @BotFather > Payments) they are in
+// the pack's fiat currency. Telegram confirms payments through the bot: a
+// pre_checkout_query, then a successful_payment message carrying the invoice payload.
+type TelegramProvider struct {
+	client        *telegram.Client
+	providerToken string
+}
+
+func NewTelegramProvider(client *telegram.Client, providerToken string) *TelegramProvider {
+	return &TelegramProvider{client: client, providerToken: providerToken}
+}
+
+func (p *TelegramProvider) Name() string { return domain.PaymentProviderTelegram }
+
+func (p *TelegramProvider) Price(pack Pack) (int32, string, bool) {
+	if p.providerToken != "" {
+		return minorUnitPrice(pack)
+	}
+	return pack.PriceStars, domain.CurrencyStars, pack.PriceStars > 0
+}
+
+func (p *TelegramProvider) CreateCheckout(ctx context.Context, c Checkout) (Session, error) {
+	link, err := p.client.CreateInvoiceLink(ctx, telegram.CreateInvoiceLinkParams{
+		Title:         c.Title,
+		Description:   c.Description,
+		Payload:       Reference(c.PaymentID),
+		ProviderToken: p.providerToken,
+		Currency:      c.Currency,
+		Prices:        []telegram.LabeledPrice{{Label: c.Title, Amount: c.Amount}},
+	})
+	if err != nil {
+		return Session{}, err
+	}
+	return Session{URL: link}, nil
+}
@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func TestCandidateNotesAndTagsStayWithinCompany(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyA, hrA := dbtest.SeedCompany(t, pool, 0)
+	companyB, hrB := dbtest.SeedCompany(t, pool, 0)
+	candidateID := dbtest.SeedCandidates(t, pool, 1)[0]
+	q := db.New(pool)
+	r := &CandidateRepo{Q: q, Pool: pool}
+
+	note, err := r.AddNote(ctx, db.CreateCandidateNoteParams{CompanyID: companyA, CandidateID: candidateID, HrUserID: hrA, Body: "A only"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := q.AddCandidateTag(ctx, db.AddCandidateTagParams{
+		CompanyID: companyA, CandidateID: candidateID, Tag: "shortlist", CreatedBy: hrA, MaxPerCandidate: domain.MaxCandidateTags,
+	}); err != nil || n != 1 {
+		t.Fatalf("AddCandidateTag = %d, %v", n, err)
+	}
+
+	// company B sees none of it
+	if notes, err := r.ListNotes(ctx, companyB, candidateID); err != nil || len(notes) != 0 {
+		t.Errorf("company B lists notes %v, %v", notes, err)
+	}
+	if _, err := r.GetNote(ctx, db.GetCandidateNoteParams{ID: note.ID, CompanyID: companyB, CandidateID: candidateID}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("company B gets note: %v, want ErrNotFound", err)
+	}
+	if tags, err := r.ListTagsByIDs(ctx, companyB, []int64{candidateID}); err != nil || len(tags) != 0 {
+		t.Errorf("company B lists tags %v, %v", tags, err)
+	}
+	if tags, err := q.ListCompanyTags(ctx, companyB); err != nil || len(tags) != 0 {
+		t.Errorf("company B's tag filter %v, %v", tags, err)
+	}
+
+	// and cannot change it
+	if _, err := r.UpdateNote(ctx, db.UpdateCandidateNoteParams{ID: note.ID, HrUserID: hrB, Body: "B was here"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("company B updates note: %v, want ErrNotFound", err)
+	}
+	if n, err := q.DeleteCandidateNote(ctx, db.DeleteCandidateNoteParams{ID: note.ID, HrUserID: hrB}); err != nil || n != 0 {
+		t.Errorf("company B deletes note: %d, %v", n, err)
+	}
+	if n, err := q.RemoveCandidateTag(ctx, db.RemoveCandidateTagParams{CompanyID: companyB, CandidateID: candidateID, Tag: "shortlist"}); err != nil || n != 0 {
+		t.Errorf("company B removes tag: %d, %v", n, err)
+	}
+
+	notes, err := r.ListNotes(ctx, companyA, candidateID)
+	if err != nil || len(notes) != 1 || notes[0].Body != "A only" {
+		t.Errorf("company A's notes after B's attempts: %v, %v", notes, err)
+	}
+	tags, err := r.ListTagsByIDs(ctx, companyA, []int64{candidateID})
+	if err != nil || len(tags) != 1 || tags[0].Tag != "shortlist" {
+		t.Errorf("company A's tags after B's attempts: %v, %v", tags, err)
+	}
+}
+
+func TestAddCandidateTagLimit(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyA, hrA := dbtest.SeedCompany(t, pool, 0)
+	companyB, hrB := dbtest.SeedCompany(t, pool, 0)
+	candidateID := dbtest.SeedCandidates(t, pool, 1)[0]
+	q := db.New(pool)
+
+	add := func(companyID, hrUserID int64, tag string) int64 {
+		t.Helper()
+		n, err := q.AddCandidateTag(ctx, db.AddCandidateTagParams{
+			CompanyID: companyID, CandidateID: candidateID, Tag: tag, CreatedBy: hrUserID, MaxPerCandidate: 3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	for i := 0; i < 3; i++ {
+		if n := add(companyA, hrA, fmt.Sprintf("tag%d", i)); n != 1 {
+			t.Fatalf("tag %d: added %d rows", i, n)
+		}
+	}
+	if n := add(companyA, hrA, "TAG0"); n != 0 {
+		t.Errorf("duplicate in another case added %d rows", n)
+	}
+	if n := add(companyA, hrA, "tag3"); n != 0 {
+		t.Errorf("tag over the limit added %d rows", n)
+	}
+	// the limit counts each company's tags separately
+	if n := add(companyB, hrB, "tag3"); n != 1 {
+		t.Errorf("company B's first tag added %d rows", n)
+	}
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+// CreateOnboardedCandidate inserts the candidate, skills and Telegram contact and ends the
+// chat's onboarding session, all in one transaction.
+// Returns domain.ErrCandidateExists if the Telegram account already has a candidate.
+func (r *CandidateRepo) CreateOnboardedCandidate(ctx context.Context, chatID int64, c domain.NewCandidate) (int64, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	q := r.Q.WithTx(tx)
+
+	id, err := q.CreateOnboardedCandidate(ctx, db.CreateOnboardedCandidateParams{
+		PublicSlug:           c.Slug,
+		TgUserID:             c.TgUserID,
+		DisplayName:          c.DisplayName,
+		DesiredRole:          c.DesiredRole,
+		EnglishLevel:         c.EnglishLevel,
+		ExpectedSalaryMinCny: c.SalaryMin,
+		ExpectedSalaryMaxCny: c.SalaryMax,
+		AvailabilityDays:     c.AvailabilityDays,
+		Timezone:             c.Timezone,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrCandidateExists
+		}
+		return 0, err
+	}
+
+	if err := addCandidateSkills(ctx, q, id, c.Skills); err != nil {
+		return 0, err
+	}
+	if err := q.UpsertCandidateTelegramContact(ctx, db.UpsertCandidateTelegramContactParams{CandidateID: id, TgUsername: c.TgUsername}); err != nil {
+		return 0, err
+	}
+	if err := q.DeleteOnboardingSession(ctx, chatID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateOnboardedCandidate applies an /edit run: profile fields, skills (replaced) and the
+// Telegram contact, then ends the chat's session.
+func (r *CandidateRepo) UpdateOnboardedCandidate(ctx context.Context, chatID int64, c domain.NewCandidate) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := r.Q.WithTx(tx)
+
+	if err := q.UpdateOnboardedCandidate(ctx, db.UpdateOnboardedCandidateParams{
+		ID:                   c.CandidateID,
+		DisplayName:          c.DisplayName,
+		DesiredRole:          c.DesiredRole,
+		EnglishLevel:         c.EnglishLevel,
+		ExpectedSalaryMinCny: c.SalaryMin,
+		ExpectedSalaryMaxCny: c.SalaryMax,
+		AvailabilityDays:     c.AvailabilityDays,
+		Timezone:             c.Timezone,
+	}); err != nil {
+		return err
+	}
+	if err := q.DeleteCandidateSkills(ctx, c.CandidateID); err != nil {
+		return err
+	}
+	if err := addCandidateSkills(ctx, q, c.CandidateID, c.Skills); err != nil {
+		return err
+	}
+	if c.TgUsername != "" {
+		if err := q.UpsertCandidateTelegramContact(ctx, db.UpsertCandidateTelegramContactParams{CandidateID: c.CandidateID, TgUsername: c.TgUsername}); err != nil {
+			return err
+		}
+	}
+	if err := q.DeleteOnboardingSession(ctx, chatID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteCandidate purges the candidate's contacts and marks the record deleted.
+// Unlock history is kept so companies' audit trails stay intact.
+func (r *CandidateRepo) DeleteCandidate(ctx context.Context, candidateID int64) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := r.Q.WithTx(tx)
+
+	if err := q.DeleteCandidateContact(ctx, candidateID); err != nil {
+		return err
+	}
+	if err := q.MarkCandidateDeleted(ctx, candidateID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func addCandidateSkills(ctx context.Context, q *db.Queries, candidateID int64, skills []string) error {
+	for _, name := range skills {
+		skillID, err := q.GetOrCreateSkillID(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := q.AddCandidateSkill(ctx, db.AddCandidateSkillParams{CandidateID: candidateID, SkillID: skillID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
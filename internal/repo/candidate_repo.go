@@ -44,6 +44,70 @@ func (r *CandidateRepo) ListSkillsByIDs(ctx context.Context, ids []int64) ([]db.
     return r.Q.ListSkillsByCandidateIDs(ctx, ids)
 }
 
+func (r *CandidateRepo) ListPipelineStagesByIDs(ctx context.Context, companyID int64, ids []int64) ([]db.ListCandidatePipelineStagesRow, error) {
+    return r.Q.ListCandidatePipelineStages(ctx, db.ListCandidatePipelineStagesParams{CompanyID: companyID, CandidateIDs: ids})
+}
+
+func (r *CandidateRepo) ListTagsByIDs(ctx context.Context, companyID int64, ids []int64) ([]db.ListCandidateTagsRow, error) {
+    return r.Q.ListCandidateTags(ctx, db.ListCandidateTagsParams{CompanyID: companyID, CandidateIDs: ids})
+}
+
+// ListNotes returns the company's notes on the candidate, newest first.
+func (r *CandidateRepo) ListNotes(ctx context.Context, companyID, candidateID int64) ([]domain.CandidateNote, error) {
+    rows, err := r.Q.ListCandidateNotes(ctx, db.ListCandidateNotesParams{CompanyID: companyID, CandidateID: candidateID})
+    if err != nil {
+        return nil, err
+    }
+    out := make([]domain.CandidateNote, 0, len(rows))
+    for _, n := range rows {
+        out = append(out, candidateNoteFromRow(n))
+    }
+    return out, nil
+}
+
+func (r *CandidateRepo) AddNote(ctx context.Context, p db.CreateCandidateNoteParams) (domain.CandidateNote, error) {
+    n, err := r.Q.CreateCandidateNote(ctx, p)
+    if err != nil {
+        return domain.CandidateNote{}, err
+    }
+    return candidateNoteFromRow(n), nil
+}
+
+// GetNote returns domain.ErrNotFound unless the note is the company's, on that candidate.
+func (r *CandidateRepo) GetNote(ctx context.Context, p db.GetCandidateNoteParams) (domain.CandidateNote, error) {
+    n, err := r.Q.GetCandidateNote(ctx, p)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return domain.CandidateNote{}, domain.ErrNotFound
+        }
+        return domain.CandidateNote{}, err
+    }
+    return candidateNoteFromRow(n), nil
+}
+
+// UpdateNote returns domain.ErrNotFound unless p.HrUserID wrote the note.
+func (r *CandidateRepo) UpdateNote(ctx context.Context, p db.UpdateCandidateNoteParams) (domain.CandidateNote, error) {
+    n, err := r.Q.UpdateCandidateNote(ctx, p)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return domain.CandidateNote{}, domain.ErrNotFound
+        }
+        return domain.CandidateNote{}, err
+    }
+    return candidateNoteFromRow(n), nil
+}
+
+func candidateNoteFromRow(n db.CandidateNoteRow) domain.CandidateNote {
+    return domain.CandidateNote{
+        ID:         n.ID,
+        Body:       n.Body,
+        AuthorID:   n.HrUserID.Int64,
+        AuthorName: n.AuthorName,
+        CreatedAt:  n.CreatedAt,
+        UpdatedAt:  n.UpdatedAt,
+    }
+}
+
 func (r *CandidateRepo) GetContactByID(ctx context.Context, candidateID int64) (domain.CandidateContact, error) {
     cc, err := r.Q.GetCandidateContactByID(ctx, candidateID)
     if err != nil {
@@ -56,7 +120,12 @@ func (r *CandidateRepo) GetContactByID(ctx context.Context, candidateID int64) (
     }, nil
 }
 
-// UnlockContactTx: lock quota -> idempotent unlock -> charge quota only if inserted
+// UnlockContactTx: lock quota -> idempotent unlock -> spend credits and queue the candidate
+// notification only if inserted. For candidates in request mode (not yet unlocked) it opens
+// a contact request instead and returns domain.ErrContactRequestPending (after committing)
+// or domain.ErrContactRequestDeclined.
+// All statements run on the same pgx.Tx, so the FOR UPDATE lock serializes concurrent
+// unlocks for a company until commit.
 func (r *CandidateRepo) UnlockContactTx(ctx context.Context, companyID, hrUserID, candidateID int64) (bool, error) {
     tx, err := r.Pool.Begin(ctx)
     if err != nil {
@@ -64,21 +133,44 @@ func (r *CandidateRepo) UnlockContactTx(ctx context.Context, companyID, hrUserID
     }
     defer tx.Rollback(ctx)
 
-    // Lock quota row
-    quota, err := r.Q.LockCompanyQuota(ctx, companyID)
+    q := r.Q.WithTx(tx)
+
+    // Lock quota row (starting a new period if the current one has ended)
+    quota, err := lockCurrentQuota(ctx, q, companyID)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return false, domain.ErrQuotaNotConfigured
+        }
+        return false, err
+    }
+
+    // Request-mode candidates approve each company first; nothing is charged until they accept
+    gate, err := q.GetContactGate(ctx, db.GetContactGateParams{CompanyID: companyID, CandidateID: candidateID})
     if err != nil {
-        // likely no quota row configured
-        return false, domain.ErrQuotaNotConfigured
+        if errors.Is(err, pgx.ErrNoRows) {
+            return false, domain.ErrNotFound
+        }
+        return false, err
     }
-    if quota.UnlockQuotaUsed >= quota.UnlockQuotaTotal {
-        return false, domain.ErrQuotaExceeded
+    if gate.ContactMode == domain.ContactModeRequest && !gate.Unlocked {
+        if quota.CreditBalance < gate.UnlockCost {
+            return false, domain.ErrQuotaExceeded
+        }
+        if err := requestContact(ctx, q, companyID, hrUserID, candidateID); err != nil {
+            return false, err
+        }
+        if err := tx.Commit(ctx); err != nil {
+            return false, err
+        }
+        return false, domain.ErrContactRequestPending
     }
 
     // Insert unlock (idempotent)
-    _, insErr := r.Q.UnlockCandidateContactIdempotent(ctx, db.UnlockCandidateContactIdempotentParams{
+    _, insErr := q.UnlockCandidateContactIdempotent(ctx, db.UnlockCandidateContactIdempotentParams{
         CompanyID:   companyID,
         HrUserID:    hrUserID,
         CandidateID: candidateID,
+        Cost:        gate.UnlockCost,
     })
 
     firstTime := true
@@ -91,9 +183,15 @@ func (r *CandidateRepo) UnlockContactTx(ctx context.Context, companyID, hrUserID
     }
 
     if firstTime {
-        if err := r.Q.IncrementCompanyQuotaUsed(ctx, db.IncrementCompanyQuotaUsedParams{
-            CompanyID: companyID,
-            Delta:     1,
+        // Only new unlocks spend credits; re-unlocking an already unlocked contact stays free
+        if err := chargeUnlock(ctx, q, quota, hrUserID, candidateID, gate.UnlockCost); err != nil {
+            return false, err
+        }
+        // Tell the candidate (if they opted in); queued in this tx so it is sent iff the unlock commits
+        if err := q.EnqueueUnlockNotification(ctx, db.EnqueueUnlockNotificationParams{
+            CompanyID:   companyID,
+            CandidateID: candidateID,
+            Kind:        domain.BotOutboxCandidateUnlocked,
         }); err != nil {
             return false, err
         }
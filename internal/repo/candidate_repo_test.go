@@ -0,0 +1,204 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+// seedUnlockFixture creates a company with the given quota, one HR user and n candidates.
+func seedUnlockFixture(t *testing.T, pool *pgxpool.Pool, quotaTotal, n int) (companyID, hrUserID int64, candidateIDs []int64) {
+	t.Helper()
+	companyID, hrUserID = dbtest.SeedCompany(t, pool, quotaTotal)
+	// cleanups run last-in, first-out: the candidates go before the company
+	candidateIDs = dbtest.SeedCandidates(t, pool, n)
+	return companyID, hrUserID, candidateIDs
+}
+
+func quotaUsed(t *testing.T, pool *pgxpool.Pool, companyID int64) int32 {
+	t.Helper()
+	var used int32
+	if err := pool.QueryRow(context.Background(), `SELECT unlock_quota_used FROM company_quotas WHERE company_id = $1`, companyID).Scan(&used); err != nil {
+		t.Fatal(err)
+	}
+	return used
+}
+
+func TestUnlockContactTxConcurrentNeverOverspends(t *testing.T) {
+	pool := dbtest.Pool(t)
+	const quota, workers = 5, 20
+	companyID, hrUserID, candidateIDs := seedUnlockFixture(t, pool, quota, workers)
+	r := &CandidateRepo{Q: db.New(pool), Pool: pool}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		ok       int
+		exceeded int
+	)
+	for _, candID := range candidateIDs {
+		wg.Add(1)
+		go func(candID int64) {
+			defer wg.Done()
+			_, err := r.UnlockContactTx(context.Background(), companyID, hrUserID, candID)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				ok++
+			case errors.Is(err, domain.ErrQuotaExceeded):
+				exceeded++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(candID)
+	}
+	wg.Wait()
+
+	if ok != quota || exceeded != workers-quota {
+		t.Fatalf("got %d unlocked / %d exceeded, want %d / %d", ok, exceeded, quota, workers-quota)
+	}
+	if used := quotaUsed(t, pool, companyID); used != quota {
+		t.Fatalf("unlock_quota_used = %d, want %d", used, quota)
+	}
+	var unlocks int
+	if err := pool.QueryRow(context.Background(), `SELECT count(*) FROM unlocks WHERE company_id = $1`, companyID).Scan(&unlocks); err != nil {
+		t.Fatal(err)
+	}
+	if unlocks != quota {
+		t.Fatalf("unlocks rows = %d, want %d", unlocks, quota)
+	}
+}
+
+func TestUnlockContactTxSameCandidateChargedOnce(t *testing.T) {
+	pool := dbtest.Pool(t)
+	const workers = 10
+	companyID, hrUserID, candidateIDs := seedUnlockFixture(t, pool, 5, 1)
+	r := &CandidateRepo{Q: db.New(pool), Pool: pool}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		firstTime int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			first, err := r.UnlockContactTx(context.Background(), companyID, hrUserID, candidateIDs[0])
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if first {
+				mu.Lock()
+				firstTime++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstTime != 1 {
+		t.Fatalf("firstTime reported %d times, want 1", firstTime)
+	}
+	if used := quotaUsed(t, pool, companyID); used != 1 {
+		t.Fatalf("unlock_quota_used = %d, want 1", used)
+	}
+}
+
+func TestListPageSearch(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	q := db.New(pool)
+	r := &CandidateRepo{Q: q, Pool: pool}
+	companyID, _ := dbtest.SeedCompany(t, pool, 0)
+
+	// a marker word keeps the matches apart from whatever else is in the database
+	marker := fmt.Sprintf("zq%d", time.Now().UnixNano())
+	goSkill, reactSkill := "Go-"+marker, "React-"+marker
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM skills WHERE name = ANY($1::text[])`, []string{goSkill, reactSkill})
+	})
+	ids := dbtest.SeedCandidates(t, pool, 2)
+	alexandra, bob := ids[0], ids[1]
+	for _, c := range []struct {
+		id                  int64
+		name, role, summary string
+		skill               string
+	}{
+		{alexandra, "Alexandra " + marker, "Golang backend", "kubernetes operators", goSkill},
+		{bob, "Bob " + marker, "Frontend", "react dashboards", reactSkill},
+	} {
+		if _, err := pool.Exec(ctx, `UPDATE candidates SET display_name = $2, desired_role = $3, summary = $4 WHERE id = $1`,
+			c.id, c.name, c.role, c.summary); err != nil {
+			t.Fatal(err)
+		}
+		skillID, err := q.GetOrCreateSkillID(ctx, c.skill)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := q.AddCandidateSkill(ctx, db.AddCandidateSkillParams{CandidateID: c.id, SkillID: skillID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name  string
+		q     *string
+		skill *string
+		want  []int64
+	}{
+		{"no filter", nil, nil, []int64{alexandra, bob}},
+		{"name", str("alexandra"), nil, []int64{alexandra}},
+		{"summary", str("kubernetes"), nil, []int64{alexandra}},
+		{"desired role", str("Frontend"), nil, []int64{bob}},
+		{"typo", str("Alexandar"), nil, []int64{alexandra}},
+		{"partial word", str("Alexand"), nil, []int64{alexandra}},
+		{"skill in another case", nil, str(strings.ToLower(goSkill)), []int64{alexandra}},
+		{"query and skill", str(marker), str(reactSkill), []int64{bob}},
+		{"unknown skill", nil, str("Cobol-" + marker), nil},
+	}
+	for _, tt := range tests {
+		rows, err := r.ListPage(ctx, db.ListCandidatesPageParams{CompanyID: companyID, Q: tt.q, Skill: tt.skill, Limit: 10000})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []int64
+		for _, row := range rows {
+			if slices.Contains(ids, row.ID) {
+				got = append(got, row.ID)
+			}
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// both names share the marker, but the full-text hit on both words ranks first
+	rows, err := r.ListPage(ctx, db.ListCandidatesPageParams{CompanyID: companyID, Q: str("Alexandra " + marker), Limit: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if slices.Contains(ids, row.ID) {
+			if row.ID != alexandra {
+				t.Errorf("candidate %d ranked above %d", row.ID, alexandra)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/util"
+)
+
+type ContactReportRepo struct {
+	Q    *db.Queries
+	Pool *pgxpool.Pool
+}
+
+// Create reports the company's unlocked contact of the candidate as invalid. Fails with
+// domain.ErrContactNotUnlocked, domain.ErrContactReportWindow or domain.ErrContactReportExists.
+func (r *ContactReportRepo) Create(ctx context.Context, companyID, hrUserID, candidateID int64, reason, details string) (*domain.ContactReport, error) {
+	unlock, err := r.Q.GetContactUnlock(ctx, db.GetContactUnlockParams{CompanyID: companyID, CandidateID: candidateID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrContactNotUnlocked
+		}
+		return nil, err
+	}
+	if time.Since(unlock.CreatedAt) > domain.ContactReportWindow {
+		return nil, domain.ErrContactReportWindow
+	}
+	row, err := r.Q.CreateContactReport(ctx, db.CreateContactReportParams{
+		CompanyID:   companyID,
+		CandidateID: candidateID,
+		UnlockID:    unlock.ID,
+		HrUserID:    hrUserID,
+		Reason:      reason,
+		Details:     details,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrContactReportExists
+		}
+		return nil, err
+	}
+	return contactReportFromRow(row), nil
+}
+
+// ContactReport returns the company's report on the candidate's contact, or nil.
+func (r *CandidateRepo) ContactReport(ctx context.Context, companyID, candidateID int64) (*domain.ContactReport, error) {
+	row, err := r.Q.GetContactReport(ctx, db.GetContactReportParams{CompanyID: companyID, CandidateID: candidateID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return contactReportFromRow(row), nil
+}
+
+func (r *ContactReportRepo) AdminList(ctx context.Context, status *string, limit, offset int32) ([]domain.AdminContactReport, error) {
+	rows, err := r.Q.AdminListContactReports(ctx, db.AdminListContactReportsParams{Status: status, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.AdminContactReport, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.AdminContactReport{
+			ContactReport: *contactReportFromRow(row.ContactReportRow),
+			CompanyID:     row.CompanyID,
+			CompanyName:   row.CompanyName,
+			CandidateName: row.CandidateName,
+			ReporterID:    row.HrUserID.Int64,
+			ReporterName:  util.TextOrEmpty(row.ReporterName),
+			UnlockCost:    row.UnlockCost,
+			UnlockedAt:    row.UnlockedAt,
+			Contact: domain.CandidateContact{
+				TgUsername: util.TextOrEmpty(row.TgUsername),
+				Email:      util.TextOrEmpty(row.Email),
+				Phone:      util.TextOrEmpty(row.Phone),
+			},
+		})
+	}
+	return out, nil
+}
+
+// RefundTx upholds a pending report: the unlock's cost is credited back (ledger kind
+// "refund", ref "contact_report:<id>") in the same transaction that closes the report.
+// If the unlock happened in the current quota period its usage is given back too, so the
+// refund is part of the period's allowance; older unlocks are refunded as credits that
+// do not expire. Returns the company ID with the report.
+func (r *ContactReportRepo) RefundTx(ctx context.Context, reportID, adminTgID int64, note string) (int64, *domain.ContactReport, error) {
+	report, err := r.pendingReport(ctx, reportID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	// quota row before the report, like AcceptContactRequestTx
+	quota, err := lockCurrentQuota(ctx, q, report.CompanyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil, domain.ErrQuotaNotConfigured
+		}
+		return 0, nil, err
+	}
+	// re-read under the lock: another reviewer may have been first
+	if report, err = q.LockContactReport(ctx, reportID); err != nil {
+		return 0, nil, err
+	}
+	if report.Status != domain.ContactReportPending {
+		return 0, nil, domain.ErrContactReportClosed
+	}
+
+	amount := report.UnlockCost
+	if back := refundedUsage(quota, amount, report.UnlockedAt); back > 0 {
+		if err := q.IncrementCompanyQuotaUsed(ctx, db.IncrementCompanyQuotaUsedParams{
+			CompanyID: report.CompanyID,
+			Delta:     -back,
+		}); err != nil {
+			return 0, nil, err
+		}
+	}
+	ref := fmt.Sprintf("contact_report:%d", reportID)
+	var hrUserID *int64
+	if report.HrUserID.Valid {
+		hrUserID = &report.HrUserID.Int64
+	}
+	if _, err := q.AddCredits(ctx, db.AddCreditsParams{
+		CompanyID:   report.CompanyID,
+		Kind:        domain.CreditRefund,
+		Amount:      amount,
+		CandidateID: &report.CandidateID,
+		HrUserID:    hrUserID,
+		Ref:         &ref,
+		Note:        report.Reason,
+	}); err != nil {
+		return 0, nil, err
+	}
+
+	row, err := q.ResolveContactReport(ctx, db.ResolveContactReportParams{
+		ID:           reportID,
+		Status:       domain.ContactReportRefunded,
+		RefundAmount: amount,
+		ReviewedBy:   adminTgID,
+		ReviewNote:   note,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+	return row.CompanyID, contactReportFromRow(row), nil
+}
+
+// refundedUsage is the part of the period usage that refunding an unlock of the given cost
+// gives back: the cost, up to what was used, if the unlock happened in the quota's current
+// period. Unlocks from earlier periods were charged to an allowance that is gone.
+func refundedUsage(quota db.CompanyQuotaRow, cost int32, unlockedAt time.Time) int32 {
+	if !quota.PeriodStart.Valid || unlockedAt.Before(quota.PeriodStart.Time) {
+		return 0
+	}
+	return max(min(cost, quota.UnlockQuotaUsed), 0)
+}
+
+// Reject closes a pending report without a refund. Returns the company ID with the report.
+func (r *ContactReportRepo) Reject(ctx context.Context, reportID, adminTgID int64, note string) (int64, *domain.ContactReport, error) {
+	row, err := r.Q.ResolveContactReport(ctx, db.ResolveContactReportParams{
+		ID:         reportID,
+		Status:     domain.ContactReportRejected,
+		ReviewedBy: adminTgID,
+		ReviewNote: note,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// not found, or no longer pending
+		_, err = r.pendingReport(ctx, reportID)
+		if err == nil {
+			err = domain.ErrContactReportClosed
+		}
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return row.CompanyID, contactReportFromRow(row), nil
+}
+
+func (r *ContactReportRepo) pendingReport(ctx context.Context, reportID int64) (db.ContactReportRow, error) {
+	report, err := r.Q.GetContactReportByID(ctx, reportID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return report, domain.ErrNotFound
+		}
+		return report, err
+	}
+	if report.Status != domain.ContactReportPending {
+		return report, domain.ErrContactReportClosed
+	}
+	return report, nil
+}
+
+func contactReportFromRow(row db.ContactReportRow) *domain.ContactReport {
+	rep := &domain.ContactReport{
+		ID:            row.ID,
+		CandidateSlug: row.CandidateSlug,
+		Reason:        row.Reason,
+		Details:       row.Details,
+		Status:        row.Status,
+		RefundAmount:  row.RefundAmount,
+		ReviewNote:    row.ReviewNote,
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.ReviewedAt.Valid {
+		t := row.ReviewedAt.Time
+		rep.ReviewedAt = &t
+	}
+	return rep
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func TestContactReportRefundTxGivesUnlockBack(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, hrUserID, candidateIDs := seedUnlockFixture(t, pool, 5, 2)
+	if _, err := pool.Exec(ctx, `UPDATE candidates SET unlock_cost = 2 WHERE id = $1`, candidateIDs[0]); err != nil {
+		t.Fatal(err)
+	}
+	candidates := &CandidateRepo{Q: db.New(pool), Pool: pool}
+	r := &ContactReportRepo{Q: candidates.Q, Pool: pool}
+
+	if _, err := r.Create(ctx, companyID, hrUserID, candidateIDs[0], domain.ContactReportTelegramInvalid, ""); !errors.Is(err, domain.ErrContactNotUnlocked) {
+		t.Fatalf("report before unlock: got %v, want ErrContactNotUnlocked", err)
+	}
+	for _, id := range candidateIDs {
+		if _, err := candidates.UnlockContactTx(ctx, companyID, hrUserID, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := r.Create(ctx, companyID, hrUserID, candidateIDs[0], domain.ContactReportTelegramInvalid, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Create(ctx, companyID, hrUserID, candidateIDs[0], domain.ContactReportOther, ""); !errors.Is(err, domain.ErrContactReportExists) {
+		t.Fatalf("second report: got %v, want ErrContactReportExists", err)
+	}
+
+	gotCompany, refunded, err := r.RefundTx(ctx, report.ID, 42, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotCompany != companyID || refunded.Status != domain.ContactReportRefunded || refunded.RefundAmount != 2 {
+		t.Fatalf("company=%d report=%+v, want a 2-credit refund", gotCompany, refunded)
+	}
+	if b := creditBalance(t, candidates.Q, companyID); b.CreditBalance != 4 || b.UnlockQuotaUsed != 1 {
+		t.Fatalf("balance=%d used=%d after the refund, want 4 and 1", b.CreditBalance, b.UnlockQuotaUsed)
+	}
+	if _, _, err := r.RefundTx(ctx, report.ID, 42, ""); !errors.Is(err, domain.ErrContactReportClosed) {
+		t.Fatalf("second refund: got %v, want ErrContactReportClosed", err)
+	}
+
+	// a rejected report changes nothing
+	report, err = r.Create(ctx, companyID, hrUserID, candidateIDs[1], domain.ContactReportPhoneInvalid, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Reject(ctx, report.ID, 42, "works for us"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Reject(ctx, report.ID, 42, ""); !errors.Is(err, domain.ErrContactReportClosed) {
+		t.Fatalf("second reject: got %v, want ErrContactReportClosed", err)
+	}
+	if b := creditBalance(t, candidates.Q, companyID); b.CreditBalance != 4 {
+		t.Fatalf("balance = %d after a rejection, want 4", b.CreditBalance)
+	}
+}
+
+func TestRefundedUsage(t *testing.T) {
+	periodStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	quota := func(used int32) db.CompanyQuotaRow {
+		return db.CompanyQuotaRow{
+			UnlockQuotaTotal: 10,
+			UnlockQuotaUsed:  used,
+			PeriodStart:      pgtype.Date{Time: periodStart, Valid: true},
+			PeriodEnd:        pgtype.Date{Time: periodStart.AddDate(0, 0, 30), Valid: true},
+		}
+	}
+	tests := []struct {
+		name       string
+		quota      db.CompanyQuotaRow
+		cost       int32
+		unlockedAt time.Time
+		want       int32
+	}{
+		{"unlock in the current period", quota(4), 2, periodStart.Add(36 * time.Hour), 2},
+		{"unlock at the period start", quota(4), 1, periodStart, 1},
+		{"unlock in an earlier period", quota(4), 2, periodStart.Add(-time.Minute), 0},
+		{"usage lowered since the unlock", quota(1), 3, periodStart.Add(time.Hour), 1},
+		{"nothing used", quota(0), 2, periodStart.Add(time.Hour), 0},
+		{"free unlock", quota(4), 0, periodStart.Add(time.Hour), 0},
+		{"no period", db.CompanyQuotaRow{UnlockQuotaUsed: 4}, 2, periodStart, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refundedUsage(tt.quota, tt.cost, tt.unlockedAt); got != tt.want {
+				t.Errorf("refundedUsage = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
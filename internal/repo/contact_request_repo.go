@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+// requestContact opens (or renews an expired) contact request and queues the candidate's
+// accept/decline prompt. An existing live request is left alone: declined requests are
+// reported as such, anything else as still pending.
+func requestContact(ctx context.Context, q *db.Queries, companyID, hrUserID, candidateID int64) error {
+	id, err := q.UpsertContactRequest(ctx, db.UpsertContactRequestParams{
+		CompanyID:   companyID,
+		CandidateID: candidateID,
+		HrUserID:    hrUserID,
+		ExpiresAt:   time.Now().Add(domain.ContactRequestTTL),
+	})
+	if err == nil {
+		return q.EnqueueContactRequestNotification(ctx, db.EnqueueContactRequestNotificationParams{
+			RequestID: id,
+			Kind:      domain.BotOutboxContactRequest,
+		})
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	existing, err := q.GetContactRequest(ctx, db.GetContactRequestParams{CompanyID: companyID, CandidateID: candidateID})
+	if err != nil {
+		return err
+	}
+	if existing.Status == domain.ContactRequestDeclined {
+		return domain.ErrContactRequestDeclined
+	}
+	return nil
+}
+
+// AcceptContactRequestTx unlocks the contact for the requesting company and spends its
+// credits. The quota row is locked before the request, in the same order as UnlockContactTx.
+// On domain.ErrQuotaExceeded the request stays pending so the candidate can accept later.
+func (r *CandidateRepo) AcceptContactRequestTx(ctx context.Context, requestID, companyID int64) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+
+	quota, err := lockCurrentQuota(ctx, q, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrQuotaNotConfigured
+		}
+		return err
+	}
+	req, err := q.LockContactRequest(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	if req.CompanyID != companyID ||
+		domain.ContactRequestStatus(req.Status, req.ExpiresAt, time.Now()) != domain.ContactRequestPending {
+		return domain.ErrContactRequestClosed
+	}
+
+	gate, err := q.GetContactGate(ctx, db.GetContactGateParams{CompanyID: companyID, CandidateID: req.CandidateID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	_, insErr := q.UnlockCandidateContactIdempotent(ctx, db.UnlockCandidateContactIdempotentParams{
+		CompanyID:   req.CompanyID,
+		HrUserID:    req.HrUserID,
+		CandidateID: req.CandidateID,
+		Cost:        gate.UnlockCost,
+	})
+	switch {
+	case insErr == nil:
+		if err := chargeUnlock(ctx, q, quota, req.HrUserID, req.CandidateID, gate.UnlockCost); err != nil {
+			return err
+		}
+	case !errors.Is(insErr, pgx.ErrNoRows):
+		return insErr
+	}
+	// already unlocked (e.g. while the candidate was in open mode): nothing to charge
+
+	if _, err := q.DecideContactRequest(ctx, db.DecideContactRequestParams{
+		ID:     req.ID,
+		Status: domain.ContactRequestAccepted,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ContactRequestState returns the candidate's contact mode and the company's request, if any.
+func (r *CandidateRepo) ContactRequestState(ctx context.Context, companyID, candidateID int64) (string, *domain.ContactRequest, error) {
+	gate, err := r.Q.GetContactGate(ctx, db.GetContactGateParams{CompanyID: companyID, CandidateID: candidateID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, domain.ErrNotFound
+		}
+		return "", nil, err
+	}
+	row, err := r.Q.GetContactRequest(ctx, db.GetContactRequestParams{CompanyID: companyID, CandidateID: candidateID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return gate.ContactMode, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return gate.ContactMode, contactRequestFromRow(row), nil
+}
+
+func contactRequestFromRow(row db.ContactRequestRow) *domain.ContactRequest {
+	req := &domain.ContactRequest{
+		Status:      domain.ContactRequestStatus(row.Status, row.ExpiresAt, time.Now()),
+		RequestedAt: row.CreatedAt,
+		ExpiresAt:   row.ExpiresAt,
+	}
+	if row.DecidedAt.Valid {
+		t := row.DecidedAt.Time
+		req.DecidedAt = &t
+	}
+	return req
+}
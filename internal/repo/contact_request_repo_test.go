@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func TestUnlockContactTxRequestModeChargesOnAccept(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, hrUserID, candidateIDs := seedUnlockFixture(t, pool, 5, 2)
+	if _, err := pool.Exec(ctx, `UPDATE candidates SET contact_mode = 'request' WHERE id = ANY($1::bigint[])`, candidateIDs); err != nil {
+		t.Fatal(err)
+	}
+	r := &CandidateRepo{Q: db.New(pool), Pool: pool}
+
+	// unlocking twice opens one request and charges nothing
+	for i := 0; i < 2; i++ {
+		if _, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[0]); !errors.Is(err, domain.ErrContactRequestPending) {
+			t.Fatalf("unlock #%d: got %v, want ErrContactRequestPending", i+1, err)
+		}
+	}
+	if used := quotaUsed(t, pool, companyID); used != 0 {
+		t.Fatalf("unlock_quota_used = %d before accept, want 0", used)
+	}
+
+	req, err := r.Q.GetContactRequest(ctx, db.GetContactRequestParams{CompanyID: companyID, CandidateID: candidateIDs[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AcceptContactRequestTx(ctx, req.ID, companyID); err != nil {
+		t.Fatal(err)
+	}
+	if used := quotaUsed(t, pool, companyID); used != 1 {
+		t.Fatalf("unlock_quota_used = %d after accept, want 1", used)
+	}
+	if err := r.AcceptContactRequestTx(ctx, req.ID, companyID); !errors.Is(err, domain.ErrContactRequestClosed) {
+		t.Fatalf("second accept: got %v, want ErrContactRequestClosed", err)
+	}
+
+	// once accepted the contact is unlocked and re-unlocking stays free
+	first, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[0])
+	if err != nil || first {
+		t.Fatalf("unlock after accept: first=%v err=%v", first, err)
+	}
+
+	// declined requests block further unlocks
+	if _, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[1]); !errors.Is(err, domain.ErrContactRequestPending) {
+		t.Fatalf("got %v, want ErrContactRequestPending", err)
+	}
+	req, err = r.Q.GetContactRequest(ctx, db.GetContactRequestParams{CompanyID: companyID, CandidateID: candidateIDs[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Q.DecideContactRequest(ctx, db.DecideContactRequestParams{ID: req.ID, Status: domain.ContactRequestDeclined}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[1]); !errors.Is(err, domain.ErrContactRequestDeclined) {
+		t.Fatalf("got %v, want ErrContactRequestDeclined", err)
+	}
+	if used := quotaUsed(t, pool, companyID); used != 1 {
+		t.Fatalf("unlock_quota_used = %d, want 1", used)
+	}
+}
@@ -0,0 +1,157 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+type CreditRepo struct {
+	Q    *db.Queries
+	Pool *pgxpool.Pool
+}
+
+// chargeUnlock spends cost credits for a new unlock. quota must have been locked by the
+// caller's transaction (lockCurrentQuota). unlock_quota_used counts the credits spent in the
+// period, so variable costs show up in the period usage as well.
+func chargeUnlock(ctx context.Context, q *db.Queries, quota db.CompanyQuotaRow, hrUserID, candidateID int64, cost int32) error {
+	if quota.CreditBalance < cost {
+		return domain.ErrQuotaExceeded
+	}
+	if err := q.IncrementCompanyQuotaUsed(ctx, db.IncrementCompanyQuotaUsedParams{
+		CompanyID: quota.CompanyID,
+		Delta:     cost,
+	}); err != nil {
+		return err
+	}
+	_, err := q.AddCredits(ctx, db.AddCreditsParams{
+		CompanyID:   quota.CompanyID,
+		Kind:        domain.CreditUnlock,
+		Amount:      -cost,
+		CandidateID: &candidateID,
+		HrUserID:    &hrUserID,
+	})
+	return err
+}
+
+// unspentGrant is the part of a period's allowance not spent yet; it is what expires at the
+// end of the period (see RolloverCompanyQuota).
+func unspentGrant(total, used int32) int32 {
+	return max(total-used, 0)
+}
+
+// grantAdjustment is the credit adjustment for changing the period's allowance from
+// prevTotal/prevUsed to total/used: the change in its unspent grant, clamped so the
+// balance never goes negative.
+func grantAdjustment(prevTotal, prevUsed, total, used, balance int32) int32 {
+	return max(unspentGrant(total, used)-unspentGrant(prevTotal, prevUsed), -balance)
+}
+
+// adjustGrant books the change of row's allowance from prevTotal/prevUsed as a credit
+// adjustment (grantAdjustment) and updates row's balance. No entry is written when nothing
+// changes.
+func adjustGrant(ctx context.Context, q *db.Queries, row *db.CompanyQuotaDetailRow, prevTotal, prevUsed int32, note string) error {
+	amount := grantAdjustment(prevTotal, prevUsed, row.UnlockQuotaTotal, row.UnlockQuotaUsed, row.CreditBalance)
+	if amount == 0 {
+		return nil
+	}
+	entry, err := q.AddCredits(ctx, db.AddCreditsParams{
+		CompanyID: row.CompanyID,
+		Kind:      domain.CreditAdjustment,
+		Amount:    amount,
+		Note:      note,
+	})
+	if err != nil {
+		return err
+	}
+	row.CreditBalance = entry.BalanceAfter
+	return nil
+}
+
+// lockOrCreateQuota is lockCurrentQuota for credits that arrive from outside (top-ups,
+// purchases): companies registered before quotas existed get an empty quota row first.
+func lockOrCreateQuota(ctx context.Context, q *db.Queries, companyID int64) (db.CompanyQuotaRow, error) {
+	quota, err := lockCurrentQuota(ctx, q, companyID)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return quota, err
+	}
+	if err := q.CreateCompanyQuotaIfNotExists(ctx, companyID); err != nil {
+		return db.CompanyQuotaRow{}, err
+	}
+	return lockCurrentQuota(ctx, q, companyID)
+}
+
+// TopUpTx adds amount non-expiring credits to the company. A non-nil ref (e.g. a payment id)
+// makes the top-up idempotent: repeating it returns the original entry and false.
+func (r *CreditRepo) TopUpTx(ctx context.Context, companyID int64, amount int32, ref *string, note string) (db.CreditEntryRow, bool, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return db.CreditEntryRow{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	if _, err := lockOrCreateQuota(ctx, q, companyID); err != nil {
+		return db.CreditEntryRow{}, false, err
+	}
+
+	if ref != nil {
+		// the quota lock serializes top-ups, so the lookup cannot race a concurrent insert
+		prev, err := q.GetCreditEntryByRef(ctx, db.GetCreditEntryByRefParams{CompanyID: companyID, Kind: domain.CreditTopUp, Ref: *ref})
+		if err == nil {
+			return prev, false, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return db.CreditEntryRow{}, false, err
+		}
+	}
+
+	entry, err := q.AddCredits(ctx, db.AddCreditsParams{
+		CompanyID: companyID,
+		Kind:      domain.CreditTopUp,
+		Amount:    amount,
+		Ref:       ref,
+		Note:      note,
+	})
+	if err != nil {
+		return db.CreditEntryRow{}, false, err
+	}
+	return entry, true, tx.Commit(ctx)
+}
+
+// AdjustQuotaTx creates or updates the company's quota row (nil fields are left unchanged)
+// and books the change to the period's unspent grant as a credit adjustment, so the ledger
+// keeps adding up to the balance. Top-ups are not affected.
+func (r *CreditRepo) AdjustQuotaTx(ctx context.Context, companyID int64, total, used *int32, periodEnd pgtype.Date) (db.CompanyQuotaDetailRow, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return db.CompanyQuotaDetailRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	prev, err := lockCurrentQuota(ctx, q, companyID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return db.CompanyQuotaDetailRow{}, err
+	}
+	// a new row starts with no credits, as if total and used had been zero
+	row, err := q.AdminUpsertCompanyQuota(ctx, db.AdminUpsertCompanyQuotaParams{
+		CompanyID:        companyID,
+		UnlockQuotaTotal: total,
+		UnlockQuotaUsed:  used,
+		PeriodEnd:        periodEnd,
+	})
+	if err != nil {
+		return db.CompanyQuotaDetailRow{}, err
+	}
+	if err := adjustGrant(ctx, q, &row, prev.UnlockQuotaTotal, prev.UnlockQuotaUsed, "quota adjusted by admin"); err != nil {
+		return db.CompanyQuotaDetailRow{}, err
+	}
+	return row, tx.Commit(ctx)
+}
@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func creditBalance(t *testing.T, q *db.Queries, companyID int64) db.GetCreditBalanceRow {
+	t.Helper()
+	b, err := q.GetCreditBalance(context.Background(), companyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(b.CreditBalance) != b.LedgerBalance {
+		t.Fatalf("credit_balance = %d, ledger adds up to %d", b.CreditBalance, b.LedgerBalance)
+	}
+	return b
+}
+
+func TestUnlockContactTxSpendsUnlockCost(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, hrUserID, candidateIDs := seedUnlockFixture(t, pool, 3, 2)
+	if _, err := pool.Exec(ctx, `UPDATE candidates SET unlock_cost = 2 WHERE id = ANY($1::bigint[])`, candidateIDs); err != nil {
+		t.Fatal(err)
+	}
+	r := &CandidateRepo{Q: db.New(pool), Pool: pool}
+	credits := &CreditRepo{Q: r.Q, Pool: pool}
+
+	if _, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if b := creditBalance(t, r.Q, companyID); b.CreditBalance != 1 || b.UnlockQuotaUsed != 2 {
+		t.Fatalf("balance=%d used=%d after a 2-credit unlock, want 1 and 2", b.CreditBalance, b.UnlockQuotaUsed)
+	}
+	if _, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[1]); !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("got %v, want ErrQuotaExceeded with 1 credit left", err)
+	}
+
+	// a top-up is applied once per ref
+	ref := "inv-1"
+	for i := 0; i < 2; i++ {
+		_, added, err := credits.TopUpTx(ctx, companyID, 5, &ref, "")
+		if err != nil || added != (i == 0) {
+			t.Fatalf("top-up #%d: added=%v err=%v", i+1, added, err)
+		}
+	}
+	if _, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[1]); err != nil {
+		t.Fatal(err)
+	}
+	if b := creditBalance(t, r.Q, companyID); b.CreditBalance != 4 {
+		t.Fatalf("balance = %d, want 4", b.CreditBalance)
+	}
+
+	// allowance changes move the unspent grant only; top-ups survive a cut
+	for _, c := range []struct{ total, balance int32 }{{10, 10}, {0, 4}} {
+		total := c.total
+		row, err := credits.AdjustQuotaTx(ctx, companyID, &total, nil, pgtype.Date{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row.CreditBalance != c.balance {
+			t.Fatalf("balance = %d with an allowance of %d, want %d", row.CreditBalance, c.total, c.balance)
+		}
+		creditBalance(t, r.Q, companyID)
+	}
+}
+
+func TestGrantAdjustment(t *testing.T) {
+	tests := []struct {
+		name                 string
+		prevTotal, prevUsed  int32
+		total, used, balance int32
+		want                 int32
+	}{
+		{"unchanged", 10, 4, 10, 4, 6, 0},
+		{"new quota row", 0, 0, 5, 0, 0, 5},
+		{"larger allowance", 10, 4, 20, 4, 6, 10},
+		{"smaller allowance", 20, 4, 10, 4, 16, -10},
+		{"allowance below usage", 10, 5, 3, 5, 5, -5},
+		{"usage reset", 10, 8, 10, 0, 2, 8},
+		{"overspent period", 10, 12, 20, 12, 0, 8},
+		{"balance never goes negative", 10, 0, 0, 0, 3, -3},
+		{"empty balance", 10, 0, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grantAdjustment(tt.prevTotal, tt.prevUsed, tt.total, tt.used, tt.balance); got != tt.want {
+				t.Errorf("grantAdjustment = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
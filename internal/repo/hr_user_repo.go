@@ -5,13 +5,15 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
 )
 
 type HRUserRepo struct {
 	Q    *db.Queries
-	Pool any // *pgxpool.Pool, but not imported here to avoid circular dependency
+	Pool *pgxpool.Pool
 	// DefaultStatus controls new HR user status when auto-creating accounts.
 	// Expected values: "active" or "pending".
 	DefaultStatus string
@@ -19,46 +21,241 @@ type HRUserRepo struct {
 
 // GetOrCreateHRUserByTelegramID finds or creates an HR user linked to a Telegram account
 // This implements the HRUserRepository interface in handlers/auth.go
-func (r *HRUserRepo) GetOrCreateHRUserByTelegramID(userID int64, username, displayName string) (hrUserID, companyID int64, status string, err error) {
+//
+// New users with a valid inviteCode join the inviting company with the invite's role;
+// otherwise they get a fresh company of their own and become its owner.
+// Existing users accept an invite with moveHRUserToInvite.
+func (r *HRUserRepo) GetOrCreateHRUserByTelegramID(userID int64, username, displayName, inviteCode string) (domain.HRUserState, error) {
 	// First, try to find existing
 	q := r.Q
 	ctx := context.Background()
 	row, findErr := q.FindHRUserByTelegramID(ctx, userID)
 	if findErr == nil {
+		if inviteCode != "" {
+			return r.moveHRUserToInvite(ctx, row, inviteCode)
+		}
 		// Exists
-		return row.ID, row.CompanyID, row.Status, nil
+		return domain.HRUserState{
+			HRUserID:       row.ID,
+			CompanyID:      row.CompanyID,
+			Status:         row.Status,
+			Role:           row.Role,
+			SessionVersion: row.SessionVersion,
+		}, nil
 	}
 	if !errors.Is(findErr, pgx.ErrNoRows) {
-		return 0, 0, "", findErr
+		return domain.HRUserState{}, findErr
 	}
 
+	if inviteCode != "" {
+		return r.createHRUserFromInvite(ctx, userID, username, displayName, inviteCode)
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return domain.HRUserState{}, err
+	}
+	defer tx.Rollback(ctx)
+	q = q.WithTx(tx)
+
 	// Create new: first create company if needed, then HR user
 	// For MVP: auto-create a company for new Telegram users
-	companyID, err = q.CreateDefaultCompany(ctx)
+	companyID, err := q.CreateDefaultCompany(ctx)
 	if err != nil {
-		return 0, 0, "", err
+		return domain.HRUserState{}, err
 	}
 	if err := q.CreateCompanyQuotaIfNotExists(ctx, companyID); err != nil {
-		return 0, 0, "", err
+		return domain.HRUserState{}, err
 	}
 
-	status = r.DefaultStatus
+	status := r.DefaultStatus
 	if status == "" {
 		status = "pending"
 	}
 
 	// Create HR user with configured default status
-	hrUserID, err = q.CreateHRUser(ctx, db.CreateHRUserParams{
+	hrUserID, err := q.CreateHRUser(ctx, db.CreateHRUserParams{
 		CompanyID:   companyID,
 		TgUserID:    userID,
 		TgUsername:  username,
 		DisplayName: displayName,
-		Role:        "recruiter",
+		Role:        domain.RoleOwner,
 		Status:      status,
 	})
 	if err != nil {
-		return 0, 0, "", err
+		return domain.HRUserState{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.HRUserState{}, err
+	}
+	return domain.HRUserState{HRUserID: hrUserID, CompanyID: companyID, Status: status, Role: domain.RoleOwner}, nil
+}
+
+// GetHRUserState returns the user's current company, status, role and session_version,
+// bypassing the cache. Removed (or missing) users yield domain.ErrNotFound.
+func (r *HRUserRepo) GetHRUserState(ctx context.Context, hrUserID int64) (domain.HRUserState, error) {
+	u, err := r.Q.GetHRUserState(ctx, hrUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.HRUserState{}, domain.ErrNotFound
+		}
+		return domain.HRUserState{}, err
+	}
+	if u.Status == domain.HRStatusRemoved {
+		return domain.HRUserState{}, domain.ErrNotFound
+	}
+	return domain.HRUserState{
+		HRUserID:       u.ID,
+		CompanyID:      u.CompanyID,
+		Status:         u.Status,
+		Role:           u.Role,
+		SessionVersion: u.SessionVersion,
+	}, nil
+}
+
+// createHRUserFromInvite redeems an invite code: lock invite -> check seats -> create member ->
+// count usage. Invited members are active immediately since an owner vouched for them, so
+// they need a free seat on the company's plan (domain.ErrSeatLimit otherwise).
+func (r *HRUserRepo) createHRUserFromInvite(ctx context.Context, userID int64, username, displayName, code string) (domain.HRUserState, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return domain.HRUserState{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := r.Q.WithTx(tx)
+
+	inv, err := q.LockRedeemableInviteByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.HRUserState{}, domain.ErrInviteInvalid
+		}
+		return domain.HRUserState{}, err
+	}
+
+	if err := q.LockCompanyForUpdate(ctx, inv.CompanyID); err != nil {
+		return domain.HRUserState{}, err
+	}
+	if err := EnsureSeatAvailable(ctx, q, inv.CompanyID); err != nil {
+		return domain.HRUserState{}, err
+	}
+
+	status := "active"
+	hrUserID, err := q.CreateHRUser(ctx, db.CreateHRUserParams{
+		CompanyID:   inv.CompanyID,
+		TgUserID:    userID,
+		TgUsername:  username,
+		DisplayName: displayName,
+		Role:        inv.Role,
+		Status:      status,
+	})
+	if err != nil {
+		return domain.HRUserState{}, err
+	}
+	if err := q.MarkCompanyInviteUsed(ctx, inv.ID); err != nil {
+		return domain.HRUserState{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.HRUserState{}, err
+	}
+	return domain.HRUserState{HRUserID: hrUserID, CompanyID: inv.CompanyID, Status: status, Role: inv.Role}, nil
+}
+
+// moveHRUserToInvite redeems an invite for an existing user. Joining the company they are
+// already in changes nothing. Otherwise they may only leave a company they are alone in
+// and that has no unlocks or payments, typically the one auto-created at their first login
+// (domain.ErrInviteAlreadyMember otherwise); the company is left behind empty.
+func (r *HRUserRepo) moveHRUserToInvite(ctx context.Context, user db.FindHRUserByTelegramIDRow, code string) (domain.HRUserState, error) {
+	state := domain.HRUserState{
+		HRUserID:       user.ID,
+		CompanyID:      user.CompanyID,
+		Status:         user.Status,
+		Role:           user.Role,
+		SessionVersion: user.SessionVersion,
+	}
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return domain.HRUserState{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := r.Q.WithTx(tx)
+
+	inv, err := q.LockRedeemableInviteByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.HRUserState{}, domain.ErrInviteInvalid
+		}
+		return domain.HRUserState{}, err
+	}
+	if inv.CompanyID == user.CompanyID {
+		return state, nil
 	}
 
-	return hrUserID, companyID, status, nil
+	// both companies' memberships change; lock them in a fixed order
+	for _, id := range []int64{min(user.CompanyID, inv.CompanyID), max(user.CompanyID, inv.CompanyID)} {
+		if err := q.LockCompanyForUpdate(ctx, id); err != nil {
+			return domain.HRUserState{}, err
+		}
+	}
+	empty, err := q.IsSoleMemberOfEmptyCompany(ctx, db.IsSoleMemberOfEmptyCompanyParams{CompanyID: user.CompanyID, HrUserID: user.ID})
+	if err != nil {
+		return domain.HRUserState{}, err
+	}
+	if !empty {
+		return domain.HRUserState{}, domain.ErrInviteAlreadyMember
+	}
+	if err := EnsureSeatAvailable(ctx, q, inv.CompanyID); err != nil {
+		return domain.HRUserState{}, err
+	}
+
+	version, err := q.MoveHRUserToCompany(ctx, db.MoveHRUserToCompanyParams{ID: user.ID, CompanyID: inv.CompanyID, Role: inv.Role})
+	if err != nil {
+		return domain.HRUserState{}, err
+	}
+	if err := q.MarkCompanyInviteUsed(ctx, inv.ID); err != nil {
+		return domain.HRUserState{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.HRUserState{}, err
+	}
+	return domain.HRUserState{
+		HRUserID:       user.ID,
+		CompanyID:      inv.CompanyID,
+		Status:         domain.HRStatusActive,
+		Role:           inv.Role,
+		SessionVersion: version,
+	}, nil
+}
+
+// MemberChange is applied to a locked member row inside WithLockedMember's transaction.
+type MemberChange func(ctx context.Context, q *db.Queries, member db.LockCompanyMemberRow) error
+
+// WithLockedMember locks the company (serializing membership changes) and the member row,
+// then runs fn in the same transaction. Returns domain.ErrNotFound if memberID is not in companyID.
+func (r *HRUserRepo) WithLockedMember(ctx context.Context, companyID, memberID int64, fn MemberChange) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := r.Q.WithTx(tx)
+
+	if err := q.LockCompanyForUpdate(ctx, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	member, err := q.LockCompanyMember(ctx, db.LockCompanyMemberParams{ID: memberID, CompanyID: companyID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	if err := fn(ctx, q, member); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
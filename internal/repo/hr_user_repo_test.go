@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+// seedTelegramOwner seeds a company whose owner logs in with a fresh Telegram ID.
+func seedTelegramOwner(t *testing.T, pool *pgxpool.Pool) (companyID, hrUserID, tgUserID int64) {
+	t.Helper()
+	companyID, hrUserID = dbtest.SeedCompany(t, pool, 0)
+	tgUserID = time.Now().UnixNano()
+	if _, err := pool.Exec(context.Background(), `UPDATE hr_users SET tg_user_id = $2 WHERE id = $1`, hrUserID, tgUserID); err != nil {
+		t.Fatal(err)
+	}
+	return companyID, hrUserID, tgUserID
+}
+
+func seedInvite(t *testing.T, q *db.Queries, companyID, createdBy int64) string {
+	t.Helper()
+	code := fmt.Sprintf("T%011d", time.Now().UnixNano()%1e11)
+	if _, err := q.CreateCompanyInvite(context.Background(), db.CreateCompanyInviteParams{
+		CompanyID: companyID,
+		Code:      code,
+		Role:      domain.RoleRecruiter,
+		MaxUses:   5,
+		CreatedBy: createdBy,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func inviteUses(t *testing.T, pool *pgxpool.Pool, code string) int {
+	t.Helper()
+	var n int
+	if err := pool.QueryRow(context.Background(), `SELECT used_count FROM company_invites WHERE code = $1`, code).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestExistingSoleOwnerAcceptsInvite(t *testing.T) {
+	pool := dbtest.Pool(t)
+	q := db.New(pool)
+	r := &HRUserRepo{Q: q, Pool: pool, DefaultStatus: domain.HRStatusActive}
+	inviting, inviter := dbtest.SeedCompany(t, pool, 0)
+	code := seedInvite(t, q, inviting, inviter)
+	oldCompany, hrUserID, tgUserID := seedTelegramOwner(t, pool)
+
+	before, err := r.GetOrCreateHRUserByTelegramID(tgUserID, "sole", "Sole Owner", "")
+	if err != nil || before.CompanyID != oldCompany {
+		t.Fatalf("login without invite: %+v, %v", before, err)
+	}
+	got, err := r.GetOrCreateHRUserByTelegramID(tgUserID, "sole", "Sole Owner", code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.HRUserID != hrUserID || got.CompanyID != inviting || got.Role != domain.RoleRecruiter || got.Status != domain.HRStatusActive {
+		t.Errorf("after accepting: %+v, want a recruiter of company %d", got, inviting)
+	}
+	if got.SessionVersion <= before.SessionVersion {
+		t.Errorf("session_version %d -> %d, want the old sessions ended", before.SessionVersion, got.SessionVersion)
+	}
+	if n := inviteUses(t, pool, code); n != 1 {
+		t.Errorf("invite used %d times, want 1", n)
+	}
+
+	// logging in again with the same code keeps the new membership
+	again, err := r.GetOrCreateHRUserByTelegramID(tgUserID, "sole", "Sole Owner", code)
+	if err != nil || again.CompanyID != inviting || inviteUses(t, pool, code) != 1 {
+		t.Errorf("second login with the invite: %+v, %v, uses %d", again, err, inviteUses(t, pool, code))
+	}
+}
+
+func TestExistingMemberCannotLeaveTeamForInvite(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	q := db.New(pool)
+	r := &HRUserRepo{Q: q, Pool: pool, DefaultStatus: domain.HRStatusActive}
+	inviting, inviter := dbtest.SeedCompany(t, pool, 0)
+	code := seedInvite(t, q, inviting, inviter)
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, companyID, hrUserID int64)
+	}{
+		{"company has a teammate", func(t *testing.T, companyID, _ int64) {
+			if _, err := pool.Exec(ctx, `INSERT INTO hr_users (company_id, display_name, status) VALUES ($1, 'mate', 'active')`, companyID); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"company has unlocked contacts", func(t *testing.T, companyID, hrUserID int64) {
+			candidateID := dbtest.SeedCandidates(t, pool, 1)[0]
+			if _, err := pool.Exec(ctx, `INSERT INTO unlocks (company_id, hr_user_id, candidate_id, unlock_type) VALUES ($1, $2, $3, 'contact')`,
+				companyID, hrUserID, candidateID); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		companyID, hrUserID, tgUserID := seedTelegramOwner(t, pool)
+		tt.setup(t, companyID, hrUserID)
+
+		if _, err := r.GetOrCreateHRUserByTelegramID(tgUserID, "owner", "Owner", code); !errors.Is(err, domain.ErrInviteAlreadyMember) {
+			t.Errorf("%s: got %v, want ErrInviteAlreadyMember", tt.name, err)
+		}
+		state, err := r.GetHRUserState(ctx, hrUserID)
+		if err != nil || state.CompanyID != companyID || state.Role != domain.RoleOwner {
+			t.Errorf("%s: user is now %+v, %v; want the owner of company %d", tt.name, state, err, companyID)
+		}
+	}
+	if n := inviteUses(t, pool, code); n != 0 {
+		t.Errorf("refused invites were used %d times", n)
+	}
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/payments"
+)
+
+type PaymentRepo struct {
+	Q    *db.Queries
+	Pool *pgxpool.Pool
+}
+
+// ApplyEventTx records a provider event and applies it to its payment in the same
+// transaction, so every event takes effect exactly once: a redelivered event (same provider
+// and ID) changes nothing and returns false. What the event does is decided by
+// domain.PaymentEvent.Outcome on the locked payment. A success adds the payment's credits as
+// a top-up with ref payments.Reference(id); a payment reported as paid under several event
+// IDs is still credited once. A success for a different amount or currency than was charged
+// fails the payment with domain.ErrPaymentAmountMismatch (the event stays recorded). Returns
+// the payment as it is now; domain.ErrNotFound if the event does not match a payment of that
+// provider.
+func (r *PaymentRepo) ApplyEventTx(ctx context.Context, ev domain.PaymentEvent, payload []byte) (db.PaymentRow, bool, error) {
+	payment, err := r.eventPayment(ctx, ev)
+	if err != nil {
+		return db.PaymentRow{}, false, err
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return db.PaymentRow{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	if _, err := q.InsertPaymentEvent(ctx, db.InsertPaymentEventParams{
+		Provider:  ev.Provider,
+		EventID:   ev.ID,
+		PaymentID: &payment.ID,
+		Type:      ev.Type,
+		Payload:   payload,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return payment, false, nil
+		}
+		return db.PaymentRow{}, false, err
+	}
+
+	if ev.Type == domain.PaymentSucceeded {
+		// quota row before the payment, like the other credit transactions
+		if _, err := lockOrCreateQuota(ctx, q, payment.CompanyID); err != nil {
+			return db.PaymentRow{}, false, err
+		}
+	}
+	if payment, err = q.LockPayment(ctx, payment.ID); err != nil {
+		return db.PaymentRow{}, false, err
+	}
+
+	switch ev.Outcome(payment.Status, payment.Amount, payment.Currency) {
+	case domain.PaymentUnchanged:
+		return payment, false, tx.Commit(ctx)
+	case domain.PaymentFail:
+		row, err := q.MarkPaymentFailed(ctx, db.MarkPaymentFailedParams{ID: payment.ID, Reason: ev.FailureReason})
+		if err != nil {
+			return db.PaymentRow{}, false, err
+		}
+		return row, true, tx.Commit(ctx)
+	case domain.PaymentMismatch:
+		row, err := q.MarkPaymentFailed(ctx, db.MarkPaymentFailedParams{ID: payment.ID, Reason: "amount_mismatch"})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// failed before
+			row, err = payment, nil
+		}
+		if err != nil {
+			return db.PaymentRow{}, false, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return db.PaymentRow{}, false, err
+		}
+		return row, false, domain.ErrPaymentAmountMismatch
+	}
+
+	var providerRef *string
+	if ev.ProviderRef != "" {
+		providerRef = &ev.ProviderRef
+	}
+	row, err := q.MarkPaymentSucceeded(ctx, db.MarkPaymentSucceededParams{ID: payment.ID, ProviderRef: providerRef})
+	if err != nil {
+		return db.PaymentRow{}, false, err
+	}
+	ref := payments.Reference(payment.ID)
+	var hrUserID *int64
+	if payment.HrUserID.Valid {
+		hrUserID = &payment.HrUserID.Int64
+	}
+	if _, err := q.AddCredits(ctx, db.AddCreditsParams{
+		CompanyID: payment.CompanyID,
+		Kind:      domain.CreditTopUp,
+		Amount:    payment.Credits,
+		HrUserID:  hrUserID,
+		Ref:       &ref,
+		Note:      payment.PackCode,
+	}); err != nil {
+		return db.PaymentRow{}, false, err
+	}
+	return row, true, tx.Commit(ctx)
+}
+
+// eventPayment finds the payment an event is about, by ID or by the provider's reference.
+func (r *PaymentRepo) eventPayment(ctx context.Context, ev domain.PaymentEvent) (db.PaymentRow, error) {
+	var (
+		payment db.PaymentRow
+		err     error
+	)
+	switch {
+	case ev.PaymentID != 0:
+		payment, err = r.Q.GetPayment(ctx, ev.PaymentID)
+	case ev.ProviderRef != "":
+		payment, err = r.Q.GetPaymentByProviderRef(ctx, db.GetPaymentByProviderRefParams{Provider: ev.Provider, ProviderRef: ev.ProviderRef})
+	default:
+		return payment, domain.ErrNotFound
+	}
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && payment.Provider != ev.Provider) {
+		return payment, domain.ErrNotFound
+	}
+	return payment, err
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func TestPaymentApplyEventTxCreditsOnce(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, hrUserID := dbtest.SeedCompany(t, pool, 0)
+	q := db.New(pool)
+	r := &PaymentRepo{Q: q, Pool: pool}
+
+	var packID int64
+	code := fmt.Sprintf("test_pack_%d_%d", companyID, time.Now().UnixNano())
+	if err := pool.QueryRow(ctx, `INSERT INTO credit_packs (code, name, credits, price_minor) VALUES ($1, $1, 10, 9900) RETURNING id`, code).Scan(&packID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM payments WHERE pack_id = $1`, packID)
+		_, _ = pool.Exec(context.Background(), `DELETE FROM credit_packs WHERE id = $1`, packID)
+	})
+	newPayment := func() db.PaymentRow {
+		t.Helper()
+		p, err := q.CreatePayment(ctx, db.CreatePaymentParams{
+			CompanyID: companyID, HrUserID: hrUserID, PackID: packID,
+			Provider: domain.PaymentProviderFake, Credits: 10, Amount: 9900, Currency: "CNY",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref := fmt.Sprintf("chk_%s_%d", code, p.ID)
+		if p, err = q.SetPaymentCheckout(ctx, db.SetPaymentCheckoutParams{ID: p.ID, ProviderRef: &ref}); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	payment := newPayment()
+	paid := domain.PaymentEvent{
+		Provider:    domain.PaymentProviderFake,
+		ID:          fmt.Sprintf("evt_%s_%d", code, payment.ID),
+		Type:        domain.PaymentSucceeded,
+		ProviderRef: payment.ProviderRef.String,
+		Amount:      9900,
+		Currency:    "CNY",
+	}
+	row, applied, err := r.ApplyEventTx(ctx, paid, []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !applied || row.Status != domain.PaymentSucceeded || !row.PaidAt.Valid {
+		t.Fatalf("applied=%v payment=%+v, want a succeeded payment", applied, row)
+	}
+	if b := creditBalance(t, q, companyID); b.CreditBalance != 10 {
+		t.Fatalf("balance = %d after paying for 10 credits", b.CreditBalance)
+	}
+
+	// a redelivery, the same payment under another event ID and a late failure change nothing
+	again := paid
+	again.ID += "_other"
+	failed := paid
+	failed.ID += "_failed"
+	failed.Type = domain.PaymentFailed
+	for _, ev := range []domain.PaymentEvent{paid, again, failed} {
+		row, applied, err := r.ApplyEventTx(ctx, ev, []byte(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if applied || row.Status != domain.PaymentSucceeded {
+			t.Fatalf("event %s: applied=%v status=%s, want no change", ev.ID, applied, row.Status)
+		}
+	}
+	if b := creditBalance(t, q, companyID); b.CreditBalance != 10 {
+		t.Fatalf("balance = %d after repeated events, want 10", b.CreditBalance)
+	}
+
+	// a different amount than was charged is not credited
+	payment = newPayment()
+	short := domain.PaymentEvent{
+		Provider:  domain.PaymentProviderFake,
+		ID:        fmt.Sprintf("evt_%s_%d", code, payment.ID),
+		Type:      domain.PaymentSucceeded,
+		PaymentID: payment.ID,
+		Amount:    100,
+		Currency:  "CNY",
+	}
+	row, _, err = r.ApplyEventTx(ctx, short, []byte(`{}`))
+	if !errors.Is(err, domain.ErrPaymentAmountMismatch) || row.Status != domain.PaymentFailed {
+		t.Fatalf("got %v and status %s, want ErrPaymentAmountMismatch and a failed payment", err, row.Status)
+	}
+	if b := creditBalance(t, q, companyID); b.CreditBalance != 10 {
+		t.Fatalf("balance = %d after a short payment, want 10", b.CreditBalance)
+	}
+
+	// events only apply to payments of their own provider
+	other := short
+	other.Provider = domain.PaymentProviderCard
+	if _, _, err := r.ApplyEventTx(ctx, other, []byte(`{}`)); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("event from another provider: got %v, want ErrNotFound", err)
+	}
+}
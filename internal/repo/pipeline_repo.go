@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+type PipelineRepo struct {
+	Q    *db.Queries
+	Pool *pgxpool.Pool
+}
+
+// CreatePipelineTx creates a pipeline with the given stages, in order.
+func (r *PipelineRepo) CreatePipelineTx(ctx context.Context, companyID, hrUserID int64, name string, stages []string) (db.PipelineRow, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return db.PipelineRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	p, err := q.CreatePipeline(ctx, db.CreatePipelineParams{CompanyID: companyID, Name: name, CreatedBy: hrUserID})
+	if err != nil {
+		return db.PipelineRow{}, err
+	}
+	for i, s := range stages {
+		if _, err := q.CreatePipelineStage(ctx, db.CreatePipelineStageParams{PipelineID: p.ID, Name: s, Position: int32(i)}); err != nil {
+			return db.PipelineRow{}, err
+		}
+	}
+	return p, tx.Commit(ctx)
+}
+
+// ReplaceStagesTx makes stages the pipeline's stage list, in order: stages with an ID are
+// renamed and moved, the others are created, and existing stages that are not listed are
+// deleted. Deleting a stage that still holds candidates fails with
+// domain.ErrPipelineStageNotEmpty; IDs of other pipelines' stages with
+// domain.ErrPipelineStageInvalid.
+func (r *PipelineRepo) ReplaceStagesTx(ctx context.Context, companyID, pipelineID int64, stages []domain.PipelineStageInput) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	if _, err := q.LockPipeline(ctx, db.GetPipelineParams{ID: pipelineID, CompanyID: companyID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	existing, err := q.ListPipelineStages(ctx, []int64{pipelineID})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(existing))
+	for _, e := range existing {
+		ids = append(ids, e.ID)
+	}
+	dropped, err := droppedStages(ids, stages)
+	if err != nil {
+		return err
+	}
+	for _, id := range dropped {
+		if err := q.PurgeDeletedPipelineStageCandidates(ctx, id); err != nil {
+			return err
+		}
+		n, err := q.DeleteEmptyPipelineStage(ctx, id)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return domain.ErrPipelineStageNotEmpty
+		}
+	}
+
+	for i, s := range stages {
+		if s.ID == 0 {
+			if _, err := q.CreatePipelineStage(ctx, db.CreatePipelineStageParams{PipelineID: pipelineID, Name: s.Name, Position: int32(i)}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := q.UpdatePipelineStage(ctx, db.UpdatePipelineStageParams{ID: s.ID, Name: s.Name, Position: int32(i)}); err != nil {
+			return err
+		}
+	}
+
+	if err := q.TouchPipeline(ctx, pipelineID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// droppedStages returns the existing stages (by ID) that the new stage list leaves out.
+// Listing a stage twice, or a stage that is not one of existing, fails with
+// domain.ErrPipelineStageInvalid.
+func droppedStages(existing []int64, stages []domain.PipelineStageInput) ([]int64, error) {
+	known := make(map[int64]bool, len(existing))
+	for _, id := range existing {
+		known[id] = true
+	}
+	kept := make(map[int64]bool, len(stages))
+	for _, s := range stages {
+		if s.ID == 0 {
+			continue
+		}
+		if !known[s.ID] || kept[s.ID] {
+			return nil, domain.ErrPipelineStageInvalid
+		}
+		kept[s.ID] = true
+	}
+	var dropped []int64
+	for _, id := range existing {
+		if !kept[id] {
+			dropped = append(dropped, id)
+		}
+	}
+	return dropped, nil
+}
@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func TestReplaceStagesTxKeepsOccupiedStages(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, hrUserID, candidateIDs := seedUnlockFixture(t, pool, 0, 1)
+	r := &PipelineRepo{Q: db.New(pool), Pool: pool}
+
+	p, err := r.CreatePipelineTx(ctx, companyID, hrUserID, "backend", []string{"contacted", "interviewing", "hired"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stages, err := r.Q.ListPipelineStages(ctx, []int64{p.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contacted, interviewing, hired := stages[0], stages[1], stages[2]
+	if _, err := r.Q.MovePipelineCandidate(ctx, db.MovePipelineCandidateParams{
+		PipelineID: p.ID, StageID: interviewing.ID, CandidateID: candidateIDs[0], HrUserID: hrUserID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// dropping the occupied stage fails and changes nothing
+	err = r.ReplaceStagesTx(ctx, companyID, p.ID, []domain.PipelineStageInput{{ID: contacted.ID, Name: "contacted"}})
+	if !errors.Is(err, domain.ErrPipelineStageNotEmpty) {
+		t.Fatalf("got %v, want ErrPipelineStageNotEmpty", err)
+	}
+	if got, _ := r.Q.ListPipelineStages(ctx, []int64{p.ID}); len(got) != 3 {
+		t.Fatalf("got %d stages after failed replace, want 3", len(got))
+	}
+
+	// reorder, rename, drop an empty stage and add one
+	err = r.ReplaceStagesTx(ctx, companyID, p.ID, []domain.PipelineStageInput{
+		{ID: interviewing.ID, Name: "interview"},
+		{Name: "offer"},
+		{ID: contacted.ID, Name: "contacted"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Q.ListPipelineStages(ctx, []int64{p.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].ID != interviewing.ID || got[0].Name != "interview" || got[0].CandidateCount != 1 ||
+		got[1].Name != "offer" || got[2].ID != contacted.ID {
+		t.Fatalf("unexpected stages %+v", got)
+	}
+	for _, s := range got {
+		if s.ID == hired.ID {
+			t.Fatal("empty stage not deleted")
+		}
+	}
+
+	// stages of other pipelines are rejected
+	other, err := r.CreatePipelineTx(ctx, companyID, hrUserID, "other", []string{"x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.ReplaceStagesTx(ctx, companyID, other.ID, []domain.PipelineStageInput{{ID: contacted.ID, Name: "x"}})
+	if !errors.Is(err, domain.ErrPipelineStageInvalid) {
+		t.Fatalf("got %v, want ErrPipelineStageInvalid", err)
+	}
+}
+
+func TestDroppedStages(t *testing.T) {
+	existing := []int64{10, 11, 12}
+	tests := []struct {
+		name    string
+		stages  []domain.PipelineStageInput
+		want    []int64
+		wantErr error
+	}{
+		{"all kept and reordered", []domain.PipelineStageInput{{ID: 12}, {ID: 10}, {ID: 11}}, nil, nil},
+		{"one left out", []domain.PipelineStageInput{{ID: 10}, {ID: 12}}, []int64{11}, nil},
+		{"new stages only", []domain.PipelineStageInput{{Name: "a"}, {Name: "b"}}, []int64{10, 11, 12}, nil},
+		{"new and kept", []domain.PipelineStageInput{{Name: "offer"}, {ID: 11}}, []int64{10, 12}, nil},
+		{"listed twice", []domain.PipelineStageInput{{ID: 10}, {ID: 10}}, nil, domain.ErrPipelineStageInvalid},
+		{"another pipeline's stage", []domain.PipelineStageInput{{ID: 10}, {ID: 99}}, nil, domain.ErrPipelineStageInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := droppedStages(existing, tt.stages)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("dropped = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
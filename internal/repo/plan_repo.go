@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+type PlanRepo struct {
+	Q    *db.Queries
+	Pool *pgxpool.Pool
+}
+
+// AssignPlanTx puts the company on planID from effectiveFrom on. An assignment that is
+// already in effect also replaces the current period's allowance (usage is kept) and books
+// the difference as a credit adjustment, so upgrades apply immediately; scheduled ones
+// apply from the first period starting on or after effectiveFrom. The returned quota is
+// nil when the current period is unchanged.
+func (r *PlanRepo) AssignPlanTx(ctx context.Context, companyID, planID int64, effectiveFrom time.Time, assignedBy int64) (*db.CompanyQuotaDetailRow, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	// lock the quota row first, like UnlockContactTx, so no unlock runs on the old allowance
+	quota, err := lockCurrentQuota(ctx, q, companyID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	configured := err == nil
+	if err := q.AssignCompanyPlan(ctx, db.AssignCompanyPlanParams{
+		CompanyID:     companyID,
+		PlanID:        planID,
+		EffectiveFrom: pgtype.Date{Time: effectiveFrom, Valid: true},
+		AssignedBy:    assignedBy,
+	}); err != nil {
+		return nil, err
+	}
+
+	var applied *db.CompanyQuotaDetailRow
+	if configured && !effectiveFrom.After(time.Now()) {
+		row, err := q.ApplyCompanyPlanQuota(ctx, quota.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+		if err := adjustGrant(ctx, q, &row, quota.UnlockQuotaTotal, quota.UnlockQuotaUsed, "plan changed"); err != nil {
+			return nil, err
+		}
+		applied = &row
+	}
+	return applied, tx.Commit(ctx)
+}
+
+// EnsureSeatAvailable fails with domain.ErrSeatLimit if the company's plan allows no more
+// active HR users. Callers serialize membership changes with LockCompanyForUpdate.
+func EnsureSeatAvailable(ctx context.Context, q *db.Queries, companyID int64) error {
+	seats, err := q.GetCompanySeats(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	if seatsFull(seats.SeatLimit, seats.ActiveSeats) {
+		return domain.ErrSeatLimit
+	}
+	return nil
+}
+
+// seatsFull reports whether active HR users take all seats of a plan's seat limit
+// (0 = unlimited).
+func seatsFull(limit int32, active int64) bool {
+	return limit > 0 && active >= int64(limit)
+}
@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func TestAssignPlanTxAppliesAllowanceAndSeats(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, _ := dbtest.SeedCompany(t, pool, 1)
+	r := &PlanRepo{Q: db.New(pool), Pool: pool}
+
+	var starterID, proID int64
+	if err := pool.QueryRow(ctx, `SELECT id FROM plans WHERE code = 'starter'`).Scan(&starterID); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, `SELECT id FROM plans WHERE code = 'pro'`).Scan(&proID); err != nil {
+		t.Fatal(err)
+	}
+
+	// a scheduled assignment leaves the current period alone
+	quota, err := r.AssignPlanTx(ctx, companyID, proID, time.Now().AddDate(0, 1, 0), 1)
+	if err != nil || quota != nil {
+		t.Fatalf("scheduled assignment: quota=%v err=%v", quota, err)
+	}
+
+	// one in effect replaces the allowance right away
+	quota, err = r.AssignPlanTx(ctx, companyID, proID, time.Now().AddDate(0, 0, -1), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quota == nil || quota.UnlockQuotaTotal != 100 {
+		t.Fatalf("quota = %+v, want the pro allowance of 100", quota)
+	}
+
+	// the fixture's HR user takes one of starter's 3 seats
+	if _, err := r.AssignPlanTx(ctx, companyID, starterID, time.Now(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(ctx, `
+INSERT INTO hr_users (company_id, display_name, status)
+VALUES ($1, 'seat-2', 'active'), ($1, 'seat-3', 'active')`, companyID); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureSeatAvailable(ctx, r.Q, companyID); !errors.Is(err, domain.ErrSeatLimit) {
+		t.Fatalf("got %v, want ErrSeatLimit", err)
+	}
+}
+
+func TestSeatsFull(t *testing.T) {
+	tests := []struct {
+		limit  int32
+		active int64
+		want   bool
+	}{
+		{0, 0, false},
+		{0, 500, false},
+		{3, 0, false},
+		{3, 2, false},
+		{3, 3, true},
+		// over the limit after a downgrade: nobody can be added until some leave
+		{3, 5, true},
+		{1, 1, true},
+	}
+	for _, tt := range tests {
+		if got := seatsFull(tt.limit, tt.active); got != tt.want {
+			t.Errorf("seatsFull(%d, %d) = %v, want %v", tt.limit, tt.active, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+)
+
+// defaultQuotaPeriodDays is the length of the period that follows one with no length.
+const defaultQuotaPeriodDays = 30
+
+type QuotaRepo struct {
+	Q    *db.Queries
+	Pool *pgxpool.Pool
+}
+
+// RolloverDueTx starts a new period for up to limit companies whose period_end has been
+// reached, archiving the finished periods. Rows locked by concurrent unlocks are skipped;
+// those unlocks roll their company over themselves (lockCurrentQuota).
+func (r *QuotaRepo) RolloverDueTx(ctx context.Context, limit int32) ([]db.CompanyQuotaDetailRow, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	today := quotaToday()
+	due, err := q.LockDueCompanyQuotas(ctx, db.LockDueCompanyQuotasParams{Today: today, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.CompanyQuotaDetailRow, 0, len(due))
+	for _, quota := range due {
+		row, err := rolloverQuota(ctx, q, quota, today)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, tx.Commit(ctx)
+}
+
+// lockCurrentQuota locks the company's quota row like LockCompanyQuota, first rolling it into
+// a new period if period_end has passed and the rollover job has not caught up yet. The
+// rollover runs on q, so it commits or rolls back with the caller's transaction.
+func lockCurrentQuota(ctx context.Context, q *db.Queries, companyID int64) (db.CompanyQuotaRow, error) {
+	quota, err := q.LockCompanyQuota(ctx, companyID)
+	today := quotaToday()
+	if err != nil || !quota.PeriodEnd.Valid || quota.PeriodEnd.Time.After(today) {
+		return quota, err
+	}
+	row, err := rolloverQuota(ctx, q, quota, today)
+	if err != nil {
+		return quota, err
+	}
+	quota.UnlockQuotaTotal = row.UnlockQuotaTotal
+	quota.UnlockQuotaUsed = row.UnlockQuotaUsed
+	quota.PeriodStart = row.PeriodStart
+	quota.PeriodEnd = row.PeriodEnd
+	quota.CreditBalance = row.CreditBalance
+	return quota, nil
+}
+
+// rolloverQuota replaces the locked quota's finished period with the one containing today.
+func rolloverQuota(ctx context.Context, q *db.Queries, quota db.CompanyQuotaRow, today time.Time) (db.CompanyQuotaDetailRow, error) {
+	start := quota.PeriodEnd.Time
+	if quota.PeriodStart.Valid {
+		start = quota.PeriodStart.Time
+	}
+	next, end := nextQuotaPeriod(start, quota.PeriodEnd.Time, today)
+	return q.RolloverCompanyQuota(ctx, db.RolloverCompanyQuotaParams{
+		CompanyID:   quota.CompanyID,
+		PeriodStart: next,
+		PeriodEnd:   end,
+	})
+}
+
+// nextQuotaPeriod returns the period after [start, end) that contains today. It keeps the
+// finished period's length (defaultQuotaPeriodDays if it had none) and is aligned to its
+// boundaries, so idle periods in between are skipped rather than archived. A today before
+// end yields the period right after.
+func nextQuotaPeriod(start, end, today time.Time) (time.Time, time.Time) {
+	days := daysBetween(start, end)
+	if days <= 0 {
+		days = defaultQuotaPeriodDays
+	}
+	skipped := max(daysBetween(end, today), 0) / days
+	next := end.AddDate(0, 0, skipped*days)
+	return next, next.AddDate(0, 0, days)
+}
+
+// daysBetween counts the calendar days from a to b (negative if b is before a).
+func daysBetween(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// quotaToday is the current date as stored in period_start and period_end (UTC midnight).
+func quotaToday() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+)
+
+func TestUnlockContactTxRollsOverEndedPeriod(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, hrUserID, candidateIDs := seedUnlockFixture(t, pool, 1, 1)
+	// a 30-day period that ended 45 days ago with the quota used up
+	if _, err := pool.Exec(ctx, `
+UPDATE company_quotas
+SET period_start = CURRENT_DATE - 75, period_end = CURRENT_DATE - 45, unlock_quota_used = 1
+WHERE company_id = $1`, companyID); err != nil {
+		t.Fatal(err)
+	}
+	r := &CandidateRepo{Q: db.New(pool), Pool: pool}
+
+	if _, err := r.UnlockContactTx(ctx, companyID, hrUserID, candidateIDs[0]); err != nil {
+		t.Fatalf("unlock after period end: %v", err)
+	}
+	if used := quotaUsed(t, pool, companyID); used != 1 {
+		t.Fatalf("unlock_quota_used = %d, want 1 in the new period", used)
+	}
+
+	// the idle period in between is skipped: the new one contains today
+	var current bool
+	if err := pool.QueryRow(ctx, `
+SELECT period_start = CURRENT_DATE - 15 AND period_end = CURRENT_DATE + 15
+FROM company_quotas WHERE company_id = $1`, companyID).Scan(&current); err != nil {
+		t.Fatal(err)
+	}
+	if !current {
+		t.Fatal("new period does not start at the first boundary on or before today")
+	}
+
+	history, err := r.Q.ListCompanyQuotaPeriods(ctx, db.ListCompanyQuotaPeriodsParams{CompanyID: companyID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].UnlockQuotaUsed != 1 {
+		t.Fatalf("history = %+v, want the finished period with 1 unlock", history)
+	}
+
+	// nothing left for the job
+	due, err := r.Q.LockDueCompanyQuotas(ctx, db.LockDueCompanyQuotasParams{Today: quotaToday(), Limit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, quota := range due {
+		if quota.CompanyID == companyID {
+			t.Fatalf("quota %+v is still due after the rollover", quota)
+		}
+	}
+}
+
+func TestNextQuotaPeriod(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	tests := []struct {
+		name              string
+		start, end, today string
+		wantStart         string
+		wantEnd           string
+	}{
+		{"ends today", "2026-01-01", "2026-01-31", "2026-01-31", "2026-01-31", "2026-03-02"},
+		{"ended yesterday", "2026-01-01", "2026-01-31", "2026-02-01", "2026-01-31", "2026-03-02"},
+		{"idle periods are skipped", "2026-01-01", "2026-01-31", "2026-03-17", "2026-03-02", "2026-04-01"},
+		{"today on a later boundary", "2026-01-01", "2026-01-31", "2026-04-01", "2026-04-01", "2026-05-01"},
+		{"keeps a 31-day length", "2026-01-01", "2026-02-01", "2026-02-10", "2026-02-01", "2026-03-04"},
+		{"weekly periods", "2026-03-02", "2026-03-09", "2026-03-29", "2026-03-23", "2026-03-30"},
+		{"empty period lasts 30 days", "2026-01-31", "2026-01-31", "2026-02-05", "2026-01-31", "2026-03-02"},
+		{"inverted period lasts 30 days", "2026-02-10", "2026-01-31", "2026-01-31", "2026-01-31", "2026-03-02"},
+		{"today before the end", "2026-01-01", "2026-01-31", "2026-01-20", "2026-01-31", "2026-03-02"},
+		{"across a leap day", "2028-02-01", "2028-03-01", "2028-03-30", "2028-03-30", "2028-04-28"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := nextQuotaPeriod(day(tt.start), day(tt.end), day(tt.today))
+			if got, want := start.Format(time.DateOnly), tt.wantStart; got != want {
+				t.Errorf("start = %s, want %s", got, want)
+			}
+			if got, want := end.Format(time.DateOnly), tt.wantEnd; got != want {
+				t.Errorf("end = %s, want %s", got, want)
+			}
+		})
+	}
+}
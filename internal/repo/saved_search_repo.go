@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+type SavedSearchRepo struct {
+	Q    *db.Queries
+	Pool *pgxpool.Pool
+}
+
+// CreateTx saves a search unless the HR user already has p.MaxPerUser of them
+// (domain.ErrSavedSearchLimit). The user's row is locked first so concurrent creates
+// count one after another.
+func (r *SavedSearchRepo) CreateTx(ctx context.Context, p db.CreateSavedSearchParams) (db.SavedSearchRow, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return db.SavedSearchRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.Q.WithTx(tx)
+	if err := q.LockHRUserForUpdate(ctx, p.HrUserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.SavedSearchRow{}, domain.ErrNotFound
+		}
+		return db.SavedSearchRow{}, err
+	}
+	row, err := q.CreateSavedSearch(ctx, p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.SavedSearchRow{}, domain.ErrSavedSearchLimit
+		}
+		return db.SavedSearchRow{}, err
+	}
+	return row, tx.Commit(ctx)
+}
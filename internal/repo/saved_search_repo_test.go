@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+)
+
+func TestSavedSearchCreateTxConcurrentKeepsLimit(t *testing.T) {
+	pool := dbtest.Pool(t)
+	const limit, workers = 3, 12
+	companyID, hrUserID := dbtest.SeedCompany(t, pool, 0)
+	r := &SavedSearchRepo{Q: db.New(pool), Pool: pool}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		ok      int
+		limited int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := r.CreateTx(context.Background(), db.CreateSavedSearchParams{
+				CompanyID:       companyID,
+				HrUserID:        hrUserID,
+				Name:            fmt.Sprintf("search %d", i),
+				Filters:         []byte(`{}`),
+				Frequency:       domain.SavedSearchDaily,
+				IntervalSeconds: 86400,
+				MaxPerUser:      limit,
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				ok++
+			case errors.Is(err, domain.ErrSavedSearchLimit):
+				limited++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if ok != limit || limited != workers-limit {
+		t.Fatalf("got %d created / %d limited, want %d / %d", ok, limited, limit, workers-limit)
+	}
+	var n int
+	if err := pool.QueryRow(context.Background(), `SELECT count(*) FROM saved_searches WHERE hr_user_id = $1`, hrUserID).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != limit {
+		t.Fatalf("saved_searches rows = %d, want %d", n, limit)
+	}
+}
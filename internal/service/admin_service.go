@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+	"tg-hr-platform/internal/util"
+)
+
+// AdminService backs the platform operator console. Unlike the /api services it is
+// deliberately not scoped to a single company.
+type AdminService struct {
+	Q *db.Queries
+	// CompanyStatus, when set, is invalidated on status changes so they apply immediately.
+	CompanyStatus *CompanyStatusService
+	// HRUsers, when set, is invalidated on HR user changes and backs RevokeHRUserSessions.
+	HRUsers *HRUserStateService
+	Credits *repo.CreditRepo
+	// Members serializes status changes with the company's other membership changes.
+	Members *repo.HRUserRepo
+}
+
+func (s *AdminService) ListCompanies(ctx context.Context, status, q *string, limit, offset int32) ([]domain.AdminCompany, error) {
+	rows, err := s.Q.AdminListCompanies(ctx, db.AdminListCompaniesParams{
+		Status: status,
+		Q:      q,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.AdminCompany, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, domain.AdminCompany{
+			ID:               r.ID,
+			Name:             r.Name,
+			Status:           r.Status,
+			MemberCount:      r.MemberCount,
+			QuotaConfigured:  r.QuotaConfigured,
+			UnlockQuotaTotal: r.UnlockQuotaTotal,
+			UnlockQuotaUsed:  r.UnlockQuotaUsed,
+			PlanCode:         util.TextOrEmpty(r.PlanCode),
+			CreatedAt:        r.CreatedAt.Time,
+		})
+	}
+	return out, nil
+}
+
+// SetCompanyStatus returns the previous status.
+func (s *AdminService) SetCompanyStatus(ctx context.Context, companyID int64, status string) (string, error) {
+	company, err := s.Q.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", err
+	}
+	if _, err := s.Q.UpdateCompanyStatus(ctx, db.UpdateCompanyStatusParams{ID: companyID, Status: status}); err != nil {
+		return "", err
+	}
+	if s.CompanyStatus != nil {
+		s.CompanyStatus.Invalidate(ctx, companyID)
+	}
+	return company.Status, nil
+}
+
+func (s *AdminService) ListHRUsers(ctx context.Context, status *string, companyID *int64, limit, offset int32) ([]domain.AdminHRUser, error) {
+	rows, err := s.Q.AdminListHRUsers(ctx, db.AdminListHRUsersParams{
+		Status:    status,
+		CompanyID: companyID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.AdminHRUser, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, domain.AdminHRUser{
+			ID:          r.ID,
+			CompanyID:   r.CompanyID,
+			CompanyName: r.CompanyName,
+			TgUsername:  util.TextOrEmpty(r.TgUsername),
+			DisplayName: util.TextOrEmpty(r.DisplayName),
+			Role:        r.Role,
+			Status:      r.Status,
+			CreatedAt:   r.CreatedAt.Time,
+		})
+	}
+	return out, nil
+}
+
+// SetHRUserStatus approves or blocks any HR user. Approving needs a free seat on the
+// company's plan (domain.ErrSeatLimit), checked under the same lock as MemberService.SetStatus.
+// Returns the user's company and previous status.
+func (s *AdminService) SetHRUserStatus(ctx context.Context, hrUserID int64, status string) (companyID int64, prev string, err error) {
+	u, err := s.Q.GetHRUserByID(ctx, hrUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, "", domain.ErrNotFound
+		}
+		return 0, "", err
+	}
+	err = s.Members.WithLockedMember(ctx, u.CompanyID, hrUserID, func(ctx context.Context, q *db.Queries, m db.LockCompanyMemberRow) error {
+		prev = m.Status
+		if m.Status == domain.HRStatusRemoved {
+			return domain.ErrNotFound
+		}
+		if m.Status == status {
+			return nil
+		}
+		if status == domain.HRStatusActive {
+			if err := repo.EnsureSeatAvailable(ctx, q, u.CompanyID); err != nil {
+				return err
+			}
+		}
+		return q.UpdateHRUserStatus(ctx, db.UpdateHRUserStatusParams{ID: hrUserID, Status: status})
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	s.HRUsers.Invalidate(ctx, hrUserID)
+	return u.CompanyID, prev, nil
+}
+
+// RevokeHRUserSessions signs an HR user out everywhere. Returns the user's company.
+func (s *AdminService) RevokeHRUserSessions(ctx context.Context, hrUserID int64) (int64, error) {
+	u, err := s.Q.GetHRUserByID(ctx, hrUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, err
+	}
+	if u.Status == domain.HRStatusRemoved {
+		return 0, domain.ErrNotFound
+	}
+	if _, err := s.Q.BumpHRUserSessionVersion(ctx, hrUserID); err != nil {
+		return 0, err
+	}
+	s.HRUsers.Invalidate(ctx, hrUserID)
+	return u.CompanyID, nil
+}
+
+// AdjustQuota creates or updates a company's quota row; nil fields are left unchanged.
+// Credits follow the change to (total - used).
+func (s *AdminService) AdjustQuota(ctx context.Context, companyID int64, total, used *int32, periodEnd pgtype.Date) (db.CompanyQuotaDetailRow, error) {
+	if _, err := s.Q.GetCompanyByID(ctx, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.CompanyQuotaDetailRow{}, domain.ErrNotFound
+		}
+		return db.CompanyQuotaDetailRow{}, err
+	}
+	return s.Credits.AdjustQuotaTx(ctx, companyID, total, used, periodEnd)
+}
+
+// CompanyExists is used before starting a read-only impersonation session.
+func (s *AdminService) CompanyExists(ctx context.Context, companyID int64) error {
+	if _, err := s.Q.GetCompanyByID(ctx, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -43,6 +44,74 @@ func (s *AuditLogService) LogHR(c *gin.Context, hrUserID int64, action, targetTy
 	}()
 }
 
+// LogAdmin logs a platform operator action against a company. hr_user_id is left 0 and the
+// operator's Telegram ID is recorded in meta.admin_tg_user_id.
+func (s *AuditLogService) LogAdmin(adminTgUserID, companyID int64, action, targetType, targetID string, meta map[string]any) {
+	m := map[string]any{"admin_tg_user_id": adminTgUserID}
+	for k, v := range meta {
+		m[k] = v
+	}
+	metaJSON, err := json.Marshal(m)
+	if err != nil {
+		metaJSON = []byte("{}")
+	}
+
+	go func() {
+		_ = s.Q.InsertAuditLog(context.Background(), db.InsertAuditLogParams{
+			CompanyID:  companyID,
+			Action:     action,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Meta:       metaJSON,
+		})
+	}()
+}
+
+// LogCompany logs something that happened to a company outside a request, e.g. a payment
+// confirmed by its provider. hrUserID is the HR user it is attributed to, or 0.
+func (s *AuditLogService) LogCompany(companyID, hrUserID int64, action, targetType, targetID string, meta map[string]any) {
+	metaJSON := []byte("{}")
+	if meta != nil {
+		if b, err := json.Marshal(meta); err == nil {
+			metaJSON = b
+		}
+	}
+
+	go func() {
+		_ = s.Q.InsertAuditLog(context.Background(), db.InsertAuditLogParams{
+			CompanyID:  companyID,
+			HrUserID:   hrUserID,
+			Action:     action,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Meta:       metaJSON,
+		})
+	}()
+}
+
+// LogCandidate logs an action a candidate took on their own profile through the bot.
+// It is not scoped to a company (company_id and hr_user_id are 0); the candidate's
+// Telegram ID is recorded in meta.tg_user_id.
+func (s *AuditLogService) LogCandidate(tgUserID int64, action string, candidateID int64, meta map[string]any) {
+	m := map[string]any{"tg_user_id": tgUserID}
+	for k, v := range meta {
+		m[k] = v
+	}
+	metaJSON, err := json.Marshal(m)
+	if err != nil {
+		metaJSON = []byte("{}")
+	}
+
+	go func() {
+		_ = s.Q.InsertAuditLog(context.Background(), db.InsertAuditLogParams{
+			Action:     action,
+			TargetType: "candidate",
+			TargetID:   strconv.FormatInt(candidateID, 10),
+			Meta:       metaJSON,
+		})
+	}()
+}
+
 // GetAuditLogs retrieves audit logs for a company
 func (s *AuditLogService) GetAuditLogs(ctx context.Context, companyID int64, limit, offset int32) ([]db.GetAuditLogsRow, error) {
 	return s.Q.GetAuditLogs(ctx, db.GetAuditLogsParams{
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/telegram"
+)
+
+const (
+	// BotOutboxMaxAttempts is how often a message is tried before it is marked failed.
+	BotOutboxMaxAttempts = 8
+	// botOutboxLease is how long a claimed message stays invisible to other workers.
+	botOutboxLease     = 2 * time.Minute
+	botOutboxRetryBase = 30 * time.Second
+	botOutboxRetryMax  = time.Hour
+)
+
+// BotOutboxWorker delivers bot_outbox messages. Messages are queued in the transaction
+// of the event they describe (see repo.UnlockContactTx), so the request path never waits
+// on Telegram; failures are retried with exponential backoff.
+type BotOutboxWorker struct {
+	Q      *db.Queries
+	Client *telegram.Client
+	// Interval between polls of the outbox (default 5s)
+	Interval  time.Duration
+	BatchSize int32
+}
+
+// Run delivers due messages until ctx is cancelled.
+func (w *BotOutboxWorker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Flush(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  Bot outbox: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush claims one batch of due messages and tries to send each once.
+func (w *BotOutboxWorker) Flush(ctx context.Context) error {
+	batch := w.BatchSize
+	if batch <= 0 {
+		batch = 20
+	}
+	rows, err := w.Q.ClaimDueBotOutbox(ctx, db.ClaimDueBotOutboxParams{
+		Limit:        batch,
+		LeaseSeconds: int32(botOutboxLease / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		if ctx.Err() != nil {
+			// unsent messages become due again when their lease expires
+			return nil
+		}
+		w.deliver(ctx, r)
+	}
+	return nil
+}
+
+func (w *BotOutboxWorker) deliver(ctx context.Context, r db.BotOutboxRow) {
+	// bookkeeping must not be lost to shutdown once Telegram has accepted the message
+	bg := context.WithoutCancel(ctx)
+
+	params, err := renderBotOutboxMessage(r)
+	if err != nil {
+		w.fail(bg, r, err)
+		return
+	}
+	if _, err := w.Client.SendMessage(ctx, params); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		if permanentSendError(err) || r.Attempts >= BotOutboxMaxAttempts {
+			w.fail(bg, r, err)
+			return
+		}
+		delay := botOutboxRetryDelay(r.Attempts)
+		log.Printf("⚠️  Bot outbox %d (%s) attempt %d failed, retrying in %s: %v", r.ID, r.Kind, r.Attempts, delay, err)
+		if err := w.Q.RetryBotOutbox(bg, db.RetryBotOutboxParams{
+			ID:           r.ID,
+			DelaySeconds: int32(delay / time.Second),
+			LastError:    err.Error(),
+		}); err != nil {
+			log.Printf("⚠️  Bot outbox %d: %v", r.ID, err)
+		}
+		return
+	}
+	if err := w.Q.MarkBotOutboxSent(bg, r.ID); err != nil {
+		log.Printf("⚠️  Bot outbox %d sent but not marked: %v", r.ID, err)
+	}
+}
+
+func (w *BotOutboxWorker) fail(ctx context.Context, r db.BotOutboxRow, err error) {
+	log.Printf("❌ Bot outbox %d (%s) failed after %d attempt(s): %v", r.ID, r.Kind, r.Attempts, err)
+	if err := w.Q.FailBotOutbox(ctx, db.FailBotOutboxParams{ID: r.ID, LastError: err.Error()}); err != nil {
+		log.Printf("⚠️  Bot outbox %d: %v", r.ID, err)
+	}
+}
+
+// botOutboxRetryDelay doubles from 30s per attempt, capped at 1h
+func botOutboxRetryDelay(attempts int32) time.Duration {
+	d := botOutboxRetryBase
+	for i := int32(1); i < attempts && d < botOutboxRetryMax; i++ {
+		d *= 2
+	}
+	return min(d, botOutboxRetryMax)
+}
+
+// permanentSendError: the user blocked the bot or the chat is gone; retrying won't help.
+func permanentSendError(err error) bool {
+	var apiErr *telegram.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == http.StatusForbidden || apiErr.Code == http.StatusBadRequest
+}
+
+func renderBotOutboxMessage(r db.BotOutboxRow) (telegram.SendMessageParams, error) {
+	switch r.Kind {
+	case domain.BotOutboxCandidateUnlocked:
+		var n domain.UnlockNotification
+		if err := json.Unmarshal(r.Payload, &n); err != nil {
+			return telegram.SendMessageParams{}, fmt.Errorf("invalid payload: %w", err)
+		}
+		role := n.DesiredRole
+		if role == "" {
+			role = "（未填写）"
+		}
+		at := r.CreatedAt.In(timezoneLocation(n.Timezone)).Format("2006-01-02 15:04 MST")
+		return telegram.SendMessageParams{
+			ChatID: r.ChatID,
+			Text:   fmt.Sprintf("🔔 有企业解锁了你的联系方式\n\n企业：%s\n职位：%s\n时间：%s", n.CompanyName, role, at),
+			ReplyMarkup: InlineKeyboard([][]BotButton{{
+				profileButton("📄 查看档案", ProfileActionView),
+				profileButton("🔕 关闭解锁通知", ProfileActionNotifyOff),
+			}}),
+		}, nil
+	case domain.BotOutboxContactRequest:
+		var n domain.ContactRequestNotification
+		if err := json.Unmarshal(r.Payload, &n); err != nil {
+			return telegram.SendMessageParams{}, fmt.Errorf("invalid payload: %w", err)
+		}
+		text := fmt.Sprintf("📨 企业「%s」申请查看你的联系方式", n.CompanyName)
+		if n.DesiredRole != "" {
+			text += fmt.Sprintf("（职位：%s）", n.DesiredRole)
+		}
+		text += fmt.Sprintf("\n\n同意后对方可以看到你的联系方式。请在 %d 天内答复。", int(domain.ContactRequestTTL.Hours()/24))
+		return telegram.SendMessageParams{ChatID: r.ChatID, Text: text, ReplyMarkup: InlineKeyboard(contactRequestButtons(n.RequestID))}, nil
+	case domain.BotOutboxSavedSearchDigest:
+		var d domain.SavedSearchDigest
+		if err := json.Unmarshal(r.Payload, &d); err != nil {
+			return telegram.SendMessageParams{}, fmt.Errorf("invalid payload: %w", err)
+		}
+		text, buttons := renderSavedSearchDigest(d)
+		return telegram.SendMessageParams{ChatID: r.ChatID, Text: text, ReplyMarkup: InlineKeyboard(buttons)}, nil
+	}
+	return telegram.SendMessageParams{}, fmt.Errorf("unknown kind %q", r.Kind)
+}
+
+// InlineKeyboard renders rows of buttons as a Telegram inline keyboard, for bot replies
+// and queued messages alike.
+func InlineKeyboard(buttons [][]BotButton) telegram.InlineKeyboardMarkup {
+	rows := make([][]telegram.InlineKeyboardButton, 0, len(buttons))
+	for _, row := range buttons {
+		r := make([]telegram.InlineKeyboardButton, 0, len(row))
+		for _, b := range row {
+			r = append(r, telegram.InlineKeyboardButton{Text: b.Text, CallbackData: b.Data, URL: b.URL})
+		}
+		rows = append(rows, r)
+	}
+	return telegram.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// timezoneLocation resolves a candidate timezone as stored by onboarding ("UTC+8",
+// "UTC+05:30" or an IANA name); anything else falls back to UTC.
+func timezoneLocation(tz string) *time.Location {
+	if m := utcOffsetRe.FindStringSubmatch(tz); m != nil {
+		if m[2] == "" {
+			return time.UTC
+		}
+		hours, _ := strconv.Atoi(m[3])
+		minutes := 0
+		if m[4] != "" {
+			minutes, _ = strconv.Atoi(strings.TrimPrefix(m[4], ":"))
+		}
+		offset := hours*3600 + minutes*60
+		if strings.HasPrefix(m[2], "-") {
+			offset = -offset
+		}
+		return time.FixedZone("UTC"+m[2], offset)
+	}
+	if tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
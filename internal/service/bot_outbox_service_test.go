@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/telegram"
+)
+
+func TestBotOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{1000, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := botOutboxRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("botOutboxRetryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestPermanentSendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"bot blocked", &telegram.APIError{Method: "sendMessage", Code: http.StatusForbidden}, true},
+		{"chat not found", &telegram.APIError{Method: "sendMessage", Code: http.StatusBadRequest}, true},
+		{"wrapped", fmt.Errorf("send: %w", &telegram.APIError{Code: http.StatusForbidden}), true},
+		{"rate limited", &telegram.APIError{Code: http.StatusTooManyRequests, RetryAfter: 5}, false},
+		{"server error", &telegram.APIError{Code: http.StatusBadGateway}, false},
+		{"network", errors.New("connection reset by peer"), false},
+	}
+	for _, tt := range tests {
+		if got := permanentSendError(tt.err); got != tt.want {
+			t.Errorf("%s: permanentSendError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenderBotOutboxMessage(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		kind     string
+		payload  string
+		wantText []string
+		wantData []string
+		wantURL  string
+		wantErr  bool
+	}{
+		{
+			name:     "unlock in the candidate's timezone",
+			kind:     domain.BotOutboxCandidateUnlocked,
+			payload:  `{"candidate_id":1,"company_name":"Acme","desired_role":"Go 开发","timezone":"UTC+8"}`,
+			wantText: []string{"企业：Acme", "职位：Go 开发", "2024-03-01 10:30 UTC+8"},
+			wantData: []string{ProfileCallbackRoute + ":" + ProfileActionView, ProfileCallbackRoute + ":" + ProfileActionNotifyOff},
+		},
+		{
+			name:     "unlock without role or timezone",
+			kind:     domain.BotOutboxCandidateUnlocked,
+			payload:  `{"candidate_id":1,"company_name":"Acme"}`,
+			wantText: []string{"职位：（未填写）", "2024-03-01 02:30 UTC"},
+		},
+		{
+			name:     "contact request",
+			kind:     domain.BotOutboxContactRequest,
+			payload:  `{"request_id":42,"company_name":"Acme","desired_role":"设计师"}`,
+			wantText: []string{"企业「Acme」申请查看你的联系方式", "（职位：设计师）", "请在 7 天内答复"},
+			wantData: []string{contactRequestButtons(42)[0][0].Data, contactRequestButtons(42)[0][1].Data},
+		},
+		{
+			name:     "saved search digest",
+			kind:     domain.BotOutboxSavedSearchDigest,
+			payload:  `{"search_id":5,"name":"Go 后端","candidates":[{"slug":"a","display_name":"张三","desired_role":"Go 开发"}],"more":true,"url":"https://example.com/search"}`,
+			wantText: []string{"保存的搜索「Go 后端」", "• 张三 · Go 开发", "还有更多匹配"},
+			wantData: []string{savedSearchButton("", SavedSearchActionMute, 5).Data},
+			wantURL:  "https://example.com/search",
+		},
+		{name: "invalid payload", kind: domain.BotOutboxContactRequest, payload: `{"request_id":"x"}`, wantErr: true},
+		{name: "unknown kind", kind: "newsletter", payload: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		p, err := renderBotOutboxMessage(db.BotOutboxRow{ID: 1, ChatID: 1001, Kind: tt.kind, Payload: []byte(tt.payload), CreatedAt: createdAt})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: got no error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if p.ChatID != 1001 {
+			t.Errorf("%s: chat %d, want 1001", tt.name, p.ChatID)
+		}
+		for _, s := range tt.wantText {
+			if !strings.Contains(p.Text, s) {
+				t.Errorf("%s: text %q does not contain %q", tt.name, p.Text, s)
+			}
+		}
+		markup, ok := p.ReplyMarkup.(telegram.InlineKeyboardMarkup)
+		if !ok {
+			t.Fatalf("%s: reply markup %T, want an inline keyboard", tt.name, p.ReplyMarkup)
+		}
+		var data []string
+		var url string
+		for _, row := range markup.InlineKeyboard {
+			for _, b := range row {
+				if b.URL != "" {
+					url = b.URL
+				} else {
+					data = append(data, b.CallbackData)
+				}
+			}
+		}
+		if tt.wantData != nil && !slices.Equal(data, tt.wantData) {
+			t.Errorf("%s: button data %q, want %q", tt.name, data, tt.wantData)
+		}
+		if url != tt.wantURL {
+			t.Errorf("%s: button URL %q, want %q", tt.name, url, tt.wantURL)
+		}
+	}
+}
+
+func TestInlineKeyboard(t *testing.T) {
+	if got := InlineKeyboard(nil); got.InlineKeyboard == nil {
+		t.Error("no buttons must still render an empty keyboard, not null")
+	}
+	got := InlineKeyboard([][]BotButton{
+		{{Text: "a", Data: "x:1"}, {Text: "b", URL: "https://example.com"}},
+		{{Text: "c", Data: "x:2"}},
+	})
+	want := [][]telegram.InlineKeyboardButton{
+		{{Text: "a", CallbackData: "x:1"}, {Text: "b", URL: "https://example.com"}},
+		{{Text: "c", CallbackData: "x:2"}},
+	}
+	if len(got.InlineKeyboard) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got.InlineKeyboard), len(want))
+	}
+	for i := range want {
+		if !slices.Equal(got.InlineKeyboard[i], want[i]) {
+			t.Errorf("row %d = %+v, want %+v", i, got.InlineKeyboard[i], want[i])
+		}
+	}
+}
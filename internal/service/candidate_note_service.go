@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+)
+
+// CandidateNoteService manages a company's private notes and tags on candidates. Every
+// query is scoped by company ID, so no other company can read or change them.
+type CandidateNoteService struct {
+	Q    *db.Queries
+	Repo *repo.CandidateRepo
+}
+
+func (s *CandidateNoteService) ListNotes(ctx context.Context, companyID int64, slug string) ([]domain.CandidateNote, error) {
+	candidateID, err := s.Repo.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.ListNotes(ctx, companyID, candidateID)
+}
+
+func (s *CandidateNoteService) AddNote(ctx context.Context, companyID, hrUserID int64, slug, body string) (*domain.CandidateNote, error) {
+	candidateID, err := s.Repo.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.Repo.AddNote(ctx, db.CreateCandidateNoteParams{
+		CompanyID:   companyID,
+		CandidateID: candidateID,
+		HrUserID:    hrUserID,
+		Body:        body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// UpdateNote edits a note; only its author may (domain.ErrForbidden otherwise).
+func (s *CandidateNoteService) UpdateNote(ctx context.Context, companyID, hrUserID int64, slug string, noteID int64, body string) (*domain.CandidateNote, error) {
+	if err := s.checkAuthor(ctx, companyID, hrUserID, slug, noteID); err != nil {
+		return nil, err
+	}
+	n, err := s.Repo.UpdateNote(ctx, db.UpdateCandidateNoteParams{ID: noteID, HrUserID: hrUserID, Body: body})
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// DeleteNote deletes a note; only its author may (domain.ErrForbidden otherwise).
+func (s *CandidateNoteService) DeleteNote(ctx context.Context, companyID, hrUserID int64, slug string, noteID int64) error {
+	if err := s.checkAuthor(ctx, companyID, hrUserID, slug, noteID); err != nil {
+		return err
+	}
+	n, err := s.Q.DeleteCandidateNote(ctx, db.DeleteCandidateNoteParams{ID: noteID, HrUserID: hrUserID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *CandidateNoteService) checkAuthor(ctx context.Context, companyID, hrUserID int64, slug string, noteID int64) error {
+	candidateID, err := s.Repo.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+	n, err := s.Repo.GetNote(ctx, db.GetCandidateNoteParams{ID: noteID, CompanyID: companyID, CandidateID: candidateID})
+	if err != nil {
+		return err
+	}
+	if n.AuthorID != hrUserID {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// AddTag tags the candidate and returns their tags. Adding a tag the candidate already has
+// (in any letter case) changes nothing.
+func (s *CandidateNoteService) AddTag(ctx context.Context, companyID, hrUserID int64, slug, tag string) ([]string, error) {
+	candidateID, err := s.Repo.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tags(ctx, companyID, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	if hasTag(tags, tag) {
+		return tags, nil
+	}
+	n, err := s.Q.AddCandidateTag(ctx, db.AddCandidateTagParams{
+		CompanyID:       companyID,
+		CandidateID:     candidateID,
+		Tag:             tag,
+		CreatedBy:       hrUserID,
+		MaxPerCandidate: domain.MaxCandidateTags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tags, err = s.tags(ctx, companyID, candidateID); err != nil || n > 0 {
+		return tags, err
+	}
+	// nothing was added: another request added the same tag meanwhile, or the limit is reached
+	if hasTag(tags, tag) {
+		return tags, nil
+	}
+	return nil, domain.ErrCandidateTagLimit
+}
+
+func hasTag(tags []string, tag string) bool {
+	return slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// RemoveTag untags the candidate (case-insensitive) and returns their remaining tags.
+func (s *CandidateNoteService) RemoveTag(ctx context.Context, companyID int64, slug, tag string) ([]string, error) {
+	candidateID, err := s.Repo.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.Q.RemoveCandidateTag(ctx, db.RemoveCandidateTagParams{CompanyID: companyID, CandidateID: candidateID, Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return s.tags(ctx, companyID, candidateID)
+}
+
+// CompanyTags lists the tags the company uses, most used first (for the tag filter).
+func (s *CandidateNoteService) CompanyTags(ctx context.Context, companyID int64) ([]domain.CompanyTag, error) {
+	rows, err := s.Q.ListCompanyTags(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.CompanyTag, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, domain.CompanyTag{Tag: r.Tag, Count: r.Count})
+	}
+	return out, nil
+}
+
+func (s *CandidateNoteService) tags(ctx context.Context, companyID, candidateID int64) ([]string, error) {
+	rows, err := s.Repo.ListTagsByIDs(ctx, companyID, []int64{candidateID})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, r.Tag)
+	}
+	return out, nil
+}
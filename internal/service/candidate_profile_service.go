@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/cache"
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+	"tg-hr-platform/internal/util"
+)
+
+// DeleteConfirmCommand must be sent to actually delete a profile after /delete.
+const DeleteConfirmCommand = "/delete confirm"
+
+// ProfileCallbackRoute prefixes the callback_data of profile buttons ("profile:<action>");
+// the bot handler routes the presses back to this service.
+const ProfileCallbackRoute = "profile"
+
+const (
+	ProfileActionView          = "view"
+	ProfileActionEdit          = "edit"
+	ProfileActionHide          = "hide"
+	ProfileActionShow          = "show"
+	ProfileActionDelete        = "delete"
+	ProfileActionDeleteConfirm = "delete_confirm"
+	ProfileActionNotifyOn      = "notify_on"
+	ProfileActionNotifyOff     = "notify_off"
+	ProfileActionModeOpen      = "mode_open"
+	ProfileActionModeRequest   = "mode_request"
+)
+
+func profileButton(text, action string) BotButton {
+	return BotButton{Text: text, Data: ProfileCallbackRoute + ":" + action}
+}
+
+// CandidateProfileService lets candidates view their profile, toggle visibility and delete
+// it through the bot. Everything is keyed by the caller's Telegram user ID.
+type CandidateProfileService struct {
+	Q     *db.Queries
+	Repo  *repo.CandidateRepo
+	Cache *cache.CandidateCache
+	Audit *AuditLogService
+}
+
+// Profile renders the caller's profile (/profile).
+func (s *CandidateProfileService) Profile(ctx context.Context, u BotUser) (BotReply, error) {
+	p, err := loadCandidateProfile(ctx, s.Q, u.ID)
+	if err != nil {
+		return noProfileReply(err)
+	}
+
+	status := "✅ 公开（HR 可搜索到）"
+	if p.Status == domain.CandidateStatusHidden {
+		status = "🙈 已隐藏（HR 无法搜索到）"
+	}
+	notify := "关闭"
+	if p.NotifyOnUnlock {
+		notify = "开启"
+	}
+	mode := "企业解锁即可见"
+	if p.ContactMode == domain.ContactModeRequest {
+		mode = "需我逐个同意"
+	}
+	contact := "（未设置）"
+	if p.TgUsername != "" {
+		contact = "@" + p.TgUsername
+	}
+	text := fmt.Sprintf(
+		"📄 你的候选人档案 %s\n\n姓名：%s\n职位：%s\n英语：%s\n期望月薪：%d-%d CNY\n到岗：%d 天\n时区：%s\n技能：%s\n联系方式：%s\n状态：%s\n联系方式公开：%s\n解锁通知：%s",
+		p.Slug, p.DisplayName, p.DesiredRole, p.EnglishLevel, p.SalaryMin, p.SalaryMax,
+		p.AvailabilityDays, p.Timezone, strings.Join(p.Skills, ", "), contact, status, mode, notify,
+	)
+	visibility := profileButton("🙈 隐藏", ProfileActionHide)
+	if p.Status == domain.CandidateStatusHidden {
+		visibility = profileButton("👀 公开", ProfileActionShow)
+	}
+	notifyToggle := profileButton("🔔 开启解锁通知", ProfileActionNotifyOn)
+	if p.NotifyOnUnlock {
+		notifyToggle = profileButton("🔕 关闭解锁通知", ProfileActionNotifyOff)
+	}
+	modeToggle := profileButton("🔐 改为需我同意才公开", ProfileActionModeRequest)
+	if p.ContactMode == domain.ContactModeRequest {
+		modeToggle = profileButton("🔓 改为解锁即公开", ProfileActionModeOpen)
+	}
+	return BotReply{Text: text, Buttons: [][]BotButton{
+		{profileButton("✏️ 修改", ProfileActionEdit), visibility, profileButton("🗑 删除", ProfileActionDelete)},
+		{notifyToggle},
+		{modeToggle},
+	}}, nil
+}
+
+// SetNotifyOnUnlock opts the caller in to (or out of) a bot message whenever a company
+// unlocks their contact, then shows the updated profile.
+func (s *CandidateProfileService) SetNotifyOnUnlock(ctx context.Context, u BotUser, notify bool) (BotReply, error) {
+	p, err := loadCandidateProfile(ctx, s.Q, u.ID)
+	if err != nil {
+		return noProfileReply(err)
+	}
+	if p.NotifyOnUnlock != notify {
+		if err := s.Q.SetCandidateNotifyOnUnlock(ctx, db.SetCandidateNotifyOnUnlockParams{ID: p.ID, Notify: notify}); err != nil {
+			return BotReply{}, err
+		}
+	}
+	return s.Profile(ctx, u)
+}
+
+// SetHidden hides the profile from HR search (/hide) or publishes it again (/show).
+func (s *CandidateProfileService) SetHidden(ctx context.Context, u BotUser, hidden bool) (BotReply, error) {
+	p, err := loadCandidateProfile(ctx, s.Q, u.ID)
+	if err != nil {
+		return noProfileReply(err)
+	}
+
+	status, text := domain.CandidateStatusActive, "✅ 档案已公开，HR 可以搜索到你。"
+	if hidden {
+		status, text = domain.CandidateStatusHidden, "🙈 档案已隐藏，HR 将无法搜索或解锁你。发送 /show 重新公开。"
+	}
+	if p.Status != status {
+		if err := s.Q.UpdateCandidateStatusByID(ctx, db.UpdateCandidateStatusByIDParams{ID: p.ID, Status: status}); err != nil {
+			return BotReply{}, err
+		}
+	}
+	return BotReply{Text: text, Buttons: [][]BotButton{{profileButton("📄 查看档案", ProfileActionView)}}}, nil
+}
+
+// SetContactMode switches between revealing the contact on unlock (domain.ContactModeOpen)
+// and approving each company first (domain.ContactModeRequest), then shows the profile.
+func (s *CandidateProfileService) SetContactMode(ctx context.Context, u BotUser, mode string) (BotReply, error) {
+	p, err := loadCandidateProfile(ctx, s.Q, u.ID)
+	if err != nil {
+		return noProfileReply(err)
+	}
+	if p.ContactMode != mode {
+		if err := s.Q.SetCandidateContactMode(ctx, db.SetCandidateContactModeParams{ID: p.ID, ContactMode: mode}); err != nil {
+			return BotReply{}, err
+		}
+	}
+	return s.Profile(ctx, u)
+}
+
+// ConfirmDelete asks the caller to confirm /delete.
+func (s *CandidateProfileService) ConfirmDelete(ctx context.Context, u BotUser) (BotReply, error) {
+	if _, err := loadCandidateProfile(ctx, s.Q, u.ID); err != nil {
+		return noProfileReply(err)
+	}
+	return BotReply{
+		Text: "⚠️ 删除后档案和联系方式将被永久清除，无法恢复。\n\n确认删除请点击下方按钮。",
+		Buttons: [][]BotButton{{
+			profileButton("🗑 确认删除", ProfileActionDeleteConfirm),
+			profileButton("取消", ProfileActionView),
+		}},
+	}, nil
+}
+
+// Delete purges the caller's contacts and marks the candidate deleted (audited).
+func (s *CandidateProfileService) Delete(ctx context.Context, u BotUser) (BotReply, error) {
+	p, err := loadCandidateProfile(ctx, s.Q, u.ID)
+	if err != nil {
+		return noProfileReply(err)
+	}
+	if err := s.Repo.DeleteCandidate(ctx, p.ID); err != nil {
+		return BotReply{}, err
+	}
+	if s.Cache != nil {
+		_ = s.Cache.InvalidateSkills(ctx, p.ID)
+	}
+	if s.Audit != nil {
+		s.Audit.LogCandidate(u.ID, "candidate.delete", p.ID, map[string]any{"slug": p.Slug})
+	}
+	return BotReply{Text: "🗑 档案已删除。如需重新求职，发送 /apply 即可。", RemoveKeyboard: true}, nil
+}
+
+func noProfileReply(err error) (BotReply, error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		return BotReply{Text: "你还没有候选人档案，发送 /apply 创建。", RemoveKeyboard: true}, nil
+	}
+	return BotReply{}, err
+}
+
+// loadCandidateProfile returns the (non-deleted) candidate linked to a Telegram account.
+func loadCandidateProfile(ctx context.Context, q *db.Queries, tgUserID int64) (domain.CandidateProfile, error) {
+	row, err := q.GetCandidateProfileByTelegramID(ctx, tgUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CandidateProfile{}, domain.ErrNotFound
+		}
+		return domain.CandidateProfile{}, err
+	}
+	skillRows, err := q.ListSkillsByCandidateIDs(ctx, []int64{row.ID})
+	if err != nil {
+		return domain.CandidateProfile{}, err
+	}
+	skills := make([]string, 0, len(skillRows))
+	for _, r := range skillRows {
+		skills = append(skills, r.Name)
+	}
+
+	return domain.CandidateProfile{
+		ID:             row.ID,
+		Slug:           row.PublicSlug,
+		DisplayName:    row.DisplayName,
+		Status:         row.Status,
+		TgUsername:     util.TextOrEmpty(row.TgUsername),
+		NotifyOnUnlock: row.NotifyOnUnlock,
+		ContactMode:    row.ContactMode,
+		OnboardingDraft: domain.OnboardingDraft{
+			DesiredRole:      util.TextOrEmpty(row.DesiredRole),
+			EnglishLevel:     util.TextOrEmpty(row.EnglishLevel),
+			SalaryMin:        util.Int4OrZero(row.ExpectedSalaryMinCny),
+			SalaryMax:        util.Int4OrZero(row.ExpectedSalaryMaxCny),
+			AvailabilityDays: util.Int4OrZero(row.AvailabilityDays),
+			Timezone:         util.TextOrEmpty(row.Timezone),
+			Skills:           skills,
+		},
+	}, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/repo"
+)
+
+func TestCandidateProfileDeletePurgesContactsAndDetaches(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	q := db.New(pool)
+	s := &CandidateProfileService{Q: q, Repo: &repo.CandidateRepo{Q: q, Pool: pool}}
+
+	u := BotUser{ID: time.Now().UnixNano() % 1_000_000_000_000, Username: "leaving"}
+	u.ChatID = u.ID
+	candidateID := dbtest.SeedCandidates(t, pool, 1)[0]
+	companyID, hrUserID := dbtest.SeedCompany(t, pool, 0)
+	if _, err := pool.Exec(ctx, `UPDATE candidates SET tg_user_id = $2, contact_consent_at = now() WHERE id = $1`, candidateID, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO candidate_contacts (candidate_id, tg_username, email, phone) VALUES ($1, 'leaving', 'a@example.com', '+100')`, candidateID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO unlocks (company_id, hr_user_id, candidate_id, unlock_type) VALUES ($1, $2, $3, 'contact')`,
+		companyID, hrUserID, candidateID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Delete(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		status   string
+		tgUserID *int64
+		consent  *time.Time
+		contacts int
+		unlocks  int
+	)
+	if err := pool.QueryRow(ctx, `
+SELECT c.status, c.tg_user_id, c.contact_consent_at,
+       (SELECT count(*) FROM candidate_contacts WHERE candidate_id = c.id),
+       (SELECT count(*) FROM unlocks WHERE candidate_id = c.id)
+FROM candidates c WHERE c.id = $1`, candidateID).Scan(&status, &tgUserID, &consent, &contacts, &unlocks); err != nil {
+		t.Fatal(err)
+	}
+	if status != "deleted" || tgUserID != nil || consent != nil {
+		t.Errorf("candidate is %s, tg_user_id %v, consent %v; want deleted and detached", status, tgUserID, consent)
+	}
+	if contacts != 0 {
+		t.Errorf("%d contact rows left", contacts)
+	}
+	// companies keep their unlock history
+	if unlocks != 1 {
+		t.Errorf("%d unlocks left, want 1", unlocks)
+	}
+
+	// the account no longer has a profile and may /apply again
+	reply, err := s.Delete(ctx, u)
+	if err != nil || !strings.Contains(reply.Text, "还没有候选人档案") {
+		t.Errorf("second delete: %q, %v", reply.Text, err)
+	}
+	if _, err := loadCandidateProfile(ctx, q, u.ID); err == nil {
+		t.Error("deleted profile still linked to the Telegram account")
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
     "context"
+    "errors"
     "time"
 
     "tg-hr-platform/internal/cache"
@@ -28,6 +29,8 @@ func (s *CandidateService) ListCandidates(ctx context.Context, f domain.Candidat
         SalaryMax:    f.SalaryMax,
         Skill:        f.Skill,
         Q:            f.Q,
+        Tag:          f.Tag,
+        UpdatedSince: f.UpdatedSince,
         Limit:        f.Limit,
         Offset:       f.Offset,
     })
@@ -57,10 +60,31 @@ func (s *CandidateService) ListCandidates(ctx context.Context, f domain.Candidat
             BCExperience:      r.BcExperience,
             Summary:           util.TextOrEmpty(r.Summary),
             UnlockedContact:   r.UnlockedContact,
+            UnlockCost:        r.UnlockCost,
             Skills:            []string{},
+            Pipelines:         []domain.CandidatePipelineStage{},
+            Tags:              []string{},
         }
     }
 
+    tags, err := s.Repo.ListTagsByIDs(ctx, f.CompanyID, ids)
+    if err != nil {
+        return nil, err
+    }
+    for _, t := range tags {
+        i := idToIdx[t.CandidateID]
+        out[i].Tags = append(out[i].Tags, t.Tag)
+    }
+
+    stages, err := s.Repo.ListPipelineStagesByIDs(ctx, f.CompanyID, ids)
+    if err != nil {
+        return nil, err
+    }
+    for _, st := range stages {
+        i := idToIdx[st.CandidateID]
+        out[i].Pipelines = append(out[i].Pipelines, pipelineStageOf(st))
+    }
+
     hit, miss, err := s.Cache.GetSkillsBatch(ctx, ids)
     if err != nil {
         return nil, err
@@ -109,7 +133,10 @@ func (s *CandidateService) GetCandidateDetail(ctx context.Context, companyID int
             BCExperience:      r.BcExperience,
             Summary:           util.TextOrEmpty(r.Summary),
             UnlockedContact:   r.UnlockedContact,
+            UnlockCost:        r.UnlockCost,
             Skills:            []string{},
+            Pipelines:         []domain.CandidatePipelineStage{},
+            Tags:              []string{},
         },
     }
 
@@ -139,16 +166,57 @@ func (s *CandidateService) GetCandidateDetail(ctx context.Context, companyID int
         }
     }
 
+    d.ContactMode, d.ContactRequest, err = s.Repo.ContactRequestState(ctx, companyID, r.ID)
+    if err != nil {
+        return nil, err
+    }
+    if r.UnlockedContact {
+        d.ContactReport, err = s.Repo.ContactReport(ctx, companyID, r.ID)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    stages, err := s.Repo.ListPipelineStagesByIDs(ctx, companyID, []int64{r.ID})
+    if err != nil {
+        return nil, err
+    }
+    for _, st := range stages {
+        d.Pipelines = append(d.Pipelines, pipelineStageOf(st))
+    }
+
+    tags, err := s.Repo.ListTagsByIDs(ctx, companyID, []int64{r.ID})
+    if err != nil {
+        return nil, err
+    }
+    for _, t := range tags {
+        d.Tags = append(d.Tags, t.Tag)
+    }
+
+    d.Notes, err = s.Repo.ListNotes(ctx, companyID, r.ID)
+    if err != nil {
+        return nil, err
+    }
+
     return d, nil
 }
 
-func (s *CandidateService) UnlockContact(ctx context.Context, companyID, hrUserID int64, slug string) (*domain.CandidateContact, error) {
+// UnlockContact reveals the contact, charging quota on the first unlock. Candidates in
+// request mode get a contact request instead (UnlockResult.ContactRequest).
+func (s *CandidateService) UnlockContact(ctx context.Context, companyID, hrUserID int64, slug string) (*domain.UnlockResult, error) {
     candidateID, err := s.Repo.GetIDBySlug(ctx, slug)
     if err != nil {
         return nil, err
     }
 
     _, err = s.Repo.UnlockContactTx(ctx, companyID, hrUserID, candidateID)
+    if errors.Is(err, domain.ErrContactRequestPending) {
+        _, req, err := s.Repo.ContactRequestState(ctx, companyID, candidateID)
+        if err != nil {
+            return nil, err
+        }
+        return &domain.UnlockResult{ContactRequest: req}, nil
+    }
     if err != nil {
         return nil, err
     }
@@ -157,5 +225,14 @@ func (s *CandidateService) UnlockContact(ctx context.Context, companyID, hrUserI
     if err != nil {
         return nil, err
     }
-    return &cc, nil
+    return &domain.UnlockResult{Contact: &cc}, nil
+}
+
+func pipelineStageOf(r db.ListCandidatePipelineStagesRow) domain.CandidatePipelineStage {
+    return domain.CandidatePipelineStage{
+        PipelineID:   r.PipelineID,
+        PipelineName: r.PipelineName,
+        StageID:      r.StageID,
+        Stage:        r.StageName,
+    }
 }
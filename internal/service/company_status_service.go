@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/cache"
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+// CompanyStatusService resolves companies.status through a short-lived Redis cache.
+// Redis failures fall back to the database.
+type CompanyStatusService struct {
+	Q     *db.Queries
+	Cache *cache.CompanyStatusCache
+}
+
+// CompanyStatus implements middleware.CompanyStatusLookup
+func (s *CompanyStatusService) CompanyStatus(ctx context.Context, companyID int64) (string, error) {
+	if s.Cache != nil {
+		if status, err := s.Cache.Get(ctx, companyID); err == nil && status != "" {
+			return status, nil
+		}
+	}
+
+	company, err := s.Q.GetCompanyByID(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", err
+	}
+	if s.Cache != nil {
+		_ = s.Cache.Set(ctx, companyID, company.Status)
+	}
+	return company.Status, nil
+}
+
+// Invalidate drops the cached status after companies.status changes
+func (s *CompanyStatusService) Invalidate(ctx context.Context, companyID int64) {
+	if s.Cache != nil {
+		_ = s.Cache.Invalidate(ctx, companyID)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+)
+
+// ContactReportService lets companies report unlocked contacts that do not work, and
+// platform admins review the reports and refund the unlock.
+type ContactReportService struct {
+	Repo       *repo.ContactReportRepo
+	Candidates *repo.CandidateRepo
+}
+
+// Report flags the company's unlocked contact of the candidate for review.
+func (s *ContactReportService) Report(ctx context.Context, companyID, hrUserID int64, slug, reason, details string) (*domain.ContactReport, error) {
+	candidateID, err := s.Candidates.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.Create(ctx, companyID, hrUserID, candidateID, reason, details)
+}
+
+// List returns reports across companies, oldest first; status filters when set.
+func (s *ContactReportService) List(ctx context.Context, status *string, limit, offset int32) ([]domain.AdminContactReport, error) {
+	return s.Repo.AdminList(ctx, status, limit, offset)
+}
+
+// Resolve refunds (refund=true) or rejects a pending report and returns the reporting
+// company's ID with the closed report.
+func (s *ContactReportService) Resolve(ctx context.Context, reportID, adminTgID int64, refund bool, note string) (int64, *domain.ContactReport, error) {
+	if refund {
+		return s.Repo.RefundTx(ctx, reportID, adminTgID, note)
+	}
+	return s.Repo.Reject(ctx, reportID, adminTgID, note)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+)
+
+// ContactRequestCallbackRoute prefixes the callback_data of the accept/decline buttons
+// ("creq:accept:<id>", "creq:decline:<id>").
+const ContactRequestCallbackRoute = "creq"
+
+const (
+	ContactRequestActionAccept  = "accept"
+	ContactRequestActionDecline = "decline"
+)
+
+func contactRequestButtons(requestID int64) [][]BotButton {
+	id := strconv.FormatInt(requestID, 10)
+	return [][]BotButton{{
+		{Text: "✅ 同意", Data: ContactRequestCallbackRoute + ":" + ContactRequestActionAccept + ":" + id},
+		{Text: "❌ 拒绝", Data: ContactRequestCallbackRoute + ":" + ContactRequestActionDecline + ":" + id},
+	}}
+}
+
+// ContactRequestService lets request-mode candidates answer companies' contact requests
+// from the bot. Requests are looked up by the caller's Telegram user ID, so a forged
+// callback cannot answer someone else's request.
+type ContactRequestService struct {
+	Q     *db.Queries
+	Repo  *repo.CandidateRepo
+	Audit *AuditLogService
+}
+
+// Answer accepts (charging the company's quota) or declines a request.
+func (s *ContactRequestService) Answer(ctx context.Context, u BotUser, requestID int64, accept bool) (BotReply, error) {
+	req, err := s.Q.GetCandidateContactRequest(ctx, db.GetCandidateContactRequestParams{ID: requestID, TgUserID: u.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BotReply{Text: "该请求不存在或已失效。"}, nil
+		}
+		return BotReply{}, err
+	}
+	if reply, closed := closedContactRequestReply(req.Status, req.ExpiresAt); closed {
+		return reply, nil
+	}
+
+	action := "candidate.contact_request.decline"
+	if accept {
+		action = "candidate.contact_request.accept"
+		err = s.Repo.AcceptContactRequestTx(ctx, req.ID, req.CompanyID)
+	} else {
+		_, err = s.Q.DecideContactRequest(ctx, db.DecideContactRequestParams{ID: req.ID, Status: domain.ContactRequestDeclined})
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = domain.ErrContactRequestClosed
+		}
+	}
+	switch {
+	case errors.Is(err, domain.ErrContactRequestClosed):
+		return BotReply{Text: "该请求已处理或已过期。"}, nil
+	case errors.Is(err, domain.ErrQuotaExceeded), errors.Is(err, domain.ErrQuotaNotConfigured):
+		// the request stays pending; keep the buttons so the candidate can retry
+		return BotReply{
+			Text:    "该企业的解锁额度暂时不足，对方补充额度后可再次点击同意。",
+			Buttons: contactRequestButtons(req.ID),
+		}, nil
+	case err != nil:
+		return BotReply{}, err
+	}
+
+	if s.Audit != nil {
+		s.Audit.LogCandidate(u.ID, action, req.CandidateID, map[string]any{
+			"company_id": req.CompanyID,
+			"request_id": req.ID,
+		})
+	}
+	if accept {
+		return BotReply{Text: "✅ 已同意，该企业现在可以看到你的联系方式。"}, nil
+	}
+	return BotReply{Text: "已拒绝，该企业将无法查看你的联系方式。"}, nil
+}
+
+func closedContactRequestReply(status string, expiresAt time.Time) (BotReply, bool) {
+	switch domain.ContactRequestStatus(status, expiresAt, time.Now()) {
+	case domain.ContactRequestAccepted:
+		return BotReply{Text: "你已同意过该请求。"}, true
+	case domain.ContactRequestDeclined:
+		return BotReply{Text: "你已拒绝过该请求。"}, true
+	case domain.ContactRequestExpired:
+		return BotReply{Text: "该请求已过期。"}, true
+	}
+	return BotReply{}, false
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/domain"
+)
+
+func TestClosedContactRequestReply(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	earlier := time.Now().Add(-time.Hour)
+	tests := []struct {
+		status     string
+		expiresAt  time.Time
+		wantClosed bool
+		wantText   string
+	}{
+		{domain.ContactRequestPending, later, false, ""},
+		{domain.ContactRequestPending, earlier, true, "已过期"},
+		{domain.ContactRequestAccepted, later, true, "已同意"},
+		// a decision stands after the request would have expired
+		{domain.ContactRequestAccepted, earlier, true, "已同意"},
+		{domain.ContactRequestDeclined, earlier, true, "已拒绝"},
+	}
+	for _, tt := range tests {
+		reply, closed := closedContactRequestReply(tt.status, tt.expiresAt)
+		if closed != tt.wantClosed || !strings.Contains(reply.Text, tt.wantText) {
+			t.Errorf("%s (expires %s): closed=%v reply=%q, want closed=%v and %q",
+				tt.status, tt.expiresAt.Format(time.Kitchen), closed, reply.Text, tt.wantClosed, tt.wantText)
+		}
+	}
+}
+
+func TestContactRequestButtons(t *testing.T) {
+	rows := contactRequestButtons(42)
+	if len(rows) != 1 || len(rows[0]) != 2 {
+		t.Fatalf("buttons = %+v, want one row of accept and decline", rows)
+	}
+	for i, want := range []string{"creq:accept:42", "creq:decline:42"} {
+		if got := rows[0][i].Data; got != want {
+			t.Errorf("button %d data = %q, want %q", i, got, want)
+		}
+	}
+	// Telegram rejects callback_data over 64 bytes
+	for _, b := range contactRequestButtons(math.MaxInt64)[0] {
+		if len(b.Data) > 64 {
+			t.Errorf("callback data %q is %d bytes", b.Data, len(b.Data))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+	"tg-hr-platform/internal/util"
+)
+
+// CreditService exposes a company's credits and their ledger, and lets platform admins top
+// up credits and price candidates.
+type CreditService struct {
+	Q    *db.Queries
+	Repo *repo.CreditRepo
+}
+
+// Balance returns the company's credits, checked against the ledger; a mismatch is logged.
+// domain.ErrQuotaNotConfigured if the company has no quota row.
+func (s *CreditService) Balance(ctx context.Context, companyID int64) (domain.CreditBalance, error) {
+	r, err := s.Q.GetCreditBalance(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CreditBalance{}, domain.ErrQuotaNotConfigured
+		}
+		return domain.CreditBalance{}, err
+	}
+	reconciled := int64(r.CreditBalance) == r.LedgerBalance
+	if !reconciled {
+		log.Printf("⚠️  Credit balance of company %d is %d but its ledger adds up to %d", companyID, r.CreditBalance, r.LedgerBalance)
+	}
+	return domain.CreditBalance{
+		Balance:       r.CreditBalance,
+		LedgerBalance: r.LedgerBalance,
+		Reconciled:    reconciled,
+		PeriodGrant:   r.UnlockQuotaTotal,
+		PeriodSpent:   r.UnlockQuotaUsed,
+		PeriodStart:   util.DateOrEmpty(r.PeriodStart),
+		PeriodEnd:     util.DateOrEmpty(r.PeriodEnd),
+	}, nil
+}
+
+// Ledger returns the company's ledger entries, newest first.
+func (s *CreditService) Ledger(ctx context.Context, companyID int64, limit, offset int32) ([]domain.CreditEntry, error) {
+	rows, err := s.Q.ListCreditEntries(ctx, db.ListCreditEntriesParams{
+		CompanyID: companyID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.CreditEntry, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, creditEntryFromRow(r))
+	}
+	return out, nil
+}
+
+// TopUp adds amount credits to the company. A non-empty ref makes the call idempotent; the
+// bool reports whether credits were added (false when ref was already used).
+func (s *CreditService) TopUp(ctx context.Context, companyID int64, amount int32, ref, note string) (domain.CreditEntry, bool, error) {
+	if _, err := s.Q.GetCompanyByID(ctx, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CreditEntry{}, false, domain.ErrNotFound
+		}
+		return domain.CreditEntry{}, false, err
+	}
+	var refp *string
+	if ref != "" {
+		refp = &ref
+	}
+	r, added, err := s.Repo.TopUpTx(ctx, companyID, amount, refp, note)
+	if err != nil {
+		return domain.CreditEntry{}, false, err
+	}
+	return creditEntryFromRow(r), added, nil
+}
+
+// SetUnlockCost prices unlocking the candidate's contact and returns the previous cost.
+// Unlocks that already happened are not affected.
+func (s *CreditService) SetUnlockCost(ctx context.Context, slug string, cost int32) (int32, error) {
+	prev, err := s.Q.SetCandidateUnlockCost(ctx, db.SetCandidateUnlockCostParams{PublicSlug: slug, Cost: cost})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, err
+	}
+	return prev, nil
+}
+
+func creditEntryFromRow(r db.CreditEntryRow) domain.CreditEntry {
+	return domain.CreditEntry{
+		ID:            r.ID,
+		Kind:          r.Kind,
+		Amount:        r.Amount,
+		BalanceAfter:  r.BalanceAfter,
+		CandidateSlug: util.TextOrEmpty(r.CandidateSlug),
+		HRUserID:      r.HrUserID.Int64,
+		Ref:           util.TextOrEmpty(r.Ref),
+		Note:          r.Note,
+		CreatedAt:     r.CreatedAt,
+	}
+}
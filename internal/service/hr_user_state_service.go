@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/cache"
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+// HRUserStateService resolves the current status, role and session_version of an HR user
+// through a short-lived Redis cache, so blocks and role changes apply without re-login.
+// Redis failures fall back to the database.
+type HRUserStateService struct {
+	Q     *db.Queries
+	Cache *cache.HRUserStateCache
+}
+
+// HRUserState implements middleware.HRUserStateLookup.
+// Removed (or missing) users yield domain.ErrNotFound.
+func (s *HRUserStateService) HRUserState(ctx context.Context, hrUserID int64) (domain.HRUserState, error) {
+	if s.Cache != nil {
+		if st, err := s.Cache.Get(ctx, hrUserID); err == nil && st != nil {
+			if st.Status == domain.HRStatusRemoved {
+				return domain.HRUserState{}, domain.ErrNotFound
+			}
+			return *st, nil
+		}
+	}
+
+	row, err := s.Q.GetHRUserState(ctx, hrUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.HRUserState{}, domain.ErrNotFound
+		}
+		return domain.HRUserState{}, err
+	}
+	st := domain.HRUserState{
+		HRUserID:       row.ID,
+		CompanyID:      row.CompanyID,
+		Status:         row.Status,
+		Role:           row.Role,
+		SessionVersion: row.SessionVersion,
+	}
+	if s.Cache != nil {
+		_ = s.Cache.Set(ctx, st)
+	}
+	if st.Status == domain.HRStatusRemoved {
+		return domain.HRUserState{}, domain.ErrNotFound
+	}
+	return st, nil
+}
+
+// RevokeSessions bumps session_version, invalidating every token issued to the user.
+func (s *HRUserStateService) RevokeSessions(ctx context.Context, hrUserID int64) error {
+	if _, err := s.Q.BumpHRUserSessionVersion(ctx, hrUserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	s.Invalidate(ctx, hrUserID)
+	return nil
+}
+
+// Invalidate drops the cached state after hr_users status/role/session_version changes
+func (s *HRUserStateService) Invalidate(ctx context.Context, hrUserID int64) {
+	if s != nil && s.Cache != nil {
+		_ = s.Cache.Invalidate(ctx, hrUserID)
+	}
+}
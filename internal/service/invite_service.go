@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+)
+
+const (
+	DefaultInviteTTL = 72 * time.Hour
+	MaxInviteTTL     = 30 * 24 * time.Hour
+	MaxInviteUses    = 100
+
+	// Telegram deep-link payloads allow [A-Za-z0-9_-], up to 64 chars.
+	// Skip look-alike characters so codes can also be typed by hand.
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	inviteCodeLen      = 12
+)
+
+type InviteService struct {
+	Q *db.Queries
+	// BotUsername (without @) is used to build https://t.me/<bot>?start=<code> links.
+	BotUsername string
+}
+
+func (s *InviteService) CreateInvite(ctx context.Context, companyID, createdBy int64, role string, maxUses int32, ttl time.Duration) (*domain.CompanyInvite, error) {
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.Q.CreateCompanyInvite(ctx, db.CreateCompanyInviteParams{
+		CompanyID: companyID,
+		Code:      code,
+		Role:      role,
+		MaxUses:   maxUses,
+		CreatedBy: createdBy,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	inv := s.toDomain(row, time.Now())
+	return &inv, nil
+}
+
+func (s *InviteService) ListInvites(ctx context.Context, companyID int64, limit, offset int32) ([]domain.CompanyInvite, error) {
+	rows, err := s.Q.ListCompanyInvites(ctx, db.ListCompanyInvitesParams{
+		CompanyID: companyID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	out := make([]domain.CompanyInvite, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, s.toDomain(r, now))
+	}
+	return out, nil
+}
+
+func (s *InviteService) RevokeInvite(ctx context.Context, companyID, inviteID int64) error {
+	n, err := s.Q.RevokeCompanyInvite(ctx, db.RevokeCompanyInviteParams{ID: inviteID, CompanyID: companyID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// SetInviteExpiry moves the expiry of a non-revoked invite; a ttl of 0 expires it immediately.
+func (s *InviteService) SetInviteExpiry(ctx context.Context, companyID, inviteID int64, ttl time.Duration) error {
+	n, err := s.Q.UpdateCompanyInviteExpiry(ctx, db.UpdateCompanyInviteExpiryParams{
+		ID:        inviteID,
+		CompanyID: companyID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *InviteService) toDomain(r db.CompanyInviteRow, now time.Time) domain.CompanyInvite {
+	inv := domain.CompanyInvite{
+		ID:        r.ID,
+		Code:      r.Code,
+		Role:      r.Role,
+		MaxUses:   r.MaxUses,
+		UsedCount: r.UsedCount,
+		CreatedBy: r.CreatedBy.Int64,
+		ExpiresAt: r.ExpiresAt.Time,
+		CreatedAt: r.CreatedAt.Time,
+	}
+	if s.BotUsername != "" {
+		inv.Link = "https://t.me/" + s.BotUsername + "?start=" + r.Code
+	}
+	switch {
+	case r.RevokedAt.Valid:
+		t := r.RevokedAt.Time
+		inv.RevokedAt = &t
+		inv.Status = domain.InviteStatusRevoked
+	case r.UsedCount >= r.MaxUses:
+		inv.Status = domain.InviteStatusUsedUp
+	case !r.ExpiresAt.Time.After(now):
+		inv.Status = domain.InviteStatusExpired
+	default:
+		inv.Status = domain.InviteStatusActive
+	}
+	return inv
+}
+
+// IsInviteCode reports whether s looks like a code made by generateInviteCode. Deep-link
+// and Mini App start parameters carry other payloads too (e.g. "apply").
+func IsInviteCode(s string) bool {
+	if len(s) != inviteCodeLen {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(inviteCodeAlphabet, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func generateInviteCode() (string, error) {
+	b := make([]byte, inviteCodeLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = inviteCodeAlphabet[int(b[i])%len(inviteCodeAlphabet)]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsInviteCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := generateInviteCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsInviteCode(code) {
+			t.Fatalf("generated code %q is not an invite code", code)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		"apply",
+		"ABCDEFGHJKL",   // too short
+		"ABCDEFGHJKLMN", // too long
+		"abcdefghjkmn",  // lower case
+		"ABCDEFGHJKL0",  // 0 is not in the alphabet
+		"ABCDEFGHJKLI",  // neither is I
+		"ABCDEF-HJKLM",
+		strings.Repeat("A", 64), // longest deep-link payload
+	} {
+		if IsInviteCode(s) {
+			t.Errorf("IsInviteCode(%q) = true", s)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+	"tg-hr-platform/internal/util"
+)
+
+// MemberService manages HR users within one company. Every method is scoped by companyID,
+// and actorID may never change their own membership (use another owner for that).
+type MemberService struct {
+	Repo *repo.HRUserRepo
+	// States, when set, has its cache entry dropped after every change so it applies immediately.
+	States *HRUserStateService
+}
+
+// MemberUpdate describes what changed, for audit logging.
+type MemberUpdate struct {
+	From string
+	To   string
+}
+
+func (s *MemberService) ListMembers(ctx context.Context, companyID int64) ([]domain.CompanyMember, error) {
+	rows, err := s.Repo.Q.ListCompanyMembers(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.CompanyMember, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, domain.CompanyMember{
+			ID:          r.ID,
+			TgUsername:  util.TextOrEmpty(r.TgUsername),
+			DisplayName: util.TextOrEmpty(r.DisplayName),
+			Role:        r.Role,
+			Status:      r.Status,
+			CreatedAt:   r.CreatedAt.Time,
+		})
+	}
+	return out, nil
+}
+
+// SetStatus approves (active) or blocks a member. Approving needs a free seat on the
+// company's plan (domain.ErrSeatLimit).
+func (s *MemberService) SetStatus(ctx context.Context, companyID, actorID, memberID int64, status string) (MemberUpdate, error) {
+	var upd MemberUpdate
+	err := s.change(ctx, companyID, actorID, memberID, func(ctx context.Context, q *db.Queries, m db.LockCompanyMemberRow) error {
+		upd = MemberUpdate{From: m.Status, To: status}
+		if m.Status == status {
+			return nil
+		}
+		if status == domain.HRStatusActive {
+			if err := repo.EnsureSeatAvailable(ctx, q, companyID); err != nil {
+				return err
+			}
+		} else {
+			if err := ensureNotLastOwner(ctx, q, companyID, m); err != nil {
+				return err
+			}
+		}
+		return q.UpdateHRUserStatus(ctx, db.UpdateHRUserStatusParams{ID: m.ID, Status: status})
+	})
+	return upd, err
+}
+
+func (s *MemberService) SetRole(ctx context.Context, companyID, actorID, memberID int64, role string) (MemberUpdate, error) {
+	var upd MemberUpdate
+	err := s.change(ctx, companyID, actorID, memberID, func(ctx context.Context, q *db.Queries, m db.LockCompanyMemberRow) error {
+		upd = MemberUpdate{From: m.Role, To: role}
+		if m.Role == role {
+			return nil
+		}
+		if err := ensureNotLastOwner(ctx, q, companyID, m); err != nil {
+			return err
+		}
+		return q.UpdateHRUserRole(ctx, db.UpdateHRUserRoleParams{ID: m.ID, Role: role})
+	})
+	return upd, err
+}
+
+func (s *MemberService) Remove(ctx context.Context, companyID, actorID, memberID int64) (MemberUpdate, error) {
+	var upd MemberUpdate
+	err := s.change(ctx, companyID, actorID, memberID, func(ctx context.Context, q *db.Queries, m db.LockCompanyMemberRow) error {
+		upd = MemberUpdate{From: m.Status, To: domain.HRStatusRemoved}
+		if err := ensureNotLastOwner(ctx, q, companyID, m); err != nil {
+			return err
+		}
+		return q.RemoveHRUser(ctx, m.ID)
+	})
+	return upd, err
+}
+
+// RevokeSessions signs a member out everywhere by bumping their session_version.
+func (s *MemberService) RevokeSessions(ctx context.Context, companyID, actorID, memberID int64) error {
+	return s.change(ctx, companyID, actorID, memberID, func(ctx context.Context, q *db.Queries, m db.LockCompanyMemberRow) error {
+		_, err := q.BumpHRUserSessionVersion(ctx, m.ID)
+		return err
+	})
+}
+
+func (s *MemberService) change(ctx context.Context, companyID, actorID, memberID int64, fn repo.MemberChange) error {
+	if actorID == memberID {
+		return domain.ErrCannotModifySelf
+	}
+	if err := s.Repo.WithLockedMember(ctx, companyID, memberID, fn); err != nil {
+		return err
+	}
+	s.States.Invalidate(ctx, memberID)
+	return nil
+}
+
+// ensureNotLastOwner refuses to take away the company's only active owner.
+func ensureNotLastOwner(ctx context.Context, q *db.Queries, companyID int64, m db.LockCompanyMemberRow) error {
+	if m.Role != domain.RoleOwner || m.Status != domain.HRStatusActive {
+		return nil
+	}
+	n, err := q.CountActiveCompanyOwners(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	if n <= 1 {
+		return domain.ErrLastOwner
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+)
+
+func seedMember(t *testing.T, pool *pgxpool.Pool, companyID int64, role, status string) int64 {
+	t.Helper()
+	var id int64
+	if err := pool.QueryRow(context.Background(), `
+INSERT INTO hr_users (company_id, tg_user_id, display_name, role, status)
+VALUES ($1, $2, 'member', $3, $4) RETURNING id`, companyID, time.Now().UnixNano(), role, status).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func memberRow(t *testing.T, pool *pgxpool.Pool, id int64) (role, status string, tgUserID *int64, sv int32) {
+	t.Helper()
+	if err := pool.QueryRow(context.Background(), `SELECT role, status, tg_user_id, session_version FROM hr_users WHERE id = $1`, id).
+		Scan(&role, &status, &tgUserID, &sv); err != nil {
+		t.Fatal(err)
+	}
+	return role, status, tgUserID, sv
+}
+
+func newMemberService(pool *pgxpool.Pool) *MemberService {
+	return &MemberService{Repo: &repo.HRUserRepo{Q: db.New(pool), Pool: pool}}
+}
+
+func TestMemberServiceRefusesSelfModification(t *testing.T) {
+	// refused before the database is touched
+	s := &MemberService{}
+	ctx := context.Background()
+	calls := map[string]func() error{
+		"SetStatus": func() error {
+			_, err := s.SetStatus(ctx, 1, 5, 5, domain.HRStatusBlocked)
+			return err
+		},
+		"SetRole": func() error {
+			_, err := s.SetRole(ctx, 1, 5, 5, domain.RoleRecruiter)
+			return err
+		},
+		"Remove":         func() error { _, err := s.Remove(ctx, 1, 5, 5); return err },
+		"RevokeSessions": func() error { return s.RevokeSessions(ctx, 1, 5, 5) },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, domain.ErrCannotModifySelf) {
+			t.Errorf("%s on self: %v, want ErrCannotModifySelf", name, err)
+		}
+	}
+}
+
+func TestMemberServiceKeepsLastOwner(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, owner := dbtest.SeedCompany(t, pool, 0)
+	admin := seedMember(t, pool, companyID, domain.RoleAdmin, domain.HRStatusActive)
+	s := newMemberService(pool)
+
+	refused := map[string]func() error{
+		"demote": func() error { _, err := s.SetRole(ctx, companyID, admin, owner, domain.RoleAdmin); return err },
+		"block":  func() error { _, err := s.SetStatus(ctx, companyID, admin, owner, domain.HRStatusBlocked); return err },
+		"remove": func() error { _, err := s.Remove(ctx, companyID, admin, owner); return err },
+	}
+	for name, call := range refused {
+		if err := call(); !errors.Is(err, domain.ErrLastOwner) {
+			t.Errorf("%s the only owner: %v, want ErrLastOwner", name, err)
+		}
+	}
+	if role, status, _, _ := memberRow(t, pool, owner); role != domain.RoleOwner || status != domain.HRStatusActive {
+		t.Fatalf("only owner is now %s/%s", role, status)
+	}
+
+	// with a second owner either may step down
+	if _, err := s.SetRole(ctx, companyID, owner, admin, domain.RoleOwner); err != nil {
+		t.Fatal(err)
+	}
+	upd, err := s.SetRole(ctx, companyID, admin, owner, domain.RoleAdmin)
+	if err != nil || upd.From != domain.RoleOwner || upd.To != domain.RoleAdmin {
+		t.Errorf("demoting one of two owners: %+v, %v", upd, err)
+	}
+
+	// members of other companies are not found
+	otherCompany, otherOwner := dbtest.SeedCompany(t, pool, 0)
+	if _, err := s.Remove(ctx, otherCompany, otherOwner, admin); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("removing another company's member: %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemberServiceApprovalNeedsSeat(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, owner := dbtest.SeedCompany(t, pool, 0)
+
+	var planID int64
+	if err := pool.QueryRow(ctx, `INSERT INTO plans (code, name, unlock_quota_monthly, seat_limit) VALUES ($1, 'Two seats', 0, 2) RETURNING id`,
+		fmt.Sprintf("member_test_%d", time.Now().UnixNano())).Scan(&planID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = pool.Exec(context.Background(), `DELETE FROM plans WHERE id = $1`, planID) })
+	if _, err := pool.Exec(ctx, `INSERT INTO company_plans (company_id, plan_id, effective_from) VALUES ($1, $2, CURRENT_DATE)`, companyID, planID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM company_plans WHERE company_id = $1`, companyID)
+	})
+
+	first := seedMember(t, pool, companyID, domain.RoleRecruiter, domain.HRStatusPending)
+	second := seedMember(t, pool, companyID, domain.RoleRecruiter, domain.HRStatusPending)
+	s := newMemberService(pool)
+
+	if _, err := s.SetStatus(ctx, companyID, owner, first, domain.HRStatusActive); err != nil {
+		t.Fatalf("approving into the last seat: %v", err)
+	}
+	if _, err := s.SetStatus(ctx, companyID, owner, second, domain.HRStatusActive); !errors.Is(err, domain.ErrSeatLimit) {
+		t.Fatalf("approving with no seat left: %v, want ErrSeatLimit", err)
+	}
+	if _, status, _, _ := memberRow(t, pool, second); status != domain.HRStatusPending {
+		t.Errorf("refused member is %s, want pending", status)
+	}
+
+	// blocking frees the seat
+	if _, err := s.SetStatus(ctx, companyID, owner, first, domain.HRStatusBlocked); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetStatus(ctx, companyID, owner, second, domain.HRStatusActive); err != nil {
+		t.Errorf("approving after a seat was freed: %v", err)
+	}
+}
+
+func TestMemberServiceRemoveEndsSessions(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	companyID, owner := dbtest.SeedCompany(t, pool, 0)
+	member := seedMember(t, pool, companyID, domain.RoleRecruiter, domain.HRStatusActive)
+	_, _, _, before := memberRow(t, pool, member)
+
+	upd, err := newMemberService(pool).Remove(ctx, companyID, owner, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upd.From != domain.HRStatusActive || upd.To != domain.HRStatusRemoved {
+		t.Errorf("update %+v", upd)
+	}
+	_, status, tgUserID, after := memberRow(t, pool, member)
+	if status != domain.HRStatusRemoved || tgUserID != nil {
+		t.Errorf("removed member is %s with tg_user_id %v, want removed and detached", status, tgUserID)
+	}
+	if after != before+1 {
+		t.Errorf("session_version %d -> %d, want it bumped", before, after)
+	}
+}
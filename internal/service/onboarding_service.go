@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/cache"
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+)
+
+const (
+	// OnboardingSessionTTL: unfinished onboardings older than this are discarded.
+	OnboardingSessionTTL = 24 * time.Hour
+
+	maxOnboardingSkills = 20
+	maxSkillLen         = 40
+	maxDesiredRoleLen   = 100
+	maxSalaryCNY        = 1_000_000
+	maxAvailabilityDays = 365
+
+	candidateSlugAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+	candidateSlugLen      = 10
+
+	consentAccept  = "✅ 同意"
+	consentDecline = "❌ 不同意"
+
+	// keepCurrent leaves a field unchanged during /edit
+	keepCurrent = "-"
+)
+
+// onboardingSteps is the question order; consent is only asked when creating a profile.
+var onboardingSteps = []string{
+	domain.OnboardingStepDesiredRole,
+	domain.OnboardingStepEnglishLevel,
+	domain.OnboardingStepSalary,
+	domain.OnboardingStepAvailability,
+	domain.OnboardingStepTimezone,
+	domain.OnboardingStepSkills,
+	domain.OnboardingStepConsent,
+}
+
+var utcOffsetRe = regexp.MustCompile(`^(?i)(utc|gmt)([+-](\d{1,2})(:\d{2})?)?$`)
+
+// BotUser is the Telegram account (and private chat) talking to the bot.
+type BotUser struct {
+	ID        int64
+	ChatID    int64
+	Username  string
+	FirstName string
+	LastName  string
+}
+
+// DisplayName is "First Last", falling back to the username.
+func (u BotUser) DisplayName() string {
+	name := strings.TrimSpace(strings.TrimSpace(u.FirstName) + " " + strings.TrimSpace(u.LastName))
+	if name == "" {
+		return u.Username
+	}
+	return name
+}
+
+// BotReply is a text reply, optionally with a one-time reply keyboard or inline Buttons
+// (Buttons take precedence; a message carries only one kind of keyboard).
+type BotReply struct {
+	Text           string
+	Keyboard       [][]string
+	RemoveKeyboard bool
+	Buttons        [][]BotButton
+}
+
+// BotButton is an inline keyboard button. Data is sent back as callback_data
+// ("<route>:<arg>", at most 64 bytes); URL buttons open a link instead.
+type BotButton struct {
+	Text string
+	Data string
+	URL  string
+}
+
+// OnboardingService runs the candidate self-onboarding conversation in the bot, and the
+// same questionnaire (prefilled) for /edit.
+// Per-chat state lives in candidate_onboarding_sessions so it survives restarts.
+type OnboardingService struct {
+	Q    *db.Queries
+	Repo *repo.CandidateRepo
+	// Cache, when set, has the candidate's skills invalidated after an edit.
+	Cache *cache.CandidateCache
+}
+
+// Start begins (or restarts) onboarding for the chat.
+func (s *OnboardingService) Start(ctx context.Context, u BotUser) (BotReply, error) {
+	if _, err := s.Q.FindCandidateIDByTelegramID(ctx, u.ID); err == nil {
+		return BotReply{Text: "你已经创建过候选人档案了。", RemoveKeyboard: true}, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return BotReply{}, err
+	}
+
+	if err := s.save(ctx, u, domain.OnboardingStepDesiredRole, domain.OnboardingDraft{}); err != nil {
+		return BotReply{}, err
+	}
+	reply := onboardingPrompt(domain.OnboardingStepDesiredRole)
+	reply.Text = "开始创建候选人档案，随时发送 /cancel 取消。\n\n" + reply.Text
+	return reply, nil
+}
+
+// StartEdit begins the questionnaire for the user's existing profile; each question shows
+// the current value and "-" keeps it.
+func (s *OnboardingService) StartEdit(ctx context.Context, u BotUser) (BotReply, error) {
+	profile, err := loadCandidateProfile(ctx, s.Q, u.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return BotReply{Text: "你还没有候选人档案，发送 /apply 创建。", RemoveKeyboard: true}, nil
+		}
+		return BotReply{}, err
+	}
+
+	draft := profile.OnboardingDraft
+	draft.CandidateID = profile.ID
+	if err := s.save(ctx, u, domain.OnboardingStepDesiredRole, draft); err != nil {
+		return BotReply{}, err
+	}
+	reply := promptFor(domain.OnboardingStepDesiredRole, draft)
+	reply.Text = "开始修改档案，随时发送 /cancel 取消。\n\n" + reply.Text
+	return reply, nil
+}
+
+// Cancel drops the caller's onboarding session in the chat. active reports whether one existed.
+func (s *OnboardingService) Cancel(ctx context.Context, u BotUser) (reply BotReply, active bool, err error) {
+	sess, err := s.Q.GetOnboardingSession(ctx, u.ChatID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BotReply{}, false, nil
+		}
+		return BotReply{}, false, err
+	}
+	if sess.TgUserID != u.ID {
+		return BotReply{}, false, nil
+	}
+	if err := s.Q.DeleteOnboardingSession(ctx, u.ChatID); err != nil {
+		return BotReply{}, true, err
+	}
+	return BotReply{Text: "已取消。", RemoveKeyboard: true}, true, nil
+}
+
+// HandleText answers the current onboarding question.
+// handled is false when the chat has no (fresh) onboarding session of the caller: in a
+// group, other members' messages must not answer someone else's questions.
+func (s *OnboardingService) HandleText(ctx context.Context, u BotUser, text string) (reply BotReply, handled bool, err error) {
+	sess, err := s.Q.GetOnboardingSession(ctx, u.ChatID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BotReply{}, false, nil
+		}
+		return BotReply{}, false, err
+	}
+	if sess.TgUserID != u.ID {
+		return BotReply{}, false, nil
+	}
+	if sess.UpdatedAt.Valid && time.Since(sess.UpdatedAt.Time) > OnboardingSessionTTL {
+		return BotReply{}, false, s.Q.DeleteOnboardingSession(ctx, u.ChatID)
+	}
+
+	var draft domain.OnboardingDraft
+	if len(sess.Data) > 0 {
+		if err := json.Unmarshal(sess.Data, &draft); err != nil {
+			return BotReply{}, true, err
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	if !(draft.Editing() && text == keepCurrent) {
+		if problem := applyOnboardingAnswer(sess.Step, text, &draft); problem != "" {
+			reply := promptFor(sess.Step, draft)
+			reply.Text = "⚠️ " + problem + "\n\n" + reply.Text
+			return reply, true, nil
+		}
+	}
+
+	next := nextOnboardingStep(sess.Step)
+	if draft.Editing() && next == domain.OnboardingStepConsent {
+		reply, err := s.completeEdit(ctx, u, draft)
+		return reply, true, err
+	}
+
+	if sess.Step == domain.OnboardingStepConsent {
+		if text == consentDecline {
+			if err := s.Q.DeleteOnboardingSession(ctx, u.ChatID); err != nil {
+				return BotReply{}, true, err
+			}
+			return BotReply{Text: "好的，未创建档案。发送 /apply 可重新开始。", RemoveKeyboard: true}, true, nil
+		}
+		reply, err := s.complete(ctx, u, draft)
+		return reply, true, err
+	}
+
+	if err := s.save(ctx, u, next, draft); err != nil {
+		return BotReply{}, true, err
+	}
+	return promptFor(next, draft), true, nil
+}
+
+// complete turns the finished draft into a candidate with the Telegram username as contact.
+func (s *OnboardingService) complete(ctx context.Context, u BotUser, draft domain.OnboardingDraft) (BotReply, error) {
+	if u.Username == "" {
+		reply := onboardingPrompt(domain.OnboardingStepConsent)
+		reply.Text = "⚠️ 你的 Telegram 账号还没有设置用户名，HR 将无法联系你。请在 Telegram 设置中添加用户名后再点同意。"
+		return reply, nil
+	}
+
+	slug, err := generateCandidateSlug()
+	if err != nil {
+		return BotReply{}, err
+	}
+	_, err = s.Repo.CreateOnboardedCandidate(ctx, u.ChatID, domain.NewCandidate{
+		TgUserID:        u.ID,
+		TgUsername:      u.Username,
+		Slug:            slug,
+		DisplayName:     u.DisplayName(),
+		OnboardingDraft: draft,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrCandidateExists) {
+			_ = s.Q.DeleteOnboardingSession(ctx, u.ChatID)
+			return BotReply{Text: "你已经创建过候选人档案了。", RemoveKeyboard: true}, nil
+		}
+		return BotReply{}, err
+	}
+	return BotReply{
+		Text:           fmt.Sprintf("🎉 档案已创建！编号：%s\n\nHR 解锁你的联系方式后会通过 @%s 联系你。", slug, u.Username),
+		RemoveKeyboard: true,
+	}, nil
+}
+
+// completeEdit writes the edited draft back to the candidate.
+func (s *OnboardingService) completeEdit(ctx context.Context, u BotUser, draft domain.OnboardingDraft) (BotReply, error) {
+	err := s.Repo.UpdateOnboardedCandidate(ctx, u.ChatID, domain.NewCandidate{
+		TgUsername:      u.Username,
+		DisplayName:     u.DisplayName(),
+		OnboardingDraft: draft,
+	})
+	if err != nil {
+		return BotReply{}, err
+	}
+	if s.Cache != nil {
+		_ = s.Cache.InvalidateSkills(ctx, draft.CandidateID)
+	}
+	return BotReply{Text: "✅ 档案已更新。发送 /profile 查看。", RemoveKeyboard: true}, nil
+}
+
+func (s *OnboardingService) save(ctx context.Context, u BotUser, step string, draft domain.OnboardingDraft) error {
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return err
+	}
+	return s.Q.UpsertOnboardingSession(ctx, db.UpsertOnboardingSessionParams{
+		ChatID:   u.ChatID,
+		TgUserID: u.ID,
+		Step:     step,
+		Data:     data,
+	})
+}
+
+func nextOnboardingStep(step string) string {
+	for i, st := range onboardingSteps {
+		if st == step && i+1 < len(onboardingSteps) {
+			return onboardingSteps[i+1]
+		}
+	}
+	return ""
+}
+
+// applyOnboardingAnswer validates text for step and records it in draft.
+// The returned problem is a user-facing message when the answer is rejected.
+func applyOnboardingAnswer(step, text string, draft *domain.OnboardingDraft) (problem string) {
+	switch step {
+	case domain.OnboardingStepDesiredRole:
+		if n := utf8.RuneCountInString(text); n < 2 || n > maxDesiredRoleLen {
+			return fmt.Sprintf("职位名称需要 2-%d 个字符。", maxDesiredRoleLen)
+		}
+		draft.DesiredRole = text
+		return ""
+
+	case domain.OnboardingStepEnglishLevel:
+		level := strings.ToLower(text)
+		if !domain.IsValidEnglishLevel(level) {
+			return "请从按钮中选择英语水平。"
+		}
+		draft.EnglishLevel = level
+		return ""
+
+	case domain.OnboardingStepSalary:
+		min, max, ok := parseSalaryRange(text)
+		if !ok {
+			return "无法识别薪资范围，请按 20000-30000 的格式填写。"
+		}
+		draft.SalaryMin, draft.SalaryMax = min, max
+		return ""
+
+	case domain.OnboardingStepAvailability:
+		days, err := strconv.Atoi(text)
+		if err != nil || days < 0 || days > maxAvailabilityDays {
+			return fmt.Sprintf("请输入 0-%d 之间的天数。", maxAvailabilityDays)
+		}
+		draft.AvailabilityDays = int32(days)
+		return ""
+
+	case domain.OnboardingStepTimezone:
+		tz, ok := normalizeTimezone(text)
+		if !ok {
+			return "无法识别时区，请填写 UTC+8 或 Asia/Shanghai 这样的格式。"
+		}
+		draft.Timezone = tz
+		return ""
+
+	case domain.OnboardingStepSkills:
+		skills, problem := parseSkills(text)
+		if problem != "" {
+			return problem
+		}
+		draft.Skills = skills
+		return ""
+
+	case domain.OnboardingStepConsent:
+		if text != consentAccept && text != consentDecline {
+			return "请点击下方按钮选择。"
+		}
+		return ""
+	}
+	return "会话状态异常，请发送 /cancel 后重新开始。"
+}
+
+// promptFor is onboardingPrompt plus, while editing, the current value and a "-" button.
+func promptFor(step string, draft domain.OnboardingDraft) BotReply {
+	reply := onboardingPrompt(step)
+	if !draft.Editing() {
+		return reply
+	}
+	reply.Text += "\n\n当前：" + currentOnboardingValue(step, draft) + "\n发送 - 保持不变"
+	reply.Keyboard = append(reply.Keyboard, []string{keepCurrent})
+	reply.RemoveKeyboard = false
+	return reply
+}
+
+func currentOnboardingValue(step string, d domain.OnboardingDraft) string {
+	switch step {
+	case domain.OnboardingStepDesiredRole:
+		return d.DesiredRole
+	case domain.OnboardingStepEnglishLevel:
+		return d.EnglishLevel
+	case domain.OnboardingStepSalary:
+		return fmt.Sprintf("%d-%d", d.SalaryMin, d.SalaryMax)
+	case domain.OnboardingStepAvailability:
+		return strconv.Itoa(int(d.AvailabilityDays))
+	case domain.OnboardingStepTimezone:
+		return d.Timezone
+	case domain.OnboardingStepSkills:
+		return strings.Join(d.Skills, ", ")
+	}
+	return ""
+}
+
+func onboardingPrompt(step string) BotReply {
+	switch step {
+	case domain.OnboardingStepDesiredRole:
+		return BotReply{Text: "1/7 你想应聘什么职位？例如：Go 后端工程师", RemoveKeyboard: true}
+	case domain.OnboardingStepEnglishLevel:
+		return BotReply{
+			Text:     "2/7 你的英语水平？",
+			Keyboard: [][]string{domain.EnglishLevels[:2], domain.EnglishLevels[2:]},
+		}
+	case domain.OnboardingStepSalary:
+		return BotReply{Text: "3/7 期望月薪范围（人民币），例如：20000-30000", RemoveKeyboard: true}
+	case domain.OnboardingStepAvailability:
+		return BotReply{Text: "4/7 最快多少天内可以到岗？请输入天数，例如：14"}
+	case domain.OnboardingStepTimezone:
+		return BotReply{Text: "5/7 你所在的时区？例如：UTC+8 或 Asia/Shanghai"}
+	case domain.OnboardingStepSkills:
+		return BotReply{Text: "6/7 你的技能（用逗号分隔），例如：Go, PostgreSQL, Redis"}
+	case domain.OnboardingStepConsent:
+		return BotReply{
+			Text:     "7/7 是否同意在 HR 解锁后，向其展示你的 Telegram 用户名以便联系你？",
+			Keyboard: [][]string{{consentAccept, consentDecline}},
+		}
+	}
+	return BotReply{Text: "发送 /apply 开始创建候选人档案"}
+}
+
+// parseSalaryRange accepts "20000-30000", "20k~30k" or a single amount.
+func parseSalaryRange(text string) (min, max int32, ok bool) {
+	text = strings.ToLower(strings.ReplaceAll(text, " ", ""))
+	for _, sep := range []string{"~", "～", "—", "－", "–", "至", "到"} {
+		text = strings.ReplaceAll(text, sep, "-")
+	}
+	parts := strings.Split(text, "-")
+	if len(parts) > 2 {
+		return 0, 0, false
+	}
+	vals := make([]int32, 0, 2)
+	for _, p := range parts {
+		mult := 1
+		if strings.HasSuffix(p, "k") {
+			mult = 1000
+			p = strings.TrimSuffix(p, "k")
+		}
+		n, err := strconv.Atoi(strings.ReplaceAll(p, ",", ""))
+		// compare before multiplying so huge amounts cannot overflow past the check
+		if err != nil || n <= 0 || n > maxSalaryCNY/mult {
+			return 0, 0, false
+		}
+		vals = append(vals, int32(n*mult))
+	}
+	min, max = vals[0], vals[len(vals)-1]
+	if max < min {
+		return 0, 0, false
+	}
+	return min, max, true
+}
+
+// normalizeTimezone accepts UTC/GMT offsets (normalized to "UTC+8") or IANA names.
+func normalizeTimezone(text string) (string, bool) {
+	if m := utcOffsetRe.FindStringSubmatch(text); m != nil {
+		if m[3] != "" {
+			if h, _ := strconv.Atoi(m[3]); h > 14 {
+				return "", false
+			}
+		}
+		if m[4] != "" {
+			if minutes, _ := strconv.Atoi(m[4][1:]); minutes > 59 {
+				return "", false
+			}
+		}
+		return "UTC" + m[2], true
+	}
+	if len(text) > 64 || !strings.Contains(text, "/") {
+		return "", false
+	}
+	if _, err := time.LoadLocation(text); err != nil {
+		return "", false
+	}
+	return text, true
+}
+
+// parseSkills splits on commas (ASCII or full-width), trims and de-duplicates case-insensitively.
+func parseSkills(text string) ([]string, string) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、' || r == ';' || r == '；' || r == '\n'
+	})
+	seen := make(map[string]bool, len(fields))
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if utf8.RuneCountInString(f) > maxSkillLen {
+			return nil, fmt.Sprintf("单个技能不能超过 %d 个字符。", maxSkillLen)
+		}
+		key := strings.ToLower(f)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, f)
+	}
+	if len(out) == 0 {
+		return nil, "请至少填写一项技能。"
+	}
+	if len(out) > maxOnboardingSkills {
+		return nil, fmt.Sprintf("最多填写 %d 项技能。", maxOnboardingSkills)
+	}
+	return out, ""
+}
+
+func generateCandidateSlug() (string, error) {
+	b := make([]byte, candidateSlugLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = candidateSlugAlphabet[int(b[i])%len(candidateSlugAlphabet)]
+	}
+	return "c-" + string(b), nil
+}
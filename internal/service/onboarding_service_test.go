@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/dbtest"
+	"tg-hr-platform/internal/repo"
+)
+
+func TestParseSalaryRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		min, max int32
+		ok       bool
+	}{
+		{"20000-30000", 20000, 30000, true},
+		{"20k~30k", 20000, 30000, true},
+		{"20K - 30K", 20000, 30000, true},
+		{"20,000–30,000", 20000, 30000, true},
+		{"20000至30000", 20000, 30000, true},
+		{"25000", 25000, 25000, true},
+		{"1000k", 1_000_000, 1_000_000, true},
+		{"30000-20000", 0, 0, false},
+		{"0-10000", 0, 0, false},
+		{"-5000", 0, 0, false},
+		{"1-2-3", 0, 0, false},
+		{"", 0, 0, false},
+		{"面议", 0, 0, false},
+		{"1000001", 0, 0, false},
+		{"1001k", 0, 0, false},
+		// n*1000 overflows int64 and wraps negative
+		{"9223372036854776k", 0, 0, false},
+		{"20000-9223372036854776k", 0, 0, false},
+	}
+	for _, tt := range tests {
+		min, max, ok := parseSalaryRange(tt.in)
+		if ok != tt.ok || min != tt.min || max != tt.max {
+			t.Errorf("parseSalaryRange(%q) = %d, %d, %v; want %d, %d, %v", tt.in, min, max, ok, tt.min, tt.max, tt.ok)
+		}
+	}
+}
+
+func TestNormalizeTimezone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"UTC+8", "UTC+8", true},
+		{"utc+8", "UTC+8", true},
+		{"GMT-5", "UTC-5", true},
+		{"UTC", "UTC", true},
+		{"UTC+05:30", "UTC+05:30", true},
+		{"UTC+8:59", "UTC+8:59", true},
+		{"UTC+14", "UTC+14", true},
+		{"UTC+8:60", "", false},
+		{"UTC+8:75", "", false},
+		{"UTC+15", "", false},
+		{"UTC+8:5", "", false},
+		{"+8", "", false},
+		{"Asia/Shanghai", "Asia/Shanghai", true},
+		{"America/Argentina/Buenos_Aires", "America/Argentina/Buenos_Aires", true},
+		{"Shanghai", "", false},
+		{"Mars/Olympus_Mons", "", false},
+		{"Asia/" + strings.Repeat("x", 64), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalizeTimezone(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("normalizeTimezone(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseSkills(t *testing.T) {
+	many := make([]string, maxOnboardingSkills+1)
+	for i := range many {
+		many[i] = "s" + strings.Repeat("x", i)
+	}
+	tests := []struct {
+		name        string
+		in          string
+		want        []string
+		wantProblem bool
+	}{
+		{"ASCII commas", "Go, PostgreSQL, Redis", []string{"Go", "PostgreSQL", "Redis"}, false},
+		{"full-width separators", "Go，PostgreSQL、Redis；Kafka", []string{"Go", "PostgreSQL", "Redis", "Kafka"}, false},
+		{"one per line", "Go\nRedis\n", []string{"Go", "Redis"}, false},
+		{"case-insensitive duplicates", "Go, go, GO, Redis", []string{"Go", "Redis"}, false},
+		{"empty fields", " , ,Go,, ", []string{"Go"}, false},
+		{"nothing", " , ", nil, true},
+		{"skill too long", "Go, " + strings.Repeat("x", maxSkillLen+1), nil, true},
+		{"longest skill", strings.Repeat("技", maxSkillLen), []string{strings.Repeat("技", maxSkillLen)}, false},
+		{"too many", strings.Join(many, ","), nil, true},
+	}
+	for _, tt := range tests {
+		got, problem := parseSkills(tt.in)
+		if (problem != "") != tt.wantProblem || !slices.Equal(got, tt.want) {
+			t.Errorf("%s: parseSkills = %q, problem %q", tt.name, got, problem)
+		}
+	}
+}
+
+func TestOnboardingIgnoresOtherGroupMembers(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	q := db.New(pool)
+	s := &OnboardingService{Q: q, Repo: &repo.CandidateRepo{Q: q, Pool: pool}}
+
+	base := time.Now().UnixNano() % 1_000_000_000_000
+	group := -base
+	owner := BotUser{ID: base, ChatID: group, Username: "owner_user", FirstName: "Owner"}
+	bystander := BotUser{ID: base + 1, ChatID: group, Username: "bystander_user", FirstName: "Bystander"}
+	candidateID := dbtest.SeedCandidates(t, pool, 1)[0]
+	if _, err := pool.Exec(ctx, `UPDATE candidates SET tg_user_id = $2 WHERE id = $1`, candidateID, owner.ID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), `DELETE FROM candidate_onboarding_sessions WHERE chat_id = $1`, group)
+	})
+
+	if _, err := s.StartEdit(ctx, owner); err != nil {
+		t.Fatal(err)
+	}
+	// the bystander can neither answer nor cancel the owner's /edit
+	if _, handled, err := s.HandleText(ctx, bystander, keepCurrent); err != nil || handled {
+		t.Fatalf("bystander's answer: handled=%v, %v", handled, err)
+	}
+	if _, active, err := s.Cancel(ctx, bystander); err != nil || active {
+		t.Fatalf("bystander's /cancel: active=%v, %v", active, err)
+	}
+
+	// the owner's answers still go through and the profile keeps the owner's identity
+	for i := 0; ; i++ {
+		if i > 20 {
+			t.Fatal("edit did not finish")
+		}
+		reply, handled, err := s.HandleText(ctx, owner, keepCurrent)
+		if err != nil || !handled {
+			t.Fatalf("owner's answer %d: handled=%v, %v", i, handled, err)
+		}
+		if strings.Contains(reply.Text, "档案已更新") {
+			break
+		}
+	}
+	var name, contact string
+	if err := pool.QueryRow(ctx, `
+SELECT c.display_name, cc.tg_username
+FROM candidates c JOIN candidate_contacts cc ON cc.candidate_id = c.id
+WHERE c.id = $1`, candidateID).Scan(&name, &contact); err != nil {
+		t.Fatal(err)
+	}
+	if name != owner.DisplayName() || contact != owner.Username {
+		t.Errorf("candidate is %q with contact %q, want the owner's", name, contact)
+	}
+}
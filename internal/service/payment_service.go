@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/payments"
+	"tg-hr-platform/internal/telegram"
+)
+
+// PaymentEvents applies provider events to payments; see repo.PaymentRepo.ApplyEventTx.
+type PaymentEvents interface {
+	ApplyEventTx(ctx context.Context, ev domain.PaymentEvent, payload []byte) (db.PaymentRow, bool, error)
+}
+
+// PaymentService sells credit packs through the registered payment providers and applies
+// their confirmations (webhooks, or Telegram payment updates from the bot).
+type PaymentService struct {
+	Q     *db.Queries
+	Repo  PaymentEvents
+	Audit *AuditLogService
+
+	providers map[string]payments.Provider
+}
+
+// Register enables a provider; call it during startup.
+func (s *PaymentService) Register(p payments.Provider) {
+	if s.providers == nil {
+		s.providers = map[string]payments.Provider{}
+	}
+	s.providers[p.Name()] = p
+}
+
+// Providers returns the names of the enabled providers.
+func (s *PaymentService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Packs returns the packs on sale with their price per enabled provider; packs no enabled
+// provider sells are left out.
+func (s *PaymentService) Packs(ctx context.Context) ([]domain.CreditPack, error) {
+	rows, err := s.Q.ListCreditPacks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := s.Providers()
+	out := make([]domain.CreditPack, 0, len(rows))
+	for _, r := range rows {
+		pack := domain.CreditPack{ID: r.ID, Code: r.Code, Name: r.Name, Credits: r.Credits, Prices: []domain.PackPrice{}}
+		for _, name := range names {
+			if amount, currency, ok := s.providers[name].Price(paymentPack(r)); ok {
+				pack.Prices = append(pack.Prices, domain.PackPrice{Provider: name, Amount: amount, Currency: currency})
+			}
+		}
+		if len(pack.Prices) > 0 {
+			out = append(out, pack)
+		}
+	}
+	return out, nil
+}
+
+// Checkout starts buying a pack with the provider; the buyer pays at the returned payment's
+// CheckoutURL. Fails with domain.ErrNotFound (pack), domain.ErrPaymentProviderUnavailable or
+// domain.ErrPaymentProvider.
+func (s *PaymentService) Checkout(ctx context.Context, companyID, hrUserID, packID int64, provider string) (domain.Payment, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return domain.Payment{}, domain.ErrPaymentProviderUnavailable
+	}
+	pack, err := s.Q.GetCreditPack(ctx, packID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Payment{}, domain.ErrNotFound
+		}
+		return domain.Payment{}, err
+	}
+	amount, currency, ok := p.Price(paymentPack(pack))
+	if !ok {
+		return domain.Payment{}, domain.ErrPaymentProviderUnavailable
+	}
+
+	row, err := s.Q.CreatePayment(ctx, db.CreatePaymentParams{
+		CompanyID: companyID,
+		HrUserID:  hrUserID,
+		PackID:    pack.ID,
+		Provider:  provider,
+		Credits:   pack.Credits,
+		Amount:    amount,
+		Currency:  currency,
+	})
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	session, err := p.CreateCheckout(ctx, payments.Checkout{
+		PaymentID:   row.ID,
+		Title:       pack.Name,
+		Description: fmt.Sprintf("为企业账户充值 %d 积分，充值积分长期有效", pack.Credits),
+		Amount:      amount,
+		Currency:    currency,
+	})
+	if err != nil {
+		log.Printf("❌ %s checkout for payment %d failed: %v", provider, row.ID, err)
+		if _, ferr := s.Q.MarkPaymentFailed(ctx, db.MarkPaymentFailedParams{ID: row.ID, Reason: "checkout_failed"}); ferr != nil {
+			log.Printf("❌ Failed to mark payment %d failed: %v", row.ID, ferr)
+		}
+		return domain.Payment{}, fmt.Errorf("%w: %v", domain.ErrPaymentProvider, err)
+	}
+	var providerRef *string
+	if session.ProviderRef != "" {
+		providerRef = &session.ProviderRef
+	}
+	row, err = s.Q.SetPaymentCheckout(ctx, db.SetPaymentCheckoutParams{ID: row.ID, ProviderRef: providerRef, CheckoutURL: session.URL})
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	return paymentFromRow(row), nil
+}
+
+// List returns the company's payments, newest first.
+func (s *PaymentService) List(ctx context.Context, companyID int64, limit, offset int32) ([]domain.Payment, error) {
+	rows, err := s.Q.ListCompanyPayments(ctx, db.ListCompanyPaymentsParams{CompanyID: companyID, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.Payment, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, paymentFromRow(r))
+	}
+	return out, nil
+}
+
+// Get returns one of the company's payments, e.g. to poll its status after checkout.
+func (s *PaymentService) Get(ctx context.Context, companyID, paymentID int64) (domain.Payment, error) {
+	row, err := s.Q.GetCompanyPayment(ctx, db.GetCompanyPaymentParams{CompanyID: companyID, ID: paymentID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Payment{}, domain.ErrNotFound
+		}
+		return domain.Payment{}, err
+	}
+	return paymentFromRow(row), nil
+}
+
+// HandleWebhook verifies and applies a provider webhook. Redeliveries, events that are not
+// about a payment outcome and events the platform cannot use (unknown payment, wrong amount)
+// are acknowledged with a nil error so the provider stops retrying; the latter are logged.
+// Fails with domain.ErrNotFound for providers without webhooks and payments.ErrInvalidSignature.
+func (s *PaymentService) HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) error {
+	p, ok := s.providers[provider].(payments.WebhookProvider)
+	if !ok {
+		return domain.ErrNotFound
+	}
+	ev, err := p.ParseWebhook(header, body)
+	if errors.Is(err, payments.ErrIgnoredEvent) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, _, err = s.apply(ctx, ev, body)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrPaymentAmountMismatch) {
+		return nil
+	}
+	return err
+}
+
+// apply records ev (see PaymentEvents) and audits the outcome.
+func (s *PaymentService) apply(ctx context.Context, ev domain.PaymentEvent, payload []byte) (db.PaymentRow, bool, error) {
+	row, applied, err := s.Repo.ApplyEventTx(ctx, ev, payload)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		log.Printf("⚠️  %s event %s matches no payment (payment_id=%d, ref=%q)", ev.Provider, ev.ID, ev.PaymentID, ev.ProviderRef)
+		return row, false, err
+	case errors.Is(err, domain.ErrPaymentAmountMismatch):
+		log.Printf("⚠️  %s payment %d was paid %d %s but charged %d %s; no credits added",
+			ev.Provider, row.ID, ev.Amount, ev.Currency, row.Amount, row.Currency)
+		return row, false, err
+	case err != nil:
+		return row, false, err
+	case !applied:
+		return row, false, nil
+	}
+
+	action := "payment.failed"
+	if row.Status == domain.PaymentSucceeded {
+		action = "payment.succeeded"
+		log.Printf("✅ Payment %d (%s) succeeded: %d credits for company %d", row.ID, row.Provider, row.Credits, row.CompanyID)
+	}
+	if s.Audit != nil {
+		s.Audit.LogCompany(row.CompanyID, row.HrUserID.Int64, action, "payment", strconv.FormatInt(row.ID, 10),
+			map[string]any{"provider": row.Provider, "pack": row.PackCode, "credits": row.Credits, "amount": row.Amount, "currency": row.Currency})
+	}
+	return row, true, nil
+}
+
+// PreCheckout decides whether Telegram may charge the user for an invoice: only pending
+// Telegram payments at their original price are accepted. errorMessage is shown otherwise.
+func (s *PaymentService) PreCheckout(ctx context.Context, q *telegram.PreCheckoutQuery) (ok bool, errorMessage string, err error) {
+	const expired = "订单已失效，请在招聘平台重新下单。"
+	id, valid := payments.ParseReference(q.InvoicePayload)
+	if !valid {
+		return false, expired, nil
+	}
+	row, err := s.Q.GetPayment(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, expired, nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	if row.Provider != domain.PaymentProviderTelegram || row.Status != domain.PaymentPending ||
+		row.Amount != q.TotalAmount || row.Currency != q.Currency {
+		return false, expired, nil
+	}
+	return true, "", nil
+}
+
+// CompleteTelegram applies a successful_payment message. The charge ID is the event ID, so a
+// repeated message is credited once.
+func (s *PaymentService) CompleteTelegram(ctx context.Context, u BotUser, p *telegram.SuccessfulPayment) (BotReply, error) {
+	support := BotReply{Text: "⚠️ 支付已完成，但未能自动为企业账户增加积分，请联系客服并提供支付凭证：" + p.TelegramPaymentChargeID}
+	id, ok := payments.ParseReference(p.InvoicePayload)
+	if !ok {
+		log.Printf("⚠️  Telegram payment %s from %d has an unknown payload %q", p.TelegramPaymentChargeID, u.ID, p.InvoicePayload)
+		return support, nil
+	}
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return BotReply{}, err
+	}
+	row, _, err := s.apply(ctx, domain.PaymentEvent{
+		Provider:    domain.PaymentProviderTelegram,
+		ID:          p.TelegramPaymentChargeID,
+		Type:        domain.PaymentSucceeded,
+		PaymentID:   id,
+		ProviderRef: p.TelegramPaymentChargeID,
+		Amount:      p.TotalAmount,
+		Currency:    p.Currency,
+	}, payload)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrPaymentAmountMismatch) {
+		return support, nil
+	}
+	if err != nil {
+		log.Printf("❌ Failed to apply Telegram payment %s (payment %d): %v", p.TelegramPaymentChargeID, id, err)
+		return support, nil
+	}
+	if row.Status != domain.PaymentSucceeded {
+		return support, nil
+	}
+	return BotReply{Text: fmt.Sprintf("✅ 支付成功！已为企业账户增加 %d 积分。", row.Credits)}, nil
+}
+
+func paymentPack(r db.CreditPackRow) payments.Pack {
+	return payments.Pack{Credits: r.Credits, PriceMinor: r.PriceMinor, Currency: r.Currency, PriceStars: r.PriceStars}
+}
+
+func paymentFromRow(r db.PaymentRow) domain.Payment {
+	p := domain.Payment{
+		ID:            r.ID,
+		PackCode:      r.PackCode,
+		Provider:      r.Provider,
+		Credits:       r.Credits,
+		Amount:        r.Amount,
+		Currency:      r.Currency,
+		Status:        r.Status,
+		CheckoutURL:   r.CheckoutURL,
+		FailureReason: r.FailureReason,
+		CreatedAt:     r.CreatedAt,
+	}
+	if r.PaidAt.Valid {
+		t := r.PaidAt.Time
+		p.PaidAt = &t
+	}
+	return p
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/payments"
+	"tg-hr-platform/internal/telegram"
+)
+
+// memPayments keeps payments in memory with the bookkeeping of repo.PaymentRepo.ApplyEventTx:
+// each event is recorded once per provider and ID, and what it does is decided by
+// domain.PaymentEvent.Outcome.
+type memPayments struct {
+	payments map[int64]*db.PaymentRow
+	events   map[string]bool
+	credits  map[int64]int32
+}
+
+func newMemPayments(rows ...db.PaymentRow) *memPayments {
+	m := &memPayments{payments: map[int64]*db.PaymentRow{}, events: map[string]bool{}, credits: map[int64]int32{}}
+	for _, r := range rows {
+		r.Status = domain.PaymentPending
+		m.payments[r.ID] = &r
+	}
+	return m
+}
+
+func (m *memPayments) ApplyEventTx(_ context.Context, ev domain.PaymentEvent, _ []byte) (db.PaymentRow, bool, error) {
+	p := m.find(ev)
+	if p == nil {
+		return db.PaymentRow{}, false, domain.ErrNotFound
+	}
+	key := ev.Provider + "/" + ev.ID
+	if m.events[key] {
+		return *p, false, nil
+	}
+	m.events[key] = true
+
+	switch ev.Outcome(p.Status, p.Amount, p.Currency) {
+	case domain.PaymentUnchanged:
+		return *p, false, nil
+	case domain.PaymentFail:
+		p.Status, p.FailureReason = domain.PaymentFailed, ev.FailureReason
+		return *p, true, nil
+	case domain.PaymentMismatch:
+		if p.Status == domain.PaymentPending {
+			p.Status, p.FailureReason = domain.PaymentFailed, "amount_mismatch"
+		}
+		return *p, false, domain.ErrPaymentAmountMismatch
+	}
+	p.Status, p.FailureReason = domain.PaymentSucceeded, ""
+	m.credits[p.CompanyID] += p.Credits
+	return *p, true, nil
+}
+
+func (m *memPayments) find(ev domain.PaymentEvent) *db.PaymentRow {
+	for _, p := range m.payments {
+		if p.Provider != ev.Provider {
+			continue
+		}
+		if p.ID == ev.PaymentID || (ev.PaymentID == 0 && p.ProviderRef.Valid && p.ProviderRef.String == ev.ProviderRef) {
+			return p
+		}
+	}
+	return nil
+}
+
+func fakePayment(id int64, amount int32) db.PaymentRow {
+	return db.PaymentRow{ID: id, CompanyID: 7, Provider: domain.PaymentProviderFake, Credits: 10, Amount: amount, Currency: "CNY"}
+}
+
+func TestPaymentServiceHandleWebhook(t *testing.T) {
+	ctx := context.Background()
+	fake := payments.NewFakeProvider("test-secret", "http://localhost:8080/payments/fake")
+	store := newMemPayments(fakePayment(1, 9900), fakePayment(2, 9900), fakePayment(3, 9900))
+	svc := &PaymentService{Repo: store}
+	svc.Register(fake)
+
+	// checkout charges amount; the store's payment was created for 9900
+	checkout := func(paymentID int64, amount int32) string {
+		t.Helper()
+		s, err := fake.CreateCheckout(ctx, payments.Checkout{PaymentID: paymentID, Amount: amount, Currency: "CNY"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := store.payments[paymentID]; p != nil {
+			p.ProviderRef = pgtype.Text{String: s.ProviderRef, Valid: true}
+		}
+		return s.ProviderRef
+	}
+	deliver := func(checkoutID string, succeeded bool) error {
+		t.Helper()
+		header, body, err := fake.Pay(checkoutID, succeeded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return svc.HandleWebhook(ctx, fake.Name(), header, body)
+	}
+	paid, short, retried := checkout(1, 9900), checkout(2, 100), checkout(3, 9900)
+	unknown := checkout(99, 9900)
+
+	steps := []struct {
+		name        string
+		checkout    string
+		succeeded   bool
+		payment     int64
+		wantStatus  string
+		wantCredits int32
+	}{
+		{"success credits the pack", paid, true, 1, domain.PaymentSucceeded, 10},
+		{"redelivery changes nothing", paid, true, 1, domain.PaymentSucceeded, 10},
+		{"late failure does not undo a success", paid, false, 1, domain.PaymentSucceeded, 10},
+		{"short payment is failed, not credited", short, true, 2, domain.PaymentFailed, 10},
+		{"declined card fails the payment", retried, false, 3, domain.PaymentFailed, 10},
+		{"retry on the same checkout succeeds", retried, true, 3, domain.PaymentSucceeded, 20},
+		{"unknown payment is acknowledged", unknown, true, 0, "", 20},
+	}
+	for _, step := range steps {
+		if err := deliver(step.checkout, step.succeeded); err != nil {
+			t.Fatalf("%s: HandleWebhook: %v", step.name, err)
+		}
+		if p := store.payments[step.payment]; p != nil && p.Status != step.wantStatus {
+			t.Errorf("%s: payment %d status = %s, want %s", step.name, step.payment, p.Status, step.wantStatus)
+		}
+		if got := store.credits[7]; got != step.wantCredits {
+			t.Errorf("%s: credits = %d, want %d", step.name, got, step.wantCredits)
+		}
+	}
+	if reason := store.payments[2].FailureReason; reason != "amount_mismatch" {
+		t.Errorf("short payment failure reason = %q, want amount_mismatch", reason)
+	}
+}
+
+func TestPaymentServiceHandleWebhookRefusals(t *testing.T) {
+	ctx := context.Background()
+	fake := payments.NewFakeProvider("test-secret", "")
+	store := newMemPayments(fakePayment(1, 9900))
+	svc := &PaymentService{Repo: store}
+	svc.Register(fake)
+
+	s, err := fake.CreateCheckout(ctx, payments.Checkout{PaymentID: 1, Amount: 9900, Currency: "CNY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, body, err := fake.Pay(s.ProviderRef, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged := []byte(strings.Replace(string(body), "9900", "990000", 1))
+	if err := svc.HandleWebhook(ctx, fake.Name(), header, forged); !errors.Is(err, payments.ErrInvalidSignature) {
+		t.Errorf("tampered body: got %v, want ErrInvalidSignature", err)
+	}
+	if err := svc.HandleWebhook(ctx, fake.Name(), http.Header{}, body); !errors.Is(err, payments.ErrInvalidSignature) {
+		t.Errorf("unsigned body: got %v, want ErrInvalidSignature", err)
+	}
+	if err := svc.HandleWebhook(ctx, domain.PaymentProviderCard, header, body); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("provider not enabled: got %v, want ErrNotFound", err)
+	}
+	if store.payments[1].Status != domain.PaymentPending || store.credits[7] != 0 {
+		t.Errorf("refused webhooks changed the payment: %+v, credits %d", store.payments[1], store.credits[7])
+	}
+}
+
+func TestPaymentServiceCompleteTelegram(t *testing.T) {
+	ctx := context.Background()
+	row := db.PaymentRow{ID: 4, CompanyID: 7, Provider: domain.PaymentProviderTelegram, Credits: 50, Amount: 250, Currency: domain.CurrencyStars}
+	store := newMemPayments(row)
+	svc := &PaymentService{Repo: store}
+	u := BotUser{ID: 1001, ChatID: 1001}
+
+	tests := []struct {
+		name        string
+		payment     telegram.SuccessfulPayment
+		wantReply   string
+		wantCredits int32
+	}{
+		{"unknown payload", telegram.SuccessfulPayment{InvoicePayload: "credits_50", TotalAmount: 250, Currency: "XTR", TelegramPaymentChargeID: "ch_0"}, "请联系客服", 0},
+		{"wrong amount", telegram.SuccessfulPayment{InvoicePayload: payments.Reference(4), TotalAmount: 1, Currency: "XTR", TelegramPaymentChargeID: "ch_1"}, "请联系客服", 0},
+		// the wrong amount failed the payment; paying the right one still succeeds
+		{"paid", telegram.SuccessfulPayment{InvoicePayload: payments.Reference(4), TotalAmount: 250, Currency: "XTR", TelegramPaymentChargeID: "ch_2"}, "增加 50 积分", 50},
+		{"repeated message", telegram.SuccessfulPayment{InvoicePayload: payments.Reference(4), TotalAmount: 250, Currency: "XTR", TelegramPaymentChargeID: "ch_2"}, "增加 50 积分", 50},
+		{"another payment", telegram.SuccessfulPayment{InvoicePayload: payments.Reference(5), TotalAmount: 250, Currency: "XTR", TelegramPaymentChargeID: "ch_3"}, "请联系客服", 50},
+	}
+	for _, tt := range tests {
+		reply, err := svc.CompleteTelegram(ctx, u, &tt.payment)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !strings.Contains(reply.Text, tt.wantReply) {
+			t.Errorf("%s: reply %q, want it to contain %q", tt.name, reply.Text, tt.wantReply)
+		}
+		if got := store.credits[7]; got != tt.wantCredits {
+			t.Errorf("%s: credits = %d, want %d", tt.name, got, tt.wantCredits)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+	"tg-hr-platform/internal/util"
+)
+
+// PipelineTransition is a candidate's stage change, for the audit log.
+type PipelineTransition struct {
+	PipelineID int64
+	// From is nil when the candidate was added to the pipeline
+	From *domain.PipelineStage
+	// To is nil when the candidate was removed from the pipeline
+	To *domain.PipelineStage
+}
+
+// AuditMeta describes the transition by stage IDs and names.
+func (t PipelineTransition) AuditMeta() map[string]any {
+	meta := map[string]any{"pipeline_id": t.PipelineID}
+	if t.From != nil {
+		meta["from_stage_id"] = t.From.ID
+		meta["from_stage"] = t.From.Name
+	}
+	if t.To != nil {
+		meta["to_stage_id"] = t.To.ID
+		meta["to_stage"] = t.To.Name
+	}
+	return meta
+}
+
+// PipelineService manages a company's hiring pipelines. Everything is scoped by company:
+// pipelines of other companies are reported as domain.ErrNotFound.
+type PipelineService struct {
+	Q          *db.Queries
+	Repo       *repo.PipelineRepo
+	Candidates *repo.CandidateRepo
+}
+
+// Create adds a pipeline; domain.DefaultPipelineStages are used when stages is empty.
+func (s *PipelineService) Create(ctx context.Context, companyID, hrUserID int64, name string, stages []string) (*domain.Pipeline, error) {
+	if len(stages) == 0 {
+		stages = domain.DefaultPipelineStages
+	}
+	row, err := s.Repo.CreatePipelineTx(ctx, companyID, hrUserID, name, stages)
+	if err != nil {
+		return nil, err
+	}
+	return s.withStages(ctx, row)
+}
+
+func (s *PipelineService) List(ctx context.Context, companyID int64) ([]domain.Pipeline, error) {
+	rows, err := s.Q.ListPipelines(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []domain.Pipeline{}, nil
+	}
+
+	ids := make([]int64, 0, len(rows))
+	out := make([]domain.Pipeline, len(rows))
+	idToIdx := make(map[int64]int, len(rows))
+	for i, r := range rows {
+		ids = append(ids, r.ID)
+		idToIdx[r.ID] = i
+		out[i] = pipelineFromRow(r)
+	}
+	stages, err := s.Q.ListPipelineStages(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range stages {
+		i := idToIdx[st.PipelineID]
+		out[i].Stages = append(out[i].Stages, pipelineStageFromRow(st))
+	}
+	return out, nil
+}
+
+// Get returns the pipeline with its candidates.
+func (s *PipelineService) Get(ctx context.Context, companyID, id int64) (*domain.PipelineDetail, error) {
+	row, err := s.Q.GetPipeline(ctx, db.GetPipelineParams{ID: id, CompanyID: companyID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	p, err := s.withStages(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.Q.ListPipelineCandidates(ctx, db.ListPipelineCandidatesParams{PipelineID: id, CompanyID: companyID})
+	if err != nil {
+		return nil, err
+	}
+
+	d := &domain.PipelineDetail{Pipeline: *p, Candidates: make([]domain.PipelineEntry, 0, len(entries))}
+	for _, e := range entries {
+		d.Candidates = append(d.Candidates, domain.PipelineEntry{
+			Slug:            e.PublicSlug,
+			DisplayName:     e.DisplayName,
+			DesiredRole:     util.TextOrEmpty(e.DesiredRole),
+			UnlockedContact: e.UnlockedContact,
+			StageID:         e.StageID,
+			AddedAt:         e.CreatedAt,
+			MovedAt:         e.MovedAt,
+		})
+	}
+	return d, nil
+}
+
+func (s *PipelineService) Rename(ctx context.Context, companyID, id int64, name string) (*domain.Pipeline, error) {
+	row, err := s.Q.RenamePipeline(ctx, db.RenamePipelineParams{ID: id, CompanyID: companyID, Name: name})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.withStages(ctx, row)
+}
+
+// ReplaceStages sets the pipeline's stages, see repo.PipelineRepo.ReplaceStagesTx.
+func (s *PipelineService) ReplaceStages(ctx context.Context, companyID, id int64, stages []domain.PipelineStageInput) (*domain.Pipeline, error) {
+	if err := s.Repo.ReplaceStagesTx(ctx, companyID, id, stages); err != nil {
+		return nil, err
+	}
+	row, err := s.Q.GetPipeline(ctx, db.GetPipelineParams{ID: id, CompanyID: companyID})
+	if err != nil {
+		return nil, err
+	}
+	return s.withStages(ctx, row)
+}
+
+func (s *PipelineService) Delete(ctx context.Context, companyID, id int64) error {
+	n, err := s.Q.DeletePipeline(ctx, db.DeletePipelineParams{ID: id, CompanyID: companyID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// MoveCandidate puts an active candidate into stageID, adding them to the pipeline if
+// needed. A nil transition means the candidate already was in that stage.
+func (s *PipelineService) MoveCandidate(ctx context.Context, companyID, hrUserID, pipelineID int64, slug string, stageID int64) (*PipelineTransition, error) {
+	p, err := s.get(ctx, companyID, pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	to := findPipelineStage(p.Stages, stageID)
+	if to == nil {
+		return nil, domain.ErrPipelineStageInvalid
+	}
+	candidateID, err := s.Candidates.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	prev, err := s.Q.MovePipelineCandidate(ctx, db.MovePipelineCandidateParams{
+		PipelineID:  pipelineID,
+		StageID:     stageID,
+		CandidateID: candidateID,
+		HrUserID:    hrUserID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the stage was removed concurrently
+			return nil, domain.ErrPipelineStageInvalid
+		}
+		return nil, err
+	}
+	if prev.Valid && prev.Int64 == stageID {
+		return nil, nil
+	}
+	t := &PipelineTransition{PipelineID: pipelineID, To: to}
+	if prev.Valid {
+		t.From = findPipelineStage(p.Stages, prev.Int64)
+	}
+	return t, nil
+}
+
+// RemoveCandidate takes a candidate out of the pipeline.
+func (s *PipelineService) RemoveCandidate(ctx context.Context, companyID, pipelineID int64, slug string) (*PipelineTransition, error) {
+	p, err := s.get(ctx, companyID, pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.Q.RemovePipelineCandidate(ctx, db.RemovePipelineCandidateParams{PipelineID: pipelineID, Slug: slug})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &PipelineTransition{PipelineID: pipelineID, From: findPipelineStage(p.Stages, row.StageID)}, nil
+}
+
+func (s *PipelineService) get(ctx context.Context, companyID, id int64) (*domain.Pipeline, error) {
+	row, err := s.Q.GetPipeline(ctx, db.GetPipelineParams{ID: id, CompanyID: companyID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.withStages(ctx, row)
+}
+
+func (s *PipelineService) withStages(ctx context.Context, row db.PipelineRow) (*domain.Pipeline, error) {
+	stages, err := s.Q.ListPipelineStages(ctx, []int64{row.ID})
+	if err != nil {
+		return nil, err
+	}
+	p := pipelineFromRow(row)
+	for _, st := range stages {
+		p.Stages = append(p.Stages, pipelineStageFromRow(st))
+	}
+	return &p, nil
+}
+
+func pipelineFromRow(r db.PipelineRow) domain.Pipeline {
+	return domain.Pipeline{
+		ID:        r.ID,
+		Name:      r.Name,
+		Stages:    []domain.PipelineStage{},
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func pipelineStageFromRow(r db.PipelineStageRow) domain.PipelineStage {
+	return domain.PipelineStage{
+		ID:             r.ID,
+		Name:           r.Name,
+		Position:       r.Position,
+		CandidateCount: r.CandidateCount,
+	}
+}
+
+func findPipelineStage(stages []domain.PipelineStage, id int64) *domain.PipelineStage {
+	for i := range stages {
+		if stages[i].ID == id {
+			return &stages[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+	"tg-hr-platform/internal/util"
+)
+
+// PlanService manages subscription plans and which company is on which (platform admins),
+// and resolves a company's current plan.
+type PlanService struct {
+	Q    *db.Queries
+	Repo *repo.PlanRepo
+}
+
+// PlanUpdate holds the fields to change; nil fields are left unchanged.
+type PlanUpdate struct {
+	Name               *string
+	UnlockQuotaMonthly *int32
+	SeatLimit          *int32
+	Features           map[string]bool
+	// Default makes the plan the one of companies without an assignment
+	Default bool
+}
+
+func (s *PlanService) List(ctx context.Context) ([]domain.Plan, error) {
+	rows, err := s.Q.ListPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.Plan, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, planFromRow(r))
+	}
+	return out, nil
+}
+
+// Create adds a plan; domain.ErrPlanExists if the code is taken.
+func (s *PlanService) Create(ctx context.Context, p domain.Plan) (domain.Plan, error) {
+	features, err := json.Marshal(planFeatures(p.Features))
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	row, err := s.Q.CreatePlan(ctx, db.CreatePlanParams{
+		Code:               p.Code,
+		Name:               p.Name,
+		UnlockQuotaMonthly: p.UnlockQuotaMonthly,
+		SeatLimit:          p.SeatLimit,
+		Features:           features,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Plan{}, domain.ErrPlanExists
+		}
+		return domain.Plan{}, err
+	}
+	return planFromRow(row), nil
+}
+
+// Update changes a plan. New allowances apply to companies from their next period on.
+func (s *PlanService) Update(ctx context.Context, id int64, upd PlanUpdate) (domain.Plan, error) {
+	var features []byte
+	if upd.Features != nil {
+		var err error
+		if features, err = json.Marshal(upd.Features); err != nil {
+			return domain.Plan{}, err
+		}
+	}
+	row, err := s.Q.UpdatePlan(ctx, db.UpdatePlanParams{
+		ID:                 id,
+		Name:               upd.Name,
+		UnlockQuotaMonthly: upd.UnlockQuotaMonthly,
+		SeatLimit:          upd.SeatLimit,
+		Features:           features,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Plan{}, domain.ErrNotFound
+		}
+		return domain.Plan{}, err
+	}
+	if upd.Default && !row.IsDefault {
+		if err := s.Q.SetDefaultPlan(ctx, id); err != nil {
+			return domain.Plan{}, err
+		}
+		row.IsDefault = true
+	}
+	return planFromRow(row), nil
+}
+
+// Assign puts a company on a plan from effectiveFrom on (see repo.PlanRepo.AssignPlanTx).
+// Returns the plan and, if the current period's allowance changed, the updated quota.
+func (s *PlanService) Assign(ctx context.Context, companyID, planID int64, effectiveFrom time.Time, adminTgID int64) (domain.Plan, *db.CompanyQuotaDetailRow, error) {
+	if _, err := s.Q.GetCompanyByID(ctx, companyID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Plan{}, nil, domain.ErrNotFound
+		}
+		return domain.Plan{}, nil, err
+	}
+	plan, err := s.Q.GetPlan(ctx, planID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Plan{}, nil, domain.ErrNotFound
+		}
+		return domain.Plan{}, nil, err
+	}
+	quota, err := s.Repo.AssignPlanTx(ctx, companyID, planID, effectiveFrom, adminTgID)
+	if err != nil {
+		return domain.Plan{}, nil, err
+	}
+	return planFromRow(plan), quota, nil
+}
+
+// Assignments lists a company's past and scheduled plan assignments, latest first.
+func (s *PlanService) Assignments(ctx context.Context, companyID int64) ([]domain.CompanyPlanAssignment, error) {
+	rows, err := s.Q.ListCompanyPlanAssignments(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.CompanyPlanAssignment, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, domain.CompanyPlanAssignment{
+			PlanID:        r.PlanID,
+			PlanCode:      r.PlanCode,
+			EffectiveFrom: util.DateOrEmpty(r.EffectiveFrom),
+			AssignedBy:    r.AssignedBy.Int64,
+			CreatedAt:     r.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// CompanyPlan returns the plan the company is on today; domain.ErrNotFound if it has no
+// assignment and there is no default plan.
+func (s *PlanService) CompanyPlan(ctx context.Context, companyID int64) (domain.Plan, error) {
+	row, err := s.Q.GetCompanyPlan(ctx, companyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Plan{}, domain.ErrNotFound
+		}
+		return domain.Plan{}, err
+	}
+	return planFromRow(row.PlanRow), nil
+}
+
+func planFromRow(r db.PlanRow) domain.Plan {
+	var features map[string]bool
+	_ = json.Unmarshal(r.Features, &features)
+	return domain.Plan{
+		ID:                 r.ID,
+		Code:               r.Code,
+		Name:               r.Name,
+		UnlockQuotaMonthly: r.UnlockQuotaMonthly,
+		SeatLimit:          r.SeatLimit,
+		Features:           planFeatures(features),
+		IsDefault:          r.IsDefault,
+		CreatedAt:          r.CreatedAt,
+		UpdatedAt:          r.UpdatedAt,
+	}
+}
+
+// planFeatures renders a missing feature set as {} rather than null
+func planFeatures(f map[string]bool) map[string]bool {
+	if f == nil {
+		return map[string]bool{}
+	}
+	return f
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+	"tg-hr-platform/internal/util"
+)
+
+// QuotaService exposes a company's finished quota periods.
+type QuotaService struct {
+	Q *db.Queries
+}
+
+// History returns the company's finished periods, newest first.
+func (s *QuotaService) History(ctx context.Context, companyID int64, limit, offset int32) ([]domain.QuotaPeriod, error) {
+	rows, err := s.Q.ListCompanyQuotaPeriods(ctx, db.ListCompanyQuotaPeriodsParams{
+		CompanyID: companyID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.QuotaPeriod, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, domain.QuotaPeriod{
+			PeriodStart:      util.DateOrEmpty(r.PeriodStart),
+			PeriodEnd:        util.DateOrEmpty(r.PeriodEnd),
+			UnlockQuotaTotal: r.UnlockQuotaTotal,
+			UnlockQuotaUsed:  r.UnlockQuotaUsed,
+			ClosedAt:         r.ClosedAt,
+		})
+	}
+	return out, nil
+}
+
+// QuotaRolloverWorker starts a new quota period for companies whose period_end has been
+// reached, archiving the finished period. Unlocks do the same lazily for their company
+// (see repo.lockCurrentQuota), so the worker mainly keeps /api/me and the admin console current.
+type QuotaRolloverWorker struct {
+	Repo *repo.QuotaRepo
+	// Interval between runs (default 10m)
+	Interval  time.Duration
+	BatchSize int32
+}
+
+// Run rolls over due periods until ctx is cancelled.
+func (w *QuotaRolloverWorker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Flush(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  Quota rollover: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush rolls over due periods in batches until none are left.
+func (w *QuotaRolloverWorker) Flush(ctx context.Context) error {
+	batch := w.BatchSize
+	if batch <= 0 {
+		batch = 100
+	}
+	for ctx.Err() == nil {
+		rows, err := w.Repo.RolloverDueTx(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			log.Printf("🔄 Quota period rolled over for company %d: %s – %s", r.CompanyID,
+				util.DateOrEmpty(r.PeriodStart), util.DateOrEmpty(r.PeriodEnd))
+		}
+		// rows locked by concurrent unlocks are skipped and rolled over by the unlock itself
+		if int32(len(rows)) < batch {
+			return nil
+		}
+	}
+	return nil
+}
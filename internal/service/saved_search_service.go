@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"tg-hr-platform/internal/db"
+	"tg-hr-platform/internal/domain"
+	"tg-hr-platform/internal/repo"
+)
+
+const (
+	// MaxSavedSearches is how many searches one HR user may keep.
+	MaxSavedSearches = 20
+	// SavedSearchDigestSize is how many new matches a digest lists.
+	SavedSearchDigestSize = 10
+
+	// SavedSearchCallbackRoute prefixes the callback_data of digest buttons
+	// ("ss:mute:<id>", "ss:unmute:<id>").
+	SavedSearchCallbackRoute = "ss"
+	SavedSearchActionMute    = "mute"
+	SavedSearchActionUnmute  = "unmute"
+
+	// savedSearchLease is how long a claimed search stays invisible to other workers.
+	savedSearchLease = 5 * time.Minute
+)
+
+// SavedSearchUpdate holds the fields of a PATCH; nil fields are kept.
+type SavedSearchUpdate struct {
+	Name      *string
+	Filters   *domain.CandidateListFilter
+	Frequency *string
+	Muted     *bool
+}
+
+// SavedSearchService manages an HR user's saved searches. Searches are private to the
+// user who saved them, since the digest goes to their own Telegram chat.
+type SavedSearchService struct {
+	Q    *db.Queries
+	Repo *repo.SavedSearchRepo
+}
+
+// Create saves a search; its first digest covers matches from now on. Frequency must be
+// one accepted by domain.SavedSearchInterval.
+func (s *SavedSearchService) Create(ctx context.Context, companyID, hrUserID int64, name string, filters domain.CandidateListFilter, frequency string) (*domain.SavedSearch, error) {
+	interval, ok := domain.SavedSearchInterval(frequency)
+	if !ok {
+		return nil, fmt.Errorf("invalid frequency %q", frequency)
+	}
+	raw, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.Repo.CreateTx(ctx, db.CreateSavedSearchParams{
+		CompanyID:       companyID,
+		HrUserID:        hrUserID,
+		Name:            name,
+		Filters:         raw,
+		Frequency:       frequency,
+		IntervalSeconds: int32(interval / time.Second),
+		MaxPerUser:      MaxSavedSearches,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return savedSearchFromRow(row)
+}
+
+func (s *SavedSearchService) List(ctx context.Context, hrUserID int64) ([]domain.SavedSearch, error) {
+	rows, err := s.Q.ListSavedSearches(ctx, hrUserID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.SavedSearch, 0, len(rows))
+	for _, r := range rows {
+		ss, err := savedSearchFromRow(r)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *ss)
+	}
+	return out, nil
+}
+
+func (s *SavedSearchService) Update(ctx context.Context, hrUserID, id int64, u SavedSearchUpdate) (*domain.SavedSearch, error) {
+	row, err := s.Q.GetSavedSearch(ctx, db.GetSavedSearchParams{ID: id, HrUserID: hrUserID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if u.Name != nil {
+		row.Name = *u.Name
+	}
+	if u.Filters != nil {
+		if row.Filters, err = json.Marshal(*u.Filters); err != nil {
+			return nil, err
+		}
+	}
+	if u.Frequency != nil {
+		row.Frequency = *u.Frequency
+	}
+	if u.Muted != nil {
+		row.Muted = *u.Muted
+	}
+	return s.save(ctx, row)
+}
+
+func (s *SavedSearchService) Delete(ctx context.Context, hrUserID, id int64) error {
+	n, err := s.Q.DeleteSavedSearch(ctx, db.DeleteSavedSearchParams{ID: id, HrUserID: hrUserID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// SetMuted handles the mute/unmute buttons of a digest. The search is looked up by the
+// caller's Telegram user ID, so a forged callback cannot mute someone else's search.
+func (s *SavedSearchService) SetMuted(ctx context.Context, u BotUser, id int64, muted bool) (BotReply, error) {
+	row, err := s.Q.GetTelegramSavedSearch(ctx, db.GetTelegramSavedSearchParams{ID: id, TgUserID: u.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BotReply{Text: "该搜索不存在或已删除。"}, nil
+		}
+		return BotReply{}, err
+	}
+	row.Muted = muted
+	ss, err := s.save(ctx, row)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return BotReply{Text: "该搜索不存在或已删除。"}, nil
+		}
+		return BotReply{}, err
+	}
+	if muted {
+		return BotReply{
+			Text:    fmt.Sprintf("🔕 已静音「%s」，不会再收到它的新匹配提醒。", ss.Name),
+			Buttons: [][]BotButton{{savedSearchButton("🔔 恢复提醒", SavedSearchActionUnmute, ss.ID)}},
+		}, nil
+	}
+	return BotReply{
+		Text:    fmt.Sprintf("🔔 已恢复「%s」的提醒（%s），之后的新匹配会发送给你。", ss.Name, savedSearchFrequencyLabel(ss.Frequency)),
+		Buttons: [][]BotButton{{savedSearchButton("🔕 静音此搜索", SavedSearchActionMute, ss.ID)}},
+	}, nil
+}
+
+func (s *SavedSearchService) save(ctx context.Context, row db.SavedSearchRow) (*domain.SavedSearch, error) {
+	interval, ok := domain.SavedSearchInterval(row.Frequency)
+	if !ok {
+		return nil, fmt.Errorf("invalid frequency %q", row.Frequency)
+	}
+	updated, err := s.Q.UpdateSavedSearch(ctx, db.UpdateSavedSearchParams{
+		ID:              row.ID,
+		HrUserID:        row.HrUserID,
+		Name:            row.Name,
+		Filters:         row.Filters,
+		Frequency:       row.Frequency,
+		Muted:           row.Muted,
+		IntervalSeconds: int32(interval / time.Second),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return savedSearchFromRow(updated)
+}
+
+func savedSearchFromRow(r db.SavedSearchRow) (*domain.SavedSearch, error) {
+	ss := &domain.SavedSearch{
+		ID:        r.ID,
+		Name:      r.Name,
+		Frequency: r.Frequency,
+		Muted:     r.Muted,
+		LastRunAt: r.LastRunAt,
+		NextRunAt: r.NextRunAt,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if err := json.Unmarshal(r.Filters, &ss.Filters); err != nil {
+		return nil, fmt.Errorf("saved search %d: invalid filters: %w", r.ID, err)
+	}
+	return ss, nil
+}
+
+func savedSearchButton(text, action string, id int64) BotButton {
+	return BotButton{Text: text, Data: SavedSearchCallbackRoute + ":" + action + ":" + strconv.FormatInt(id, 10)}
+}
+
+func savedSearchFrequencyLabel(frequency string) string {
+	switch frequency {
+	case domain.SavedSearchHourly:
+		return "每小时"
+	case domain.SavedSearchWeekly:
+		return "每周"
+	}
+	return "每天"
+}
+
+// SavedSearchDigestWorker runs due saved searches and queues a bot_outbox digest of the
+// candidates that matched since the previous run. Delivery (and its retries) is left to
+// BotOutboxWorker.
+type SavedSearchDigestWorker struct {
+	Q          *db.Queries
+	Candidates *CandidateService
+	// WebAppURL is linked from digests when it is https (Telegram rejects other button URLs)
+	WebAppURL string
+	// Interval between polls for due searches (default 1m)
+	Interval  time.Duration
+	BatchSize int32
+}
+
+// Run processes due searches until ctx is cancelled.
+func (w *SavedSearchDigestWorker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Flush(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  Saved search digests: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush claims one batch of due searches and runs each once.
+func (w *SavedSearchDigestWorker) Flush(ctx context.Context) error {
+	batch := w.BatchSize
+	if batch <= 0 {
+		batch = 20
+	}
+	rows, err := w.Q.ClaimDueSavedSearches(ctx, db.ClaimDueSavedSearchesParams{
+		Limit:        batch,
+		LeaseSeconds: int32(savedSearchLease / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		if ctx.Err() != nil {
+			// unprocessed searches become due again when their lease expires
+			return nil
+		}
+		if err := w.run(ctx, r); err != nil {
+			// retried once the lease expires
+			log.Printf("⚠️  Saved search %d: %v", r.ID, err)
+		}
+	}
+	return nil
+}
+
+func (w *SavedSearchDigestWorker) run(ctx context.Context, r db.ClaimDueSavedSearchesRow) error {
+	interval, ok := domain.SavedSearchInterval(r.Frequency)
+	if !ok {
+		return fmt.Errorf("invalid frequency %q", r.Frequency)
+	}
+	var f domain.CandidateListFilter
+	if err := json.Unmarshal(r.Filters, &f); err != nil {
+		return fmt.Errorf("invalid filters: %w", err)
+	}
+	since := r.LastRunAt
+	f.CompanyID = r.CompanyID
+	f.UpdatedSince = &since
+	f.Limit = SavedSearchDigestSize + 1
+	f.Offset = 0
+
+	cards, err := w.Candidates.ListCandidates(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	var digest []byte
+	if len(cards) > 0 {
+		d := domain.SavedSearchDigest{
+			SearchID: r.ID,
+			Name:     r.Name,
+			More:     len(cards) > SavedSearchDigestSize,
+			URL:      w.candidatesURL(),
+		}
+		for _, c := range cards[:min(len(cards), SavedSearchDigestSize)] {
+			d.Candidates = append(d.Candidates, domain.SavedSearchMatch{
+				Slug:        c.Slug,
+				DisplayName: c.DisplayName,
+				DesiredRole: c.DesiredRole,
+			})
+		}
+		if digest, err = json.Marshal(d); err != nil {
+			return err
+		}
+	}
+	return w.Q.CompleteSavedSearchRun(ctx, db.CompleteSavedSearchRunParams{
+		ID:              r.ID,
+		RunAt:           r.RunAt,
+		IntervalSeconds: int32(interval / time.Second),
+		ChatID:          r.ChatID,
+		Kind:            domain.BotOutboxSavedSearchDigest,
+		Digest:          digest,
+	})
+}
+
+func (w *SavedSearchDigestWorker) candidatesURL() string {
+	if !strings.HasPrefix(w.WebAppURL, "https://") {
+		return ""
+	}
+	u, err := url.JoinPath(w.WebAppURL, "candidates")
+	if err != nil {
+		return ""
+	}
+	return u
+}
+
+func renderSavedSearchDigest(d domain.SavedSearchDigest) (string, [][]BotButton) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔎 保存的搜索「%s」有新的匹配候选人：\n", d.Name)
+	for _, c := range d.Candidates {
+		b.WriteString("\n• " + c.DisplayName)
+		if c.DesiredRole != "" {
+			b.WriteString(" · " + c.DesiredRole)
+		}
+	}
+	if d.More {
+		b.WriteString("\n\n还有更多匹配，请在平台中查看。")
+	}
+
+	var buttons [][]BotButton
+	if d.URL != "" {
+		buttons = append(buttons, []BotButton{{Text: "📋 查看候选人", URL: d.URL}})
+	}
+	buttons = append(buttons, []BotButton{savedSearchButton("🔕 静音此搜索", SavedSearchActionMute, d.SearchID)})
+	return b.String(), buttons
+}
@@ -0,0 +1,215 @@
+// Package telegram is a small outbound client for the Telegram Bot API.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://api.telegram.org"
+	// DefaultMaxRetries bounds how often a request is retried after 429 Too Many Requests.
+	DefaultMaxRetries = 3
+	// maxRetryAfter caps how long a single 429 may hold a request back.
+	maxRetryAfter = time.Minute
+)
+
+// Client calls https://api.telegram.org/bot<token>/<method>. It is safe for concurrent use;
+// all requests share one rate limiter, and 429 responses are retried after retry_after.
+type Client struct {
+	token      string
+	baseURL    string
+	http       *http.Client
+	limiter    *rateLimiter
+	maxRetries int
+}
+
+func NewClient(token string) *Client {
+	return &Client{
+		token:      token,
+		baseURL:    DefaultBaseURL,
+		http:       &http.Client{Timeout: 15 * time.Second},
+		limiter:    newRateLimiter(DefaultGlobalInterval, DefaultChatInterval),
+		maxRetries: DefaultMaxRetries,
+	}
+}
+
+// SetBaseURL points the client at another Bot API server (a local server or an httptest stand-in).
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// SetHTTPClient replaces the default HTTP client (15s timeout).
+func (c *Client) SetHTTPClient(hc *http.Client) {
+	c.http = hc
+}
+
+// SetRateLimits overrides the minimum spacing between requests, overall and per chat.
+func (c *Client) SetRateLimits(global, perChat time.Duration) {
+	c.limiter = newRateLimiter(global, perChat)
+}
+
+// SetMaxRetries sets how many times a 429 response is retried (0 disables retries).
+func (c *Client) SetMaxRetries(n int) {
+	c.maxRetries = n
+}
+
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	var u User
+	if err := c.Call(ctx, "getMe", 0, nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (c *Client) SendMessage(ctx context.Context, p SendMessageParams) (*Message, error) {
+	var m Message
+	if err := c.Call(ctx, "sendMessage", p.ChatID, p, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *Client) EditMessageText(ctx context.Context, p EditMessageTextParams) (*Message, error) {
+	var m Message
+	if err := c.Call(ctx, "editMessageText", p.ChatID, p, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *Client) AnswerCallbackQuery(ctx context.Context, p AnswerCallbackQueryParams) error {
+	return c.Call(ctx, "answerCallbackQuery", 0, p, nil)
+}
+
+func (c *Client) SetMyCommands(ctx context.Context, commands []BotCommand) error {
+	return c.Call(ctx, "setMyCommands", 0, map[string]any{"commands": commands}, nil)
+}
+
+func (c *Client) SetWebhook(ctx context.Context, p SetWebhookParams) error {
+	return c.Call(ctx, "setWebhook", 0, p, nil)
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, dropPendingUpdates bool) error {
+	return c.Call(ctx, "deleteWebhook", 0, map[string]any{"drop_pending_updates": dropPendingUpdates}, nil)
+}
+
+// CreateInvoiceLink returns a t.me link that opens the invoice; the WebApp can open it in
+// place with Telegram.WebApp.openInvoice.
+func (c *Client) CreateInvoiceLink(ctx context.Context, p CreateInvoiceLinkParams) (string, error) {
+	var link string
+	if err := c.Call(ctx, "createInvoiceLink", 0, p, &link); err != nil {
+		return "", err
+	}
+	return link, nil
+}
+
+func (c *Client) AnswerPreCheckoutQuery(ctx context.Context, p AnswerPreCheckoutQueryParams) error {
+	return c.Call(ctx, "answerPreCheckoutQuery", 0, p, nil)
+}
+
+// GetUpdates long-polls for updates. Updates are returned undecoded so callers can parse
+// them into their own types; p.Timeout must stay below the HTTP client timeout.
+func (c *Client) GetUpdates(ctx context.Context, p GetUpdatesParams) ([]json.RawMessage, error) {
+	var updates []json.RawMessage
+	if err := c.Call(ctx, "getUpdates", 0, p, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// apiResponse is the Bot API envelope: {"ok":true,"result":...} or {"ok":false,"error_code":...}.
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  *struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// Call invokes any Bot API method: it POSTs params as JSON and decodes result into out (if non-nil).
+// chatID scopes the per-chat rate limit; 0 applies only the global limit.
+func (c *Client) Call(ctx context.Context, method string, chatID int64, params, out any) error {
+	body := []byte("{}")
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(ctx, chatID); err != nil {
+			return err
+		}
+		err := c.do(ctx, method, body, out)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests || attempt >= c.maxRetries {
+			return err
+		}
+		wait := time.Duration(apiErr.RetryAfter) * time.Second
+		if wait <= 0 {
+			wait = time.Second
+		}
+		if wait > maxRetryAfter {
+			return err
+		}
+		c.limiter.pause(time.Now().Add(wait))
+	}
+}
+
+func (c *Client) do(ctx context.Context, method string, body []byte, out any) error {
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// the URL contains the bot token; don't let it leak into logs
+		return fmt.Errorf("telegram %s: request failed: %w", method, redactToken(err, c.token))
+	}
+	defer resp.Body.Close()
+
+	var r apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("telegram %s: invalid response (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if !r.OK {
+		apiErr := &APIError{Method: method, Code: r.ErrorCode, Description: r.Description}
+		if apiErr.Code == 0 {
+			apiErr.Code = resp.StatusCode
+		}
+		if r.Parameters != nil {
+			apiErr.RetryAfter = r.Parameters.RetryAfter
+		}
+		return apiErr
+	}
+	// editMessageText returns plain `true` for inline messages
+	if out == nil || len(r.Result) == 0 || string(r.Result) == "true" {
+		return nil
+	}
+	if err := json.Unmarshal(r.Result, out); err != nil {
+		return fmt.Errorf("telegram %s: invalid result: %w", method, err)
+	}
+	return nil
+}
+
+func redactToken(err error, token string) error {
+	if token == "" {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), token, "<token>"))
+}
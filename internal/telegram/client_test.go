@@ -0,0 +1,155 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testToken = "123:abc"
+
+// fakeBotAPI stands in for api.telegram.org; handler receives the method name and JSON body.
+func fakeBotAPI(t *testing.T, handler func(method string, body map[string]any) (status int, resp string)) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := "/bot" + testToken + "/"
+		if len(r.URL.Path) <= len(prefix) || r.URL.Path[:len(prefix)] != prefix {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		status, resp := handler(r.URL.Path[len(prefix):], body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient(testToken)
+	c.SetBaseURL(srv.URL)
+	c.SetRateLimits(0, 0)
+	return c
+}
+
+func TestSendMessage(t *testing.T) {
+	c := fakeBotAPI(t, func(method string, body map[string]any) (int, string) {
+		if method != "sendMessage" || body["chat_id"] != float64(42) || body["text"] != "hi" {
+			t.Errorf("unexpected call %s %v", method, body)
+		}
+		return 200, `{"ok":true,"result":{"message_id":7,"chat":{"id":42,"type":"private"},"text":"hi"}}`
+	})
+
+	m, err := c.SendMessage(context.Background(), SendMessageParams{ChatID: 42, Text: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MessageID != 7 || m.Chat.ID != 42 {
+		t.Fatalf("unexpected message %+v", m)
+	}
+}
+
+func TestCreateInvoiceLink(t *testing.T) {
+	c := fakeBotAPI(t, func(method string, body map[string]any) (int, string) {
+		prices, _ := body["prices"].([]any)
+		if method != "createInvoiceLink" || body["currency"] != "XTR" || body["payload"] != "payment:7" || len(prices) != 1 {
+			t.Errorf("unexpected call %s %v", method, body)
+		}
+		if _, ok := body["provider_token"]; ok {
+			t.Errorf("Stars invoices must not send a provider_token: %v", body)
+		}
+		return 200, `{"ok":true,"result":"https://t.me/$abc"}`
+	})
+
+	link, err := c.CreateInvoiceLink(context.Background(), CreateInvoiceLinkParams{
+		Title:    "10 credits",
+		Payload:  "payment:7",
+		Currency: "XTR",
+		Prices:   []LabeledPrice{{Label: "10 credits", Amount: 500}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != "https://t.me/$abc" {
+		t.Fatalf("link = %q", link)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	c := fakeBotAPI(t, func(string, map[string]any) (int, string) {
+		return 400, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+	})
+
+	err := c.AnswerCallbackQuery(context.Background(), AnswerCallbackQueryParams{CallbackQueryID: "x"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != 400 || apiErr.Method != "answerCallbackQuery" {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+}
+
+func TestRetryAfter429(t *testing.T) {
+	var calls atomic.Int32
+	c := fakeBotAPI(t, func(string, map[string]any) (int, string) {
+		if calls.Add(1) == 1 {
+			return 429, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`
+		}
+		return 200, `{"ok":true,"result":{"id":1,"is_bot":true,"first_name":"HR","username":"hr_bot"}}`
+	})
+
+	start := time.Now()
+	u, err := c.GetMe(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Username != "hr_bot" || calls.Load() != 2 {
+		t.Fatalf("unexpected result %+v after %d calls", u, calls.Load())
+	}
+	if time.Since(start) < time.Second {
+		t.Fatal("retry did not honour retry_after")
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	c := fakeBotAPI(t, func(string, map[string]any) (int, string) {
+		calls.Add(1)
+		return 429, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":1}}`
+	})
+	c.SetMaxRetries(0)
+
+	if err := c.SetWebhook(context.Background(), SetWebhookParams{URL: "https://example.com/bot/webhook"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 call, got %d", calls.Load())
+	}
+}
+
+func TestPerChatRateLimit(t *testing.T) {
+	c := fakeBotAPI(t, func(string, map[string]any) (int, string) {
+		return 200, `{"ok":true,"result":{"message_id":1,"chat":{"id":1,"type":"private"}}}`
+	})
+	c.SetRateLimits(0, 100*time.Millisecond)
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.SendMessage(ctx, SendMessageParams{ChatID: 1, Text: "x"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("3 messages to one chat took %s, want >= 200ms", elapsed)
+	}
+
+	// other chats are not held back by chat 1
+	start = time.Now()
+	if _, err := c.SendMessage(ctx, SendMessageParams{ChatID: 2, Text: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("message to another chat waited %s", elapsed)
+	}
+}
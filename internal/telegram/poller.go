@@ -0,0 +1,143 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultPollTimeout is the getUpdates long-poll timeout; it must stay below the
+	// client's HTTP timeout (15s by default).
+	DefaultPollTimeout = 10 * time.Second
+	// DefaultUpdateTimeout bounds how long one update may be processed.
+	DefaultUpdateTimeout = 30 * time.Second
+
+	pollBackoffMin = time.Second
+	pollBackoffMax = 30 * time.Second
+)
+
+// UpdateHandler processes one raw update (the same JSON Telegram would POST to a webhook).
+type UpdateHandler func(ctx context.Context, update json.RawMessage)
+
+// Poller receives updates with getUpdates instead of a webhook, for deployments without
+// a public HTTPS URL. Updates are handled one at a time, in order.
+type Poller struct {
+	client         *Client
+	handler        UpdateHandler
+	pollTimeout    time.Duration
+	updateTimeout  time.Duration
+	allowedUpdates []string
+	// offset is the next update_id to fetch; everything below it has been handled
+	offset int64
+}
+
+func NewPoller(client *Client, handler UpdateHandler) *Poller {
+	return &Poller{
+		client:        client,
+		handler:       handler,
+		pollTimeout:   DefaultPollTimeout,
+		updateTimeout: DefaultUpdateTimeout,
+	}
+}
+
+// SetPollTimeout overrides the getUpdates long-poll timeout (0 means short polling).
+func (p *Poller) SetPollTimeout(d time.Duration) {
+	p.pollTimeout = d
+}
+
+// SetAllowedUpdates limits the update types Telegram delivers (e.g. "message").
+func (p *Poller) SetAllowedUpdates(types []string) {
+	p.allowedUpdates = types
+}
+
+// Run deletes any webhook (getUpdates fails while one is set) and polls until ctx is
+// cancelled. The update in progress is allowed to finish, and the offset reached is
+// acknowledged before returning so a restart does not receive the same updates again.
+// Undecodable updates are skipped; Run only fails if it cannot tell where to continue.
+func (p *Poller) Run(ctx context.Context) error {
+	if err := p.client.DeleteWebhook(ctx, false); err != nil {
+		return err
+	}
+	log.Println("🔄 Bot polling started")
+
+	backoff := pollBackoffMin
+	for ctx.Err() == nil {
+		updates, err := p.client.GetUpdates(ctx, GetUpdatesParams{
+			Offset:         p.offset,
+			Timeout:        int(p.pollTimeout / time.Second),
+			AllowedUpdates: p.allowedUpdates,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			// 409 Conflict: a webhook was set again or another instance is polling
+			log.Printf("⚠️  getUpdates failed, retrying in %s: %v", backoff, err)
+			if sleepCtx(ctx, backoff) != nil {
+				break
+			}
+			backoff = min(backoff*2, pollBackoffMax)
+			continue
+		}
+		backoff = pollBackoffMin
+
+		offset := p.offset
+		for _, raw := range updates {
+			var u struct {
+				UpdateID int64 `json:"update_id"`
+			}
+			if err := json.Unmarshal(raw, &u); err != nil || u.UpdateID == 0 {
+				// update_ids are sequential, so the bad update is the one at the offset;
+				// without an offset yet, a later update in the batch moves past it
+				log.Printf("⚠️  Skipping undecodable update at offset %d: %v", p.offset, err)
+				if p.offset > 0 {
+					p.offset++
+				}
+				continue
+			}
+			// in-flight updates finish even if shutdown starts meanwhile
+			p.dispatch(context.WithoutCancel(ctx), raw)
+			p.offset = u.UpdateID + 1
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if len(updates) > 0 && p.offset == offset {
+			// nothing in the batch could be decoded, and getUpdates would return it again
+			return fmt.Errorf("telegram: no decodable update in a batch of %d at offset %d", len(updates), offset)
+		}
+	}
+
+	p.acknowledge()
+	log.Println("🛑 Bot polling stopped")
+	return nil
+}
+
+// dispatch runs the handler for one update; a panic is logged and the update skipped
+// so that one bad update cannot stop the loop (or be redelivered forever).
+func (p *Poller) dispatch(ctx context.Context, raw json.RawMessage) {
+	ctx, cancel := context.WithTimeout(ctx, p.updateTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Panic while handling update: %v", r)
+		}
+	}()
+	p.handler(ctx, raw)
+}
+
+// acknowledge confirms handled updates to Telegram, which only forgets an update once
+// getUpdates is called with a higher offset.
+func (p *Poller) acknowledge() {
+	if p.offset == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := p.client.GetUpdates(ctx, GetUpdatesParams{Offset: p.offset, Limit: 1}); err != nil {
+		log.Printf("⚠️  Failed to acknowledge updates up to %d: %v", p.offset-1, err)
+	}
+}
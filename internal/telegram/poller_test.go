@@ -0,0 +1,195 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPollerHandlesUpdatesAndAcknowledgesOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		offsets []float64
+	)
+	c := fakeBotAPI(t, func(method string, body map[string]any) (int, string) {
+		switch method {
+		case "deleteWebhook":
+			return 200, `{"ok":true,"result":true}`
+		case "getUpdates":
+			mu.Lock()
+			offset, _ := body["offset"].(float64)
+			offsets = append(offsets, offset)
+			first := len(offsets) == 1
+			mu.Unlock()
+			if first {
+				return 200, `{"ok":true,"result":[{"update_id":10,"message":{"text":"a"}},{"update_id":11,"message":{"text":"b"}}]}`
+			}
+			return 200, `{"ok":true,"result":[]}`
+		}
+		t.Errorf("unexpected method %s", method)
+		return 404, `{"ok":false,"error_code":404,"description":"Not Found"}`
+	})
+
+	var handled []int64
+	p := NewPoller(c, func(hctx context.Context, raw json.RawMessage) {
+		var u struct {
+			UpdateID int64 `json:"update_id"`
+		}
+		_ = json.Unmarshal(raw, &u)
+		handled = append(handled, u.UpdateID)
+		if u.UpdateID == 11 {
+			// shutdown arrives mid-batch; the handler's context must stay usable
+			cancel()
+			if hctx.Err() != nil {
+				t.Error("handler context cancelled by shutdown")
+			}
+		}
+	})
+	p.SetPollTimeout(0)
+
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("poller did not stop")
+	}
+
+	if len(handled) != 2 || handled[0] != 10 || handled[1] != 11 {
+		t.Fatalf("unexpected handled updates %v", handled)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// first poll without offset, then the acknowledgement of both updates
+	if len(offsets) != 2 || offsets[0] != 0 || offsets[1] != 12 {
+		t.Fatalf("unexpected getUpdates offsets %v", offsets)
+	}
+}
+
+func TestPollerSurvivesHandlerPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	c := fakeBotAPI(t, func(method string, body map[string]any) (int, string) {
+		if method == "getUpdates" {
+			calls++
+			if calls == 1 {
+				return 200, `{"ok":true,"result":[{"update_id":1},{"update_id":2}]}`
+			}
+			return 200, `{"ok":true,"result":[]}`
+		}
+		return 200, `{"ok":true,"result":true}`
+	})
+
+	var handled []int64
+	p := NewPoller(c, func(_ context.Context, raw json.RawMessage) {
+		var u struct {
+			UpdateID int64 `json:"update_id"`
+		}
+		_ = json.Unmarshal(raw, &u)
+		handled = append(handled, u.UpdateID)
+		if u.UpdateID == 1 {
+			panic("boom")
+		}
+		cancel()
+	})
+	p.SetPollTimeout(0)
+
+	if err := p.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(handled) != 2 || p.offset != 3 {
+		t.Fatalf("handled %v, offset %d", handled, p.offset)
+	}
+}
+
+func TestPollerSkipsUndecodableUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	batches := []string{
+		`[{"update_id":5}]`,
+		`[{"update_id":"6"}]`,
+		`[{"update_id":7}]`,
+	}
+	var offsets []float64
+	c := fakeBotAPI(t, func(method string, body map[string]any) (int, string) {
+		if method != "getUpdates" {
+			return 200, `{"ok":true,"result":true}`
+		}
+		offset, _ := body["offset"].(float64)
+		offsets = append(offsets, offset)
+		if len(offsets) > len(batches) {
+			return 200, `{"ok":true,"result":[]}`
+		}
+		return 200, `{"ok":true,"result":` + batches[len(offsets)-1] + `}`
+	})
+
+	var handled []int64
+	p := NewPoller(c, func(_ context.Context, raw json.RawMessage) {
+		var u struct {
+			UpdateID int64 `json:"update_id"`
+		}
+		_ = json.Unmarshal(raw, &u)
+		handled = append(handled, u.UpdateID)
+		if u.UpdateID == 7 {
+			cancel()
+		}
+	})
+	p.SetPollTimeout(0)
+
+	if err := p.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(handled) != 2 || handled[0] != 5 || handled[1] != 7 {
+		t.Fatalf("handled %v, want [5 7]", handled)
+	}
+	// the bad update is not fetched again; the last offset is the acknowledgement
+	want := []float64{0, 6, 7, 8}
+	if len(offsets) != len(want) {
+		t.Fatalf("getUpdates offsets %v, want %v", offsets, want)
+	}
+	for i := range want {
+		if offsets[i] != want[i] {
+			t.Fatalf("getUpdates offsets %v, want %v", offsets, want)
+		}
+	}
+}
+
+func TestPollerStopsOnUndecodableFirstBatch(t *testing.T) {
+	var polls int
+	c := fakeBotAPI(t, func(method string, body map[string]any) (int, string) {
+		if method != "getUpdates" {
+			return 200, `{"ok":true,"result":true}`
+		}
+		polls++
+		return 200, `{"ok":true,"result":[{"message":{"text":"no id"}}]}`
+	})
+	p := NewPoller(c, func(context.Context, json.RawMessage) {
+		t.Error("handler called for an undecodable update")
+	})
+	p.SetPollTimeout(0)
+
+	done := make(chan error, 1)
+	go func() { done <- p.Run(context.Background()) }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Run kept polling the same undecodable update")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("poller did not stop")
+	}
+	if polls != 1 {
+		t.Errorf("polled %d times, want 1", polls)
+	}
+}
@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bot API limits: about 30 messages per second overall and one per second per chat.
+const (
+	DefaultGlobalInterval = time.Second / 30
+	DefaultChatInterval   = time.Second
+)
+
+// rateLimiter spaces out requests by reserving the next free slot, globally and per chat.
+type rateLimiter struct {
+	mu             sync.Mutex
+	globalInterval time.Duration
+	chatInterval   time.Duration
+	nextGlobal     time.Time
+	nextChat       map[int64]time.Time
+}
+
+func newRateLimiter(global, perChat time.Duration) *rateLimiter {
+	return &rateLimiter{globalInterval: global, chatInterval: perChat, nextChat: make(map[int64]time.Time)}
+}
+
+// wait blocks until a request to chatID (0 = not chat-bound) may be sent.
+func (l *rateLimiter) wait(ctx context.Context, chatID int64) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := now
+	if l.nextGlobal.After(at) {
+		at = l.nextGlobal
+	}
+	if chatID != 0 {
+		if next, ok := l.nextChat[chatID]; ok && next.After(at) {
+			at = next
+		}
+		l.nextChat[chatID] = at.Add(l.chatInterval)
+		if len(l.nextChat) > 10000 {
+			l.prune(now)
+		}
+	}
+	l.nextGlobal = at.Add(l.globalInterval)
+	l.mu.Unlock()
+
+	return sleepCtx(ctx, time.Until(at))
+}
+
+// pause holds back every request until t, e.g. after a 429 retry_after.
+func (l *rateLimiter) pause(t time.Time) {
+	l.mu.Lock()
+	if t.After(l.nextGlobal) {
+		l.nextGlobal = t
+	}
+	l.mu.Unlock()
+}
+
+func (l *rateLimiter) prune(now time.Time) {
+	for id, next := range l.nextChat {
+		if next.Before(now) {
+			delete(l.nextChat, id)
+		}
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package telegram
+
+import "fmt"
+
+// User is a Telegram user or bot.
+type User struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+type Message struct {
+	MessageID         int64              `json:"message_id"`
+	From              *User              `json:"from,omitempty"`
+	Chat              Chat               `json:"chat"`
+	Date              int64              `json:"date"`
+	Text              string             `json:"text,omitempty"`
+	SuccessfulPayment *SuccessfulPayment `json:"successful_payment,omitempty"`
+}
+
+// CallbackQuery is sent when a user presses an inline keyboard button with callback_data.
+// Message is nil for buttons on inline-mode messages.
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+type InlineQuery struct {
+	ID     string `json:"id"`
+	From   User   `json:"from"`
+	Query  string `json:"query"`
+	Offset string `json:"offset"`
+}
+
+type ChatMember struct {
+	User   User   `json:"user"`
+	Status string `json:"status"` // creator, administrator, member, restricted, left, kicked
+}
+
+// ChatMemberUpdated (my_chat_member) reports changes to the bot's own membership,
+// e.g. status "kicked" when a user blocks the bot in a private chat.
+type ChatMemberUpdated struct {
+	Chat          Chat       `json:"chat"`
+	From          User       `json:"from"`
+	Date          int64      `json:"date"`
+	OldChatMember ChatMember `json:"old_chat_member"`
+	NewChatMember ChatMember `json:"new_chat_member"`
+}
+
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string      `json:"text"`
+	URL          string      `json:"url,omitempty"`
+	CallbackData string      `json:"callback_data,omitempty"`
+	WebApp       *WebAppInfo `json:"web_app,omitempty"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type KeyboardButton struct {
+	Text string `json:"text"`
+}
+
+type ReplyKeyboardMarkup struct {
+	Keyboard        [][]KeyboardButton `json:"keyboard"`
+	OneTimeKeyboard bool               `json:"one_time_keyboard,omitempty"`
+	ResizeKeyboard  bool               `json:"resize_keyboard,omitempty"`
+}
+
+type ReplyKeyboardRemove struct {
+	RemoveKeyboard bool `json:"remove_keyboard"`
+}
+
+// SendMessageParams: ReplyMarkup is one of the *Markup types above (or nil).
+type SendMessageParams struct {
+	ChatID                int64  `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview,omitempty"`
+	ReplyMarkup           any    `json:"reply_markup,omitempty"`
+}
+
+type EditMessageTextParams struct {
+	ChatID      int64                 `json:"chat_id"`
+	MessageID   int64                 `json:"message_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode,omitempty"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type AnswerCallbackQueryParams struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+	URL             string `json:"url,omitempty"`
+	CacheTime       int    `json:"cache_time,omitempty"`
+}
+
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+type SetWebhookParams struct {
+	URL                string   `json:"url"`
+	SecretToken        string   `json:"secret_token,omitempty"`
+	AllowedUpdates     []string `json:"allowed_updates,omitempty"`
+	DropPendingUpdates bool     `json:"drop_pending_updates,omitempty"`
+	MaxConnections     int      `json:"max_connections,omitempty"`
+}
+
+// GetUpdatesParams: Offset acknowledges every update with a lower update_id; Timeout is in seconds.
+type GetUpdatesParams struct {
+	Offset         int64    `json:"offset,omitempty"`
+	Limit          int      `json:"limit,omitempty"`
+	Timeout        int      `json:"timeout,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+// LabeledPrice is one line of an invoice. Amount is in the currency's smallest unit
+// (whole Stars for XTR).
+type LabeledPrice struct {
+	Label  string `json:"label"`
+	Amount int32  `json:"amount"`
+}
+
+// CreateInvoiceLinkParams: leave ProviderToken empty and use currency "XTR" for Telegram Stars.
+// Payload is returned in the pre_checkout_query and successful_payment of the invoice.
+type CreateInvoiceLinkParams struct {
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	Payload       string         `json:"payload"`
+	ProviderToken string         `json:"provider_token,omitempty"`
+	Currency      string         `json:"currency"`
+	Prices        []LabeledPrice `json:"prices"`
+}
+
+// PreCheckoutQuery asks the bot to confirm an order before the user is charged; it must be
+// answered (answerPreCheckoutQuery) within 10 seconds.
+type PreCheckoutQuery struct {
+	ID             string `json:"id"`
+	From           User   `json:"from"`
+	Currency       string `json:"currency"`
+	TotalAmount    int32  `json:"total_amount"`
+	InvoicePayload string `json:"invoice_payload"`
+}
+
+type AnswerPreCheckoutQueryParams struct {
+	PreCheckoutQueryID string `json:"pre_checkout_query_id"`
+	OK                 bool   `json:"ok"`
+	// ErrorMessage is shown to the user when OK is false
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// SuccessfulPayment is the service message sent to the bot once the user has been charged.
+type SuccessfulPayment struct {
+	Currency                string `json:"currency"`
+	TotalAmount             int32  `json:"total_amount"`
+	InvoicePayload          string `json:"invoice_payload"`
+	TelegramPaymentChargeID string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID string `json:"provider_payment_charge_id"`
+}
+
+// APIError is a Bot API response with ok=false.
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	// RetryAfter is set (in seconds) on 429 Too Many Requests.
+	RetryAfter int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
@@ -15,3 +15,11 @@ func Int4OrZero(n pgtype.Int4) int32 {
     }
     return 0
 }
+
+// DateOrEmpty formats a date as YYYY-MM-DD
+func DateOrEmpty(d pgtype.Date) string {
+    if d.Valid {
+        return d.Time.Format("2006-01-02")
+    }
+    return ""
+}
@@ -0,0 +1,18 @@
+-- Candidate search: trigram fallback + case-insensitive skill lookup
+-- search_tsv (GIN) is defined in 001_init.sql; these indexes back the pg_trgm
+-- word-similarity fallback used by ListCandidatesPage.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_candidates_display_name_trgm ON candidates USING GIN (display_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_candidates_desired_role_trgm ON candidates USING GIN (desired_role gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_skills_name_lower ON skills (lower(name));
+
+-- Backfill rows inserted before the trigger existed
+UPDATE candidates
+SET search_tsv =
+  setweight(to_tsvector('simple', coalesce(display_name,'')), 'A') ||
+  setweight(to_tsvector('simple', coalesce(desired_role,'')), 'B') ||
+  setweight(to_tsvector('simple', coalesce(summary,'')), 'C')
+WHERE search_tsv IS NULL;
@@ -0,0 +1,25 @@
+-- Company invitations: owners generate join codes that attach new Telegram
+-- logins to an existing company (deep-linkable via the bot: /start <code>).
+
+CREATE TABLE IF NOT EXISTS company_invites (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  code TEXT NOT NULL UNIQUE,
+  role TEXT NOT NULL DEFAULT 'recruiter', -- owner/admin/recruiter
+  max_uses INT NOT NULL DEFAULT 1,
+  used_count INT NOT NULL DEFAULT 0,
+  created_by BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ,
+  last_used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_invites_company ON company_invites(company_id, created_at DESC);
+
+-- Until now every login auto-created a single-member company; make that member its owner.
+UPDATE hr_users h
+SET role = 'owner', updated_at = now()
+WHERE h.role = 'recruiter'
+  AND h.id = (SELECT min(id) FROM hr_users WHERE company_id = h.company_id)
+  AND NOT EXISTS (SELECT 1 FROM hr_users o WHERE o.company_id = h.company_id AND o.role = 'owner');
@@ -0,0 +1,6 @@
+-- session_version is embedded in every JWT ("sv"); bumping it invalidates all of a
+-- user's access and refresh tokens at once.
+ALTER TABLE hr_users ADD COLUMN IF NOT EXISTS session_version INT NOT NULL DEFAULT 0;
+
+-- Telegram logins look users up by tg_user_id on every sign-in
+CREATE INDEX IF NOT EXISTS idx_hr_users_tg_user_id ON hr_users(tg_user_id);
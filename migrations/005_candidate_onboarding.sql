@@ -0,0 +1,17 @@
+-- Candidate self-onboarding through the Telegram bot.
+
+-- Link self-registered candidates to their Telegram account (NULL for hand-inserted rows).
+ALTER TABLE candidates ADD COLUMN IF NOT EXISTS tg_user_id BIGINT;
+ALTER TABLE candidates ADD COLUMN IF NOT EXISTS contact_consent_at TIMESTAMPTZ;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_candidates_tg_user_id ON candidates(tg_user_id);
+
+-- Per-chat conversation state while a candidate answers the onboarding questions.
+-- Rows are deleted once the candidate record is created or the flow is cancelled.
+CREATE TABLE IF NOT EXISTS candidate_onboarding_sessions (
+  chat_id BIGINT PRIMARY KEY,
+  tg_user_id BIGINT NOT NULL,
+  step TEXT NOT NULL,
+  data JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
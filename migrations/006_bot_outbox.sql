@@ -0,0 +1,21 @@
+-- Durable outbox for bot messages sent outside a conversation (e.g. unlock notifications).
+
+-- Candidates opt in to hearing about contact unlocks from the bot.
+ALTER TABLE candidates ADD COLUMN IF NOT EXISTS notify_on_unlock BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Rows are written in the same transaction as the event that triggers them and delivered
+-- asynchronously; failed sends are retried until max attempts, then marked failed.
+CREATE TABLE IF NOT EXISTS bot_outbox (
+  id BIGSERIAL PRIMARY KEY,
+  chat_id BIGINT NOT NULL,
+  kind TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  status TEXT NOT NULL DEFAULT 'pending', -- pending/sent/failed
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_outbox_due ON bot_outbox(next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,20 @@
+-- Consent-gated contact reveal: candidates in "request" mode approve each company.
+
+ALTER TABLE candidates ADD COLUMN IF NOT EXISTS contact_mode TEXT NOT NULL DEFAULT 'open'; -- open/request
+
+-- One request per company and candidate. Quota is charged (and the unlock inserted) only
+-- when the candidate accepts. A pending request past expires_at counts as expired and may
+-- be renewed by the company; declined requests stay declined.
+CREATE TABLE IF NOT EXISTS contact_requests (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  candidate_id BIGINT NOT NULL REFERENCES candidates(id) ON DELETE CASCADE,
+  hr_user_id BIGINT NOT NULL REFERENCES hr_users(id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'pending', -- pending/accepted/declined
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  decided_at TIMESTAMPTZ,
+  UNIQUE (company_id, candidate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_requests_candidate ON contact_requests(candidate_id);
@@ -0,0 +1,19 @@
+-- Saved candidate searches with periodic new-match digests from the bot.
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  hr_user_id BIGINT NOT NULL REFERENCES hr_users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  filters JSONB NOT NULL DEFAULT '{}'::jsonb, -- domain.CandidateListFilter
+  frequency TEXT NOT NULL DEFAULT 'daily', -- hourly/daily/weekly
+  muted BOOLEAN NOT NULL DEFAULT FALSE,
+  -- candidates updated after last_run_at are new matches for the next digest
+  last_run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  next_run_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_hr_user ON saved_searches(hr_user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_due ON saved_searches(next_run_at) WHERE NOT muted;
@@ -0,0 +1,37 @@
+-- Company hiring pipelines (shortlists) with configurable, ordered stages.
+
+CREATE TABLE IF NOT EXISTS pipelines (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  created_by BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pipelines_company ON pipelines(company_id);
+
+CREATE TABLE IF NOT EXISTS pipeline_stages (
+  id BIGSERIAL PRIMARY KEY,
+  pipeline_id BIGINT NOT NULL REFERENCES pipelines(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  position INT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pipeline_stages_pipeline ON pipeline_stages(pipeline_id, position);
+
+-- A candidate is in at most one stage per pipeline. Stages that still hold candidates
+-- cannot be deleted (the FK is NO ACTION so deleting a whole pipeline still cascades).
+CREATE TABLE IF NOT EXISTS pipeline_candidates (
+  pipeline_id BIGINT NOT NULL REFERENCES pipelines(id) ON DELETE CASCADE,
+  candidate_id BIGINT NOT NULL REFERENCES candidates(id) ON DELETE CASCADE,
+  stage_id BIGINT NOT NULL REFERENCES pipeline_stages(id),
+  added_by BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  moved_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (pipeline_id, candidate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pipeline_candidates_candidate ON pipeline_candidates(candidate_id);
+CREATE INDEX IF NOT EXISTS idx_pipeline_candidates_stage ON pipeline_candidates(stage_id);
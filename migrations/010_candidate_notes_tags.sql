@@ -0,0 +1,26 @@
+-- Private recruiter notes and tags on candidates. Both belong to a company and are never
+-- shown to other companies.
+
+CREATE TABLE IF NOT EXISTS candidate_notes (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  candidate_id BIGINT NOT NULL REFERENCES candidates(id) ON DELETE CASCADE,
+  hr_user_id BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_candidate_notes_company_candidate ON candidate_notes(company_id, candidate_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS candidate_tags (
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  candidate_id BIGINT NOT NULL REFERENCES candidates(id) ON DELETE CASCADE,
+  tag TEXT NOT NULL,
+  created_by BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- tags are case-insensitive per candidate; the first spelling is kept
+CREATE UNIQUE INDEX IF NOT EXISTS idx_candidate_tags_unique ON candidate_tags(company_id, candidate_id, lower(tag));
+CREATE INDEX IF NOT EXISTS idx_candidate_tags_company_tag ON candidate_tags(company_id, lower(tag));
@@ -0,0 +1,15 @@
+-- Finished quota periods. company_quotas only holds the current period; when its
+-- period_end is reached the usage is archived here and the counter starts over.
+
+CREATE TABLE IF NOT EXISTS company_quota_periods (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  period_start DATE NOT NULL,
+  period_end   DATE NOT NULL,
+  unlock_quota_total INT NOT NULL,
+  unlock_quota_used  INT NOT NULL,
+  closed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (company_id, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_quotas_period_end ON company_quotas(period_end);
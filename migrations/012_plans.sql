@@ -0,0 +1,57 @@
+-- Subscription plans. A company's quota for a period is the unlock allowance of the plan in
+-- effect when the period starts; companies without an assignment get the default plan.
+
+CREATE TABLE IF NOT EXISTS plans (
+  id BIGSERIAL PRIMARY KEY,
+  code TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  unlock_quota_monthly INT NOT NULL,
+  seat_limit INT NOT NULL DEFAULT 0, -- active HR users; 0 = unlimited
+  features JSONB NOT NULL DEFAULT '{}'::jsonb, -- {"flag": true}
+  is_default BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO plans (code, name, unlock_quota_monthly, seat_limit, features, is_default) VALUES
+  ('starter',    'Starter',    20,  3,  '{}', true),
+  ('pro',        'Pro',        100, 10, '{"priority_support": true}', false),
+  ('enterprise', 'Enterprise', 500, 0,  '{"priority_support": true, "dedicated_manager": true}', false)
+ON CONFLICT (code) DO NOTHING;
+
+-- A plan applies from effective_from until the company's next assignment
+CREATE TABLE IF NOT EXISTS company_plans (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  plan_id BIGINT NOT NULL REFERENCES plans(id),
+  effective_from DATE NOT NULL,
+  assigned_by BIGINT, -- platform admin tg_user_id
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (company_id, effective_from)
+);
+
+-- Companies that predate plans keep unlimited seats: they go on the grandfathered "legacy"
+-- plan (starter's allowance, no seat limit) instead of falling back to starter. Companies
+-- created later start on the default plan, also when this file is run again.
+INSERT INTO plans (code, name, unlock_quota_monthly, seat_limit) VALUES
+  ('legacy', 'Legacy', 20, 0)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO company_plans (company_id, plan_id, effective_from)
+SELECT c.id, p.id, c.created_at::date
+FROM companies c
+JOIN plans p ON p.code = 'legacy'
+WHERE c.created_at < (SELECT created_at FROM plans WHERE code = 'starter')
+  AND NOT EXISTS (SELECT 1 FROM company_plans cp WHERE cp.company_id = c.id)
+ON CONFLICT (company_id, effective_from) DO NOTHING;
+
+-- company_plan_id resolves the plan in effect for a company on a date
+CREATE OR REPLACE FUNCTION company_plan_id(p_company_id BIGINT, p_on DATE) RETURNS BIGINT AS $$
+  SELECT COALESCE(
+    (SELECT plan_id FROM company_plans
+     WHERE company_id = p_company_id AND effective_from <= p_on
+     ORDER BY effective_from DESC
+     LIMIT 1),
+    (SELECT id FROM plans WHERE is_default ORDER BY id LIMIT 1)
+  )
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,59 @@
+-- Credits. company_quotas.credit_balance is what a company can spend on unlocks; every change
+-- to it is a row in credit_ledger, written in the same transaction under the quota row lock.
+-- Each period grants the plan's allowance (unlock_quota_total); whatever is left of that grant
+-- expires at period end, top-ups do not. Unlocks spend the grant first.
+
+ALTER TABLE company_quotas ADD COLUMN IF NOT EXISTS credit_balance INT NOT NULL DEFAULT 0;
+
+-- Credits an unlock of this candidate costs (senior or highly rated candidates cost more)
+ALTER TABLE candidates ADD COLUMN IF NOT EXISTS unlock_cost INT NOT NULL DEFAULT 1;
+ALTER TABLE candidates DROP CONSTRAINT IF EXISTS candidates_unlock_cost_check;
+ALTER TABLE candidates ADD CONSTRAINT candidates_unlock_cost_check CHECK (unlock_cost > 0);
+
+CREATE TABLE IF NOT EXISTS credit_ledger (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL, -- grant/topup/unlock/refund/expire/adjustment
+  amount INT NOT NULL, -- signed: debits are negative
+  balance_after INT NOT NULL,
+  candidate_id BIGINT REFERENCES candidates(id) ON DELETE SET NULL,
+  hr_user_id BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  ref TEXT, -- external reference (payment id, ...), unique per company and kind
+  note TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_ledger_company ON credit_ledger(company_id, id DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credit_ledger_ref ON credit_ledger(company_id, kind, ref) WHERE ref IS NOT NULL;
+
+-- Append-only: entries are never changed, and only disappear with their company (or when a
+-- referenced candidate/HR user is deleted, which only clears that reference)
+CREATE OR REPLACE FUNCTION credit_ledger_append_only() RETURNS trigger AS $$
+begin
+  if pg_trigger_depth() > 1 then
+    if tg_op = 'DELETE' then
+      return old;
+    end if;
+    return new;
+  end if;
+  raise exception 'credit_ledger is append-only';
+end
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_credit_ledger_append_only ON credit_ledger;
+CREATE TRIGGER trg_credit_ledger_append_only
+BEFORE UPDATE OR DELETE ON credit_ledger
+FOR EACH ROW EXECUTE FUNCTION credit_ledger_append_only();
+
+-- Opening balance: what is left of the current period
+WITH opened AS (
+  UPDATE company_quotas
+  SET credit_balance = GREATEST(unlock_quota_total - unlock_quota_used, 0)
+  WHERE credit_balance = 0
+    AND NOT EXISTS (SELECT 1 FROM credit_ledger l WHERE l.company_id = company_quotas.company_id)
+  RETURNING company_id, credit_balance
+)
+INSERT INTO credit_ledger (company_id, kind, amount, balance_after, note)
+SELECT company_id, 'grant', credit_balance, credit_balance, 'opening balance'
+FROM opened
+WHERE credit_balance > 0;
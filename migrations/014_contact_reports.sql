@@ -0,0 +1,24 @@
+-- Reports of unlocked contacts that turned out to be dead (unknown Telegram username,
+-- disconnected phone, bouncing email). A platform admin reviews each report; a refunded
+-- report gives the unlock's cost back as a 'refund' credit entry (ref 'contact_report:<id>').
+
+-- One report per company and candidate, i.e. per unlock. Rejected reports stay rejected.
+CREATE TABLE IF NOT EXISTS contact_reports (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  candidate_id BIGINT NOT NULL REFERENCES candidates(id) ON DELETE CASCADE,
+  unlock_id BIGINT NOT NULL REFERENCES unlocks(id) ON DELETE CASCADE,
+  hr_user_id BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  reason TEXT NOT NULL, -- telegram_invalid/phone_invalid/email_invalid/other
+  details TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending', -- pending/refunded/rejected
+  refund_amount INT NOT NULL DEFAULT 0,
+  reviewed_by BIGINT, -- platform admin Telegram user ID
+  review_note TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  reviewed_at TIMESTAMPTZ,
+  UNIQUE (company_id, candidate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_reports_status ON contact_reports(status, id);
+CREATE INDEX IF NOT EXISTS idx_contact_reports_candidate ON contact_reports(candidate_id);
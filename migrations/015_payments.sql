@@ -0,0 +1,58 @@
+-- Credit purchases. A company picks a credit pack and pays through a provider (Telegram
+-- Stars/Payments via the bot, or a card processor's hosted checkout). The provider confirms
+-- the payment asynchronously (webhook or successful_payment update); the confirmation adds
+-- the pack's credits as a 'topup' ledger entry with ref 'payment:<id>'.
+
+CREATE TABLE IF NOT EXISTS credit_packs (
+  id BIGSERIAL PRIMARY KEY,
+  code TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  credits INT NOT NULL CHECK (credits > 0),
+  price_minor INT NOT NULL DEFAULT 0 CHECK (price_minor >= 0), -- card price in minor units (fen); 0 = not sold by card
+  currency TEXT NOT NULL DEFAULT 'CNY',
+  price_stars INT NOT NULL DEFAULT 0 CHECK (price_stars >= 0), -- Telegram Stars price; 0 = not sold for Stars
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO credit_packs (code, name, credits, price_minor, price_stars) VALUES
+  ('credits_10', '10 积分', 10, 9900, 500),
+  ('credits_50', '50 积分', 50, 44900, 2250),
+  ('credits_200', '200 积分', 200, 159900, 8000)
+ON CONFLICT (code) DO NOTHING;
+
+-- Credits and price are copied from the pack so later price changes do not affect open payments
+CREATE TABLE IF NOT EXISTS payments (
+  id BIGSERIAL PRIMARY KEY,
+  company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+  hr_user_id BIGINT REFERENCES hr_users(id) ON DELETE SET NULL,
+  pack_id BIGINT NOT NULL REFERENCES credit_packs(id),
+  provider TEXT NOT NULL, -- telegram/card/fake
+  credits INT NOT NULL,
+  amount INT NOT NULL, -- in the currency's minor units (Stars for XTR)
+  currency TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending', -- pending/succeeded/failed
+  provider_ref TEXT, -- the provider's checkout/charge ID
+  checkout_url TEXT NOT NULL DEFAULT '',
+  failure_reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  paid_at TIMESTAMPTZ,
+  UNIQUE (provider, provider_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_company ON payments(company_id, id DESC);
+
+-- Every provider notification that was applied. Providers redeliver webhooks until they get
+-- a 2xx, so (provider, event_id) makes processing idempotent; the row is written in the
+-- same transaction that applies the event.
+CREATE TABLE IF NOT EXISTS payment_events (
+  id BIGSERIAL PRIMARY KEY,
+  provider TEXT NOT NULL,
+  event_id TEXT NOT NULL,
+  payment_id BIGINT REFERENCES payments(id) ON DELETE CASCADE,
+  type TEXT NOT NULL, -- succeeded/failed
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (provider, event_id)
+);